@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	_ "fmt"
 	"log/slog"
@@ -13,6 +14,8 @@ import (
 
 	// Internal
 	"real-state-backend/config"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
 	"real-state-backend/internal/handlers"
 	"real-state-backend/internal/repository"
 	"real-state-backend/internal/services"
@@ -47,21 +50,119 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
 	auditRepo := repository.NewAuditRepository(db)
-	authService := services.NewAuthService(userRepo, sessionRepo, auditRepo, cfg.JWTSecret, cfg.JWTPepper, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.MaxFailedAttempts, cfg.LockoutDuration)
+	// Outbox: los eventos se confirman en la transacción del cambio que los origina
+	txManager := repository.NewTxManager(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	authService := services.NewAuthService(userRepo, sessionRepo, outboxRepo, txManager, cfg.JWTSecret, cfg.JWTPepper, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.MaxFailedAttempts, cfg.LockoutDuration)
 	authHandler := handlers.NewAuthHandler(authService)
 
+	// Contexto de los workers en segundo plano: abarcan todas las agencias
+	bgCtx, cancelWorkers := context.WithCancel(context.WithValue(context.Background(), "organization_id", domain.PlatformScope))
+	defer cancelWorkers()
+
+	// Webhooks: los eventos del outbox se encolan y se despachan en segundo plano
+	webhookService := services.NewWebhookService(bgCtx, repository.NewWebhookRepository(db), 10*time.Second, 15*time.Second, cfg.WebhookAllowPrivate)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	// Notificaciones por correo: cola persistente despachada en segundo plano por SMTP
+	var emailSender ports.EmailSender
+	if cfg.SMTPAddr != "" {
+		sender, err := repository.NewSMTPEmailSender(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, cfg.SMTPTLS, 30*time.Second)
+		if err != nil {
+			slog.Error("Invalid SMTP configuration", "error", err)
+			os.Exit(1)
+		}
+		emailSender = sender
+	} else {
+		slog.Warn("SMTP not configured, notifications stay queued")
+	}
 	propRepo := repository.NewPropertyRepository(db)
-	propService := services.NewPropertyService(propRepo)
-	propHandler := handlers.NewPropertyHandler(propService)
+	notificationService := services.NewNotificationService(bgCtx, repository.NewNotificationRepository(db), userRepo, propRepo, emailSender, 30*time.Second, 30*time.Second)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	savedSearchService := services.NewSavedSearchService(repository.NewSavedSearchRepository(db), propRepo, notificationService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
+	passwordResetHandler := handlers.NewPasswordResetHandler(services.NewPasswordResetService(userRepo,
+		repository.NewPasswordResetRepository(db), sessionRepo, notificationService, authService, outboxRepo, txManager, cfg.PasswordResetURL))
+
+	// Suscriptores en proceso del outbox; los publicados se conservan 7 días
+	services.StartOutboxDispatcher(bgCtx, outboxRepo, map[string][]ports.EventHandler{
+		domain.EventAuditLogged:           {services.AuditLogHandler(auditRepo)},
+		domain.EventPropertyCreated:       {webhookService.EnqueueEvent, savedSearchService.HandlePropertyCreated},
+		domain.EventPropertyUpdated:       {webhookService.EnqueueEvent},
+		domain.EventPropertyStatusChanged: {webhookService.EnqueueEvent, savedSearchService.HandlePropertyStatusChanged},
+		domain.EventLeadCreated:           {webhookService.EnqueueEvent, notificationService.HandleLeadCreated},
+	}, 2*time.Second, 7*24*time.Hour)
+
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	analyticsService := services.NewAnalyticsService(bgCtx, analyticsRepo, txManager, outboxRepo, 1000)
+
+	geoRepo := repository.NewGeoRepository(db)
+	geoService := services.NewGeoService(geoRepo)
+	geoHandler := handlers.NewGeoHandler(geoService)
+
+	zoneBoundaryRepo := repository.NewZoneBoundaryRepository(db)
+	revisionRepo := repository.NewPropertyRevisionRepository(db)
+	zoneBoundaryService := services.NewZoneBoundaryService(zoneBoundaryRepo, propRepo, revisionRepo, txManager)
+	zoneBoundaryHandler := handlers.NewZoneBoundaryHandler(zoneBoundaryService)
+	// Geocodificación: el catálogo local funciona sin conexión y sirve de respaldo del proveedor
+	var geocoder ports.Geocoder = services.NewCatalogGeocoder(geoService, zoneBoundaryService)
+	if cfg.GeocoderProvider == "nominatim" {
+		geocoder = services.NewFallbackGeocoder(repository.NewNominatimGeocoder(cfg.GeocoderURL, cfg.GeocoderUserAgent), geocoder)
+	}
+	offerRepo := repository.NewOfferRepository(db)
+	commissionService := services.NewCommissionService(repository.NewCommissionRepository(db), propRepo, offerRepo, authService)
+	commissionHandler := handlers.NewCommissionHandler(commissionService)
+	propService := services.NewPropertyService(bgCtx, propRepo, revisionRepo, repository.NewPropertyTranslationRepository(db), zoneBoundaryService, geocoder, commissionService, txManager, outboxRepo, 500)
+	propHandler := handlers.NewPropertyHandler(propService, analyticsService, geoService, authService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, propService)
+
+	rateRepo := repository.NewExchangeRateRepository(db)
+	valuationService := services.NewValuationService(propRepo, rateRepo)
+	valuationHandler := handlers.NewValuationHandler(valuationService)
+
+	marketStatsRepo := repository.NewMarketStatsRepository(db)
+	marketStatsService := services.NewMarketStatsService(bgCtx, marketStatsRepo, rateRepo, 15*time.Minute)
+	marketStatsHandler := handlers.NewMarketStatsHandler(marketStatsService)
+
+	mortgageProductRepo := repository.NewMortgageProductRepository(db)
+	mortgageService := services.NewMortgageService(mortgageProductRepo, propRepo, rateRepo)
+	mortgageHandler := handlers.NewMortgageHandler(mortgageService)
+
+	comparisonHandler := handlers.NewComparisonHandler(services.NewComparisonService(propRepo, rateRepo), propService)
+	shareLinkService := services.NewShareLinkService(repository.NewShareLinkRepository(db), propRepo, cfg.ShareLinkSecret)
+	shareLinkHandler := handlers.NewShareLinkHandler(shareLinkService, propService, analyticsService, cfg.PublicCoordinatePrecision)
+
+	publicCatalogService := services.NewPublicCatalogService(propService, cfg.PublicCoordinatePrecision, cfg.PublicCacheTTL)
+	publicCatalogHandler := handlers.NewPublicCatalogHandler(publicCatalogService, cfg.PublicCacheTTL)
+
+	documentService := services.NewPropertyDocumentService(repository.NewPropertyDocumentRepository(db),
+		repository.NewLocalDocumentStorage(cfg.DocumentsDir), propRepo, authService, auditRepo, txManager)
+	documentHandler := handlers.NewPropertyDocumentHandler(documentService)
+
+	offerService := services.NewOfferService(bgCtx, offerRepo, propRepo, revisionRepo, authService, txManager, outboxRepo, time.Minute)
+	offerHandler := handlers.NewOfferHandler(offerService)
+
+	organizationHandler := handlers.NewOrganizationHandler(services.NewOrganizationService(repository.NewOrganizationRepository(db), authService))
 
 	configRepo := repository.NewSecurityConfigRepository(db)
-	configHandler := handlers.NewConfigHandler(configRepo, auditRepo)
+	configHandler := handlers.NewConfigHandler(configRepo, outboxRepo, txManager)
 
 	// 5. Router y Rutas
 	mux := http.NewServeMux()
 	// Endpoints públicos
 	mux.HandleFunc("POST /login", authHandler.Login)
 	mux.HandleFunc("POST /refresh", authHandler.RefreshToken)
+	// Recuperación de contraseña, con un límite estricto por IP
+	passwordRateLimit := middleware.RateLimitMiddleware(5, time.Minute)
+	mux.Handle("POST /password/forgot", passwordRateLimit(http.HandlerFunc(passwordResetHandler.Forgot)))
+	mux.Handle("POST /password/reset", passwordRateLimit(http.HandlerFunc(passwordResetHandler.Reset)))
+	// API pública de solo lectura (sin JWT), con su propio límite de peticiones por IP
+	publicRateLimit := middleware.RateLimitMiddleware(cfg.PublicRateLimit, time.Minute)
+	// El catálogo y los enlaces compartidos son públicos para todas las agencias
+	publicScope := middleware.PlatformScopeMiddleware
+	mux.Handle("GET /public/properties", publicRateLimit(publicScope(http.HandlerFunc(publicCatalogHandler.List))))
+	mux.Handle("GET /public/properties/{id}", publicRateLimit(publicScope(http.HandlerFunc(publicCatalogHandler.Get))))
+	mux.Handle("GET /public/share/{token}", publicRateLimit(publicScope(http.HandlerFunc(shareLinkHandler.Open))))
 
 	// Subrouter para rutas protegidas
 	protectedMux := http.NewServeMux()
@@ -70,6 +171,99 @@ func main() {
 	protectedMux.HandleFunc("GET /properties", propHandler.GetAll)
 	protectedMux.HandleFunc("GET /properties/{id}", propHandler.GetByID)
 	protectedMux.HandleFunc("POST /properties", propHandler.CreateProperty)
+	protectedMux.HandleFunc("GET /properties/compare", comparisonHandler.Compare)
+	protectedMux.Handle("GET /properties/duplicates", middleware.RBACMiddleware(authService, "review_duplicate_properties")(http.HandlerFunc(propHandler.GetDuplicates)))
+	protectedMux.Handle("PATCH /properties/{id}", middleware.RBACMiddleware(authService, "update_property")(http.HandlerFunc(propHandler.UpdateProperty)))
+	protectedMux.Handle("PUT /properties/{id}/status", middleware.RBACMiddleware(authService, "update_property")(http.HandlerFunc(propHandler.ChangeStatus)))
+	protectedMux.Handle("PUT /properties/{id}/coordinates", middleware.RBACMiddleware(authService, "update_property")(http.HandlerFunc(propHandler.SetCoordinates)))
+	rbacUpdateProperty := middleware.RBACMiddleware(authService, "update_property")
+	protectedMux.Handle("GET /properties/{id}/revisions", rbacUpdateProperty(http.HandlerFunc(propHandler.ListRevisions)))
+	protectedMux.Handle("GET /properties/{id}/revisions/diff", rbacUpdateProperty(http.HandlerFunc(propHandler.DiffRevisions)))
+	protectedMux.Handle("GET /properties/{id}/revisions/{revision}", rbacUpdateProperty(http.HandlerFunc(propHandler.GetRevision)))
+	protectedMux.Handle("POST /properties/{id}/revisions/{revision}/revert", rbacUpdateProperty(http.HandlerFunc(propHandler.RevertRevision)))
+	protectedMux.HandleFunc("GET /properties/{id}/translations", propHandler.ListTranslations)
+	protectedMux.Handle("PUT /properties/{id}/translations/{lang}", rbacUpdateProperty(http.HandlerFunc(propHandler.SaveTranslation)))
+	protectedMux.Handle("DELETE /properties/{id}/translations/{lang}", rbacUpdateProperty(http.HandlerFunc(propHandler.DeleteTranslation)))
+	rbacShare := middleware.RBACMiddleware(authService, "share_property")
+	protectedMux.Handle("POST /properties/{id}/share-links", rbacShare(http.HandlerFunc(shareLinkHandler.Create)))
+	protectedMux.Handle("GET /properties/{id}/share-links", rbacShare(http.HandlerFunc(shareLinkHandler.List)))
+	protectedMux.Handle("DELETE /properties/{id}/share-links/{link}", rbacShare(http.HandlerFunc(shareLinkHandler.Revoke)))
+	// Documentos: el acceso (agente responsable o view_property_documents) se valida en el servicio
+	protectedMux.HandleFunc("GET /properties/{id}/documents", documentHandler.List)
+	protectedMux.HandleFunc("POST /properties/{id}/documents", documentHandler.Upload)
+	protectedMux.HandleFunc("GET /properties/{id}/documents/{doc}/versions", documentHandler.ListVersions)
+	protectedMux.HandleFunc("POST /properties/{id}/documents/{doc}/versions", documentHandler.UploadVersion)
+	protectedMux.HandleFunc("GET /properties/{id}/documents/{doc}/download", documentHandler.Download)
+	// Ofertas: las partes (comprador, agente responsable o manage_offers) se validan en el servicio
+	protectedMux.HandleFunc("POST /properties/{id}/offers", offerHandler.Submit)
+	protectedMux.HandleFunc("GET /properties/{id}/offers", offerHandler.List)
+	protectedMux.HandleFunc("GET /properties/{id}/offers/{offer}", offerHandler.Get)
+	protectedMux.HandleFunc("POST /properties/{id}/offers/{offer}/counter", offerHandler.Counter)
+	protectedMux.HandleFunc("POST /properties/{id}/offers/{offer}/accept", offerHandler.Accept)
+	protectedMux.HandleFunc("POST /properties/{id}/offers/{offer}/reject", offerHandler.Reject)
+	protectedMux.HandleFunc("POST /properties/{id}/offers/{offer}/withdraw", offerHandler.Withdraw)
+	protectedMux.HandleFunc("POST /properties/{id}/offers/{offer}/messages", offerHandler.AddMessage)
+	rbacCommissions := middleware.RBACMiddleware(authService, "manage_commissions")
+	protectedMux.Handle("GET /properties/{id}/commissions", rbacCommissions(http.HandlerFunc(commissionHandler.ListSettlements)))
+	protectedMux.Handle("POST /properties/{id}/commissions", rbacCommissions(http.HandlerFunc(commissionHandler.Settle)))
+	protectedMux.Handle("GET /commissions/rules", rbacCommissions(http.HandlerFunc(commissionHandler.ListRules)))
+	protectedMux.Handle("PUT /commissions/rules/{operation}", rbacCommissions(http.HandlerFunc(commissionHandler.UpdateRule)))
+	// El agente puede ver su propio estado de cuenta; el de otros requiere manage_commissions (validado en el servicio)
+	protectedMux.HandleFunc("GET /commissions/agents/{agent}/statement", commissionHandler.Statement)
+	protectedMux.HandleFunc("POST /properties/{id}/favorite", analyticsHandler.AddFavorite)
+	protectedMux.HandleFunc("POST /properties/{id}/inquiries", analyticsHandler.CreateInquiry)
+	// Reportes de analítica requieren permiso 'view_property_analytics'
+	rbacAnalytics := middleware.RBACMiddleware(authService, "view_property_analytics")
+	protectedMux.Handle("GET /properties/{id}/analytics", rbacAnalytics(http.HandlerFunc(analyticsHandler.GetPropertyStats)))
+	protectedMux.Handle("GET /analytics/top-viewed", rbacAnalytics(http.HandlerFunc(analyticsHandler.GetTopViewed)))
+	protectedMux.HandleFunc("POST /valuations/estimate", valuationHandler.Estimate)
+	rbacMarketStats := middleware.RBACMiddleware(authService, "view_market_stats")
+	protectedMux.Handle("GET /market-stats", rbacMarketStats(http.HandlerFunc(marketStatsHandler.GetMonthly)))
+	protectedMux.Handle("POST /market-stats/refresh", rbacMarketStats(http.HandlerFunc(marketStatsHandler.Refresh)))
+	protectedMux.HandleFunc("GET /geo/departments", geoHandler.ListDepartments)
+	protectedMux.HandleFunc("GET /geo/departments/{id}/municipalities", geoHandler.ListMunicipalities)
+	protectedMux.HandleFunc("GET /geo/municipalities/{id}/zones", geoHandler.ListZones)
+	protectedMux.HandleFunc("GET /zones/boundaries", zoneBoundaryHandler.List)
+	rbacZones := middleware.RBACMiddleware(authService, "manage_zone_boundaries")
+	protectedMux.Handle("POST /zones/boundaries", rbacZones(http.HandlerFunc(zoneBoundaryHandler.Import)))
+	protectedMux.Handle("DELETE /zones/boundaries/{id}", rbacZones(http.HandlerFunc(zoneBoundaryHandler.Delete)))
+	// Agencias: la plataforma las administra; cada agencia administra solo sus usuarios
+	rbacOrganizations := middleware.RBACMiddleware(authService, "manage_organizations")
+	protectedMux.Handle("GET /organizations", rbacOrganizations(http.HandlerFunc(organizationHandler.List)))
+	protectedMux.Handle("POST /organizations", rbacOrganizations(http.HandlerFunc(organizationHandler.Create)))
+	protectedMux.Handle("PATCH /organizations/{id}", rbacOrganizations(http.HandlerFunc(organizationHandler.Update)))
+	protectedMux.Handle("POST /organizations/{id}/users", rbacOrganizations(http.HandlerFunc(organizationHandler.CreateAgencyAdmin)))
+	rbacAgencyUsers := middleware.RBACMiddleware(authService, "manage_agency_users")
+	protectedMux.Handle("GET /organization/users", rbacAgencyUsers(http.HandlerFunc(organizationHandler.ListUsers)))
+	protectedMux.Handle("POST /organization/users", rbacAgencyUsers(http.HandlerFunc(organizationHandler.CreateUser)))
+	protectedMux.Handle("PUT /organization/users/{id}/roles", rbacAgencyUsers(http.HandlerFunc(organizationHandler.SetUserRoles)))
+	protectedMux.Handle("GET /organization/roles", rbacAgencyUsers(http.HandlerFunc(organizationHandler.ListRoles)))
+	protectedMux.Handle("POST /organization/roles", rbacAgencyUsers(http.HandlerFunc(organizationHandler.CreateRole)))
+	rbacWebhooks := middleware.RBACMiddleware(authService, "manage_webhooks")
+	protectedMux.Handle("GET /webhooks", rbacWebhooks(http.HandlerFunc(webhookHandler.List)))
+	protectedMux.Handle("POST /webhooks", rbacWebhooks(http.HandlerFunc(webhookHandler.Create)))
+	protectedMux.Handle("GET /webhooks/{id}", rbacWebhooks(http.HandlerFunc(webhookHandler.Get)))
+	protectedMux.Handle("PATCH /webhooks/{id}", rbacWebhooks(http.HandlerFunc(webhookHandler.Update)))
+	protectedMux.Handle("DELETE /webhooks/{id}", rbacWebhooks(http.HandlerFunc(webhookHandler.Delete)))
+	protectedMux.Handle("POST /webhooks/{id}/ping", rbacWebhooks(http.HandlerFunc(webhookHandler.Ping)))
+	protectedMux.Handle("GET /webhooks/{id}/deliveries", rbacWebhooks(http.HandlerFunc(webhookHandler.ListDeliveries)))
+	protectedMux.Handle("GET /webhooks/{id}/deliveries/{delivery}", rbacWebhooks(http.HandlerFunc(webhookHandler.GetDelivery)))
+	protectedMux.Handle("POST /webhooks/{id}/deliveries/{delivery}/redeliver", rbacWebhooks(http.HandlerFunc(webhookHandler.Redeliver)))
+	// Notificaciones: cada usuario gestiona las suyas
+	protectedMux.HandleFunc("GET /notifications", notificationHandler.List)
+	protectedMux.HandleFunc("GET /notifications/preferences", notificationHandler.GetPreferences)
+	protectedMux.HandleFunc("PUT /notifications/preferences", notificationHandler.UpdatePreferences)
+	protectedMux.HandleFunc("POST /notifications/test", notificationHandler.SendTest)
+	// Búsquedas guardadas: cada usuario gestiona las suyas
+	protectedMux.HandleFunc("GET /saved-searches", savedSearchHandler.List)
+	protectedMux.HandleFunc("POST /saved-searches", savedSearchHandler.Create)
+	protectedMux.HandleFunc("DELETE /saved-searches/{id}", savedSearchHandler.Delete)
+	protectedMux.HandleFunc("POST /mortgage/calculate", mortgageHandler.Calculate)
+	protectedMux.HandleFunc("GET /mortgage/products", mortgageHandler.ListProducts)
+	rbacMortgage := middleware.RBACMiddleware(authService, "manage_mortgage_products")
+	protectedMux.Handle("POST /mortgage/products", rbacMortgage(http.HandlerFunc(mortgageHandler.CreateProduct)))
+	protectedMux.Handle("PUT /mortgage/products/{id}", rbacMortgage(http.HandlerFunc(mortgageHandler.UpdateProduct)))
+	protectedMux.Handle("DELETE /mortgage/products/{id}", rbacMortgage(http.HandlerFunc(mortgageHandler.DeleteProduct)))
 	// Manejar /config por método. PUT requiere permiso 'manage_security_config'
 	protectedMux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -98,6 +292,23 @@ func main() {
 	mux.Handle("/properties", protectedHandler)
 	mux.Handle("/properties/", protectedHandler)
 	mux.Handle("POST /properties", createPropertyHandler) // Sobrescribir con RBAC
+	mux.Handle("/analytics/", protectedHandler)
+	mux.Handle("/commissions/", protectedHandler)
+	mux.Handle("/organizations", protectedHandler)
+	mux.Handle("/organizations/", protectedHandler)
+	mux.Handle("/organization/", protectedHandler)
+	mux.Handle("/webhooks", protectedHandler)
+	mux.Handle("/webhooks/", protectedHandler)
+	mux.Handle("/notifications", protectedHandler)
+	mux.Handle("/notifications/", protectedHandler)
+	mux.Handle("/saved-searches", protectedHandler)
+	mux.Handle("/saved-searches/", protectedHandler)
+	mux.Handle("/valuations/", protectedHandler)
+	mux.Handle("/market-stats", protectedHandler)
+	mux.Handle("/market-stats/", protectedHandler)
+	mux.Handle("/mortgage/", protectedHandler)
+	mux.Handle("/geo/", protectedHandler)
+	mux.Handle("/zones/", protectedHandler)
 
 	// Rutas para /config (protegidas). GET/PUT se despachan dentro de protectedMux
 	mux.Handle("/config", protectedHandler)
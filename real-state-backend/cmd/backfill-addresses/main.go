@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log/slog"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"real-state-backend/config"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/repository"
+	"real-state-backend/internal/services"
+)
+
+// backfill-addresses recalcula la dirección normalizada de las propiedades existentes
+// con domain.NormalizeAddress. Se ejecuta después de aplicar la migración 000006 o
+// de cambiar las reglas de normalización.
+func main() {
+	batchSize := flag.Int("batch", 500, "propiedades por lote")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	db, err := sql.Open("postgres", cfg.DBUrl)
+	if err != nil {
+		slog.Error("Failed to connect to DB", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// El backfill abarca las propiedades de todas las agencias
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), "organization_id", domain.PlatformScope))
+	defer cancel()
+	propService := services.NewPropertyService(ctx, repository.NewPropertyRepository(db), nil, nil, nil, nil, nil,
+		repository.NewTxManager(db), nil, 0)
+
+	updated, err := propService.BackfillAddresses(ctx, *batchSize)
+	if err != nil {
+		slog.Error("Address backfill failed", "updated", updated, "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Address backfill completed", "updated", updated)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log/slog"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"real-state-backend/config"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/repository"
+	"real-state-backend/internal/services"
+)
+
+// backfill-zones reasigna la zona/colonia de las propiedades existentes según
+// los polígonos cargados. Se ejecuta después de importar o corregir polígonos.
+func main() {
+	batchSize := flag.Int("batch", 500, "propiedades por lote")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	db, err := sql.Open("postgres", cfg.DBUrl)
+	if err != nil {
+		slog.Error("Failed to connect to DB", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	propRepo := repository.NewPropertyRepository(db)
+	zoneBoundaryService := services.NewZoneBoundaryService(repository.NewZoneBoundaryRepository(db), propRepo, repository.NewPropertyRevisionRepository(db), repository.NewTxManager(db))
+
+	// El backfill abarca las propiedades de todas las agencias
+	ctx := context.WithValue(context.Background(), "organization_id", domain.PlatformScope)
+	updated, err := zoneBoundaryService.Backfill(ctx, *batchSize)
+	if err != nil {
+		slog.Error("Zone backfill failed", "updated", updated, "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Zone backfill completed", "updated", updated)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Caja de la Ciudad de Guatemala donde caen los resultados simulados
+const (
+	minLat, maxLat = 14.55, 14.66
+	minLng, maxLng = -90.58, -90.47
+)
+
+// nominatim-stub es una API compatible con /search de Nominatim para probar la
+// geocodificación sin conexión (GEOCODER_PROVIDER=nominatim GEOCODER_URL=http://localhost:8088).
+// Cada dirección recibe siempre las mismas coordenadas; -notfound simula direcciones
+// no encontradas y -fail responde 503 para probar el respaldo del catálogo.
+func main() {
+	addr := flag.String("addr", ":8088", "dirección de escucha")
+	notFound := flag.String("notfound", "sin resultado", "texto que, si aparece en la consulta, no retorna resultados")
+	rank := flag.Int("rank", 26, "place_rank de los resultados (30 = edificio, 26 = calle, 16 = municipio)")
+	fail := flag.Bool("fail", false, "responder 503 a todas las consultas")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	http.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		slog.Info("Geocode query", "q", q, "user_agent", r.UserAgent())
+		if *fail {
+			http.Error(w, "simulated failure", http.StatusServiceUnavailable)
+			return
+		}
+
+		places := make([]map[string]interface{}, 0, 1)
+		if q != "" && (*notFound == "" || !strings.Contains(strings.ToLower(q), strings.ToLower(*notFound))) {
+			lat, lng := coordinates(q)
+			places = append(places, map[string]interface{}{
+				"lat":          strconv.FormatFloat(lat, 'f', 7, 64),
+				"lon":          strconv.FormatFloat(lng, 'f', 7, 64),
+				"place_rank":   *rank,
+				"display_name": q,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(places)
+	})
+
+	slog.Info("Nominatim stub listening", "addr", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		slog.Error("Stub failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// coordinates deriva un punto estable dentro de la caja a partir del texto consultado
+func coordinates(q string) (float64, float64) {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(strings.TrimSpace(q))))
+	sum := h.Sum64()
+	latFrac := float64(sum&0xffffffff) / float64(1<<32)
+	lngFrac := float64(sum>>32) / float64(1<<32)
+	return minLat + latFrac*(maxLat-minLat), minLng + lngFrac*(maxLng-minLng)
+}
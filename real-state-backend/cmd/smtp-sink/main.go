@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// smtp-sink es un servidor SMTP local que acepta todos los correos para probar las
+// notificaciones: registra cada mensaje y, con -dir, lo guarda como .eml.
+// -fail rechaza los mensajes con un error temporal para probar los reintentos.
+func main() {
+	addr := flag.String("addr", ":1025", "dirección de escucha")
+	dir := flag.String("dir", "", "directorio donde guardar los mensajes (.eml)")
+	fail := flag.Bool("fail", false, "rechazar todos los mensajes con 451")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0o750); err != nil {
+			slog.Error("Cannot create output directory", "error", err)
+			os.Exit(1)
+		}
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		slog.Error("Sink failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("SMTP sink listening", "addr", *addr, "dir", *dir, "fail", *fail)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			slog.Warn("Accept failed", "error", err)
+			continue
+		}
+		go serve(conn, *dir, *fail)
+	}
+}
+
+// serve atiende una sesión SMTP básica (sin STARTTLS ni AUTH)
+func serve(conn net.Conn, dir string, fail bool) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Minute))
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 smtp-sink ready")
+
+	var from string
+	var to []string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 smtp-sink")
+		case "MAIL":
+			from, to = strings.TrimPrefix(arg, "FROM:"), nil
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			body, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if fail {
+				tp.PrintfLine("451 Simulated temporary failure")
+				continue
+			}
+			subject := header(body, "Subject")
+			slog.Info("Message received", "from", from, "to", to, "subject", subject, "size", len(body))
+			if dir != "" {
+				name := filepath.Join(dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+				if err := os.WriteFile(name, body, 0o640); err != nil {
+					slog.Warn("Cannot save message", "error", err)
+				}
+			}
+			tp.PrintfLine("250 OK")
+		case "RSET":
+			from, to = "", nil
+			tp.PrintfLine("250 OK")
+		case "NOOP":
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// header retorna un encabezado del mensaje decodificado (RFC 2047)
+func header(body []byte, name string) string {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(body)))
+	h, _ := r.ReadMIMEHeader()
+	value, err := new(mime.WordDecoder).DecodeHeader(h.Get(name))
+	if err != nil {
+		return h.Get(name)
+	}
+	return value
+}
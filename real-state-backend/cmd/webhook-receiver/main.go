@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// webhook-receiver es un receptor local para probar las suscripciones: valida la
+// firma de cada envío y lo registra. -fail responde 500 para probar los reintentos.
+// La API debe iniciarse con WEBHOOK_ALLOW_PRIVATE=true para entregar en localhost.
+func main() {
+	addr := flag.String("addr", ":9090", "dirección de escucha")
+	secret := flag.String("secret", "", "secreto de la suscripción (whsec_...)")
+	fail := flag.Bool("fail", false, "responder 500 a todos los envíos")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	http.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		valid := *secret == "" || domain.VerifyWebhookSignature(*secret, r.Header.Get(domain.WebhookHeaderSignature), body, time.Now(), 5*time.Minute)
+		slog.Info("Webhook received",
+			"event", r.Header.Get(domain.WebhookHeaderEvent),
+			"event_id", r.Header.Get(domain.WebhookHeaderEventID),
+			"delivery", r.Header.Get(domain.WebhookHeaderDelivery),
+			"signature_valid", valid,
+			"body", string(body))
+		switch {
+		case !valid:
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+		case *fail:
+			http.Error(w, "simulated failure", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	slog.Info("Webhook receiver listening", "addr", *addr, "verify_signature", *secret != "")
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		slog.Error("Receiver failed", "error", err)
+		os.Exit(1)
+	}
+}
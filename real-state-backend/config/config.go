@@ -2,11 +2,14 @@ package config
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"os"
 	"real-state-backend/internal/repository"
+	"strconv"
 	"time"
 )
 
@@ -20,6 +23,25 @@ type Config struct {
 	RefreshTokenTTL   time.Duration
 	MaxFailedAttempts int
 	LockoutDuration   time.Duration
+	GeocoderProvider  string // offline (catálogo) o nominatim
+	GeocoderURL       string // API compatible con Nominatim
+	GeocoderUserAgent string
+	ShareLinkSecret   string // Firma de los enlaces públicos de propiedades
+	// Catálogo público (sin autenticación)
+	PublicCoordinatePrecision int           // Decimales de lat/lng expuestos (3 ≈ 110 m)
+	PublicCacheTTL            time.Duration // Vigencia de las respuestas en caché
+	PublicRateLimit           int           // Peticiones por minuto por IP
+	DocumentsDir              string        // Directorio de la bóveda de documentos
+	// Webhooks: permite destinos en direcciones privadas o locales (ej. cmd/webhook-receiver)
+	WebhookAllowPrivate bool
+	// Correo saliente (para pruebas locales SMTP_ADDR=localhost:1025 con cmd/smtp-sink)
+	SMTPAddr     string // host:puerto; vacío desactiva el envío (los correos quedan en cola)
+	SMTPUsername string // Vacío = sin autenticación
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTLS      string // starttls, tls o none
+	// PasswordResetURL es la página del frontend que recibe el token de recuperación
+	PasswordResetURL string
 }
 
 func LoadConfig() *Config {
@@ -33,15 +55,30 @@ func LoadConfig() *Config {
 	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", user, password, host, port, dbName)
 
 	return &Config{
-		ServerPort:        getEnv("SERVER_PORT", "8080"),
-		DBUrl:             dbURL,
-		Environment:       getEnv("GO_ENV", "development"),
-		JWTSecret:         getEnv("JWT_SECRET", "my-secret-key"),
-		JWTPepper:         getEnv("JWT_PEPPER", "my-pepper-key"),
-		AccessTokenTTL:    15 * time.Minute,
-		RefreshTokenTTL:   7 * 24 * time.Hour,
-		MaxFailedAttempts: 5,
-		LockoutDuration:   15 * time.Minute,
+		ServerPort:                getEnv("SERVER_PORT", "8080"),
+		DBUrl:                     dbURL,
+		Environment:               getEnv("GO_ENV", "development"),
+		JWTSecret:                 getEnv("JWT_SECRET", "my-secret-key"),
+		JWTPepper:                 getEnv("JWT_PEPPER", "my-pepper-key"),
+		AccessTokenTTL:            15 * time.Minute,
+		RefreshTokenTTL:           7 * 24 * time.Hour,
+		MaxFailedAttempts:         5,
+		LockoutDuration:           15 * time.Minute,
+		GeocoderProvider:          getEnv("GEOCODER_PROVIDER", "offline"),
+		GeocoderURL:               getEnv("GEOCODER_URL", "https://nominatim.openstreetmap.org"),
+		GeocoderUserAgent:         getEnv("GEOCODER_USER_AGENT", "real-state-backend/1.0"),
+		ShareLinkSecret:           shareLinkSecret(),
+		PublicCoordinatePrecision: getEnvInt("PUBLIC_COORDINATE_PRECISION", 3),
+		PublicCacheTTL:            time.Duration(getEnvInt("PUBLIC_CACHE_TTL_SECONDS", 60)) * time.Second,
+		PublicRateLimit:           getEnvInt("PUBLIC_RATE_LIMIT_PER_MINUTE", 60),
+		DocumentsDir:              getEnv("DOCUMENTS_DIR", "./data/documents"),
+		WebhookAllowPrivate:       getEnvBool("WEBHOOK_ALLOW_PRIVATE", false),
+		SMTPAddr:                  getEnv("SMTP_ADDR", ""),
+		SMTPUsername:              getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:              getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                  getEnv("SMTP_FROM", "Real State <no-reply@realstate.local>"),
+		SMTPTLS:                   getEnv("SMTP_TLS", "starttls"),
+		PasswordResetURL:          getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
 	}
 }
 
@@ -83,9 +120,41 @@ func LoadConfigFromDB(cfg *Config, db *sql.DB) *Config {
 	slog.Info("Security config loaded from database", "access_ttl", cfg.AccessTokenTTL, "refresh_ttl", cfg.RefreshTokenTTL)
 	return cfg
 }
+
+// shareLinkSecret lee SHARE_LINK_SECRET. Sin ella se genera una clave aleatoria: una
+// clave fija conocida permitiría falsificar enlaces, y con la aleatoria los enlaces
+// emitidos dejan de abrir al reiniciar.
+func shareLinkSecret() string {
+	if secret := getEnv("SHARE_LINK_SECRET", ""); secret != "" {
+		return secret
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	slog.Warn("SHARE_LINK_SECRET not set, using a random key; share links stop working on restart")
+	return hex.EncodeToString(b)
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
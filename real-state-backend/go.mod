@@ -6,9 +6,8 @@ toolchain go1.24.12
 
 require github.com/lib/pq v1.10.9
 
-require github.com/golang-jwt/jwt/v5 v5.3.0
-
 require (
-	github.com/google/uuid v1.6.0 // indirect
-	golang.org/x/crypto v0.47.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.0
+	github.com/google/uuid v1.6.0
+	golang.org/x/crypto v0.47.0
 )
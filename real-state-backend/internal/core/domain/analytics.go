@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Tipos de eventos registrados sobre una propiedad
+const (
+	PropertyEventView     = "view"
+	PropertyEventFavorite = "favorite"
+	PropertyEventInquiry  = "inquiry"
+)
+
+// PropertyEvent representa una interacción de un usuario con un inmueble
+// (vista de detalle, favorito o consulta).
+type PropertyEvent struct {
+	ID         int64                  `json:"id"`
+	PropertyID int64                  `json:"property_id"`
+	EventType  string                 `json:"event_type"`
+	UserID     *string                `json:"user_id,omitempty"`
+	DeviceID   string                 `json:"device_id,omitempty"`
+	ViewerKey  string                 `json:"-"` // Clave de deduplicación (usuario/dispositivo)
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// PropertyDailyStats agrega los eventos de un inmueble en un día
+type PropertyDailyStats struct {
+	Date      string `json:"date"` // YYYY-MM-DD
+	Views     int    `json:"views"`
+	Favorites int    `json:"favorites"`
+	Inquiries int    `json:"inquiries"`
+}
+
+// PropertyViewRank es una fila del reporte de propiedades más vistas
+type PropertyViewRank struct {
+	PropertyID int64  `json:"property_id"`
+	Title      string `json:"title"`
+	City       string `json:"city"`
+	Type       string `json:"type"`
+	Views      int    `json:"views"`
+	Favorites  int    `json:"favorites"`
+	Inquiries  int    `json:"inquiries"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// Unidades de área aceptadas. El valor canónico se almacena en m².
+const (
+	AreaUnitSqM     = "m2"
+	AreaUnitVaras2  = "v2" // Vara cuadrada guatemalteca
+	AreaUnitManzana = "mz" // 10,000 varas cuadradas
+)
+
+// Factores de conversión a m² (vara guatemalteca = 0.835905 m)
+const (
+	SqMPerVara2   = 0.698737
+	SqMPerManzana = 6987.37
+)
+
+// ErrInvalidAreaUnit indica una unidad de área desconocida
+var ErrInvalidAreaUnit = errors.New("invalid area unit")
+
+// PropertyAreas expone el área y el precio por unidad en todas las unidades
+type PropertyAreas struct {
+	SqM             float64  `json:"sqm"`
+	Varas2          float64  `json:"varas2"`
+	Manzanas        float64  `json:"manzanas"`
+	PricePerSqM     *float64 `json:"price_per_sqm,omitempty"`
+	PricePerVara2   *float64 `json:"price_per_vara2,omitempty"`
+	PricePerManzana *float64 `json:"price_per_manzana,omitempty"`
+}
+
+// IsValidAreaUnit indica si la unidad es soportada (vacío equivale a m²)
+func IsValidAreaUnit(unit string) bool {
+	switch unit {
+	case "", AreaUnitSqM, AreaUnitVaras2, AreaUnitManzana:
+		return true
+	}
+	return false
+}
+
+// AreaToSqM convierte un área expresada en la unidad indicada a m²
+func AreaToSqM(value float64, unit string) (float64, error) {
+	switch unit {
+	case "", AreaUnitSqM:
+		return value, nil
+	case AreaUnitVaras2:
+		return value * SqMPerVara2, nil
+	case AreaUnitManzana:
+		return value * SqMPerManzana, nil
+	}
+	return 0, ErrInvalidAreaUnit
+}
+
+// NewPropertyAreas calcula el área en todas las unidades y el precio por unidad
+func NewPropertyAreas(areaSqM, price float64) *PropertyAreas {
+	if areaSqM <= 0 {
+		return nil
+	}
+	areas := &PropertyAreas{
+		SqM:      roundTo(areaSqM, 2),
+		Varas2:   roundTo(areaSqM/SqMPerVara2, 2),
+		Manzanas: roundTo(areaSqM/SqMPerManzana, 4),
+	}
+	if price > 0 {
+		perSqM := roundTo(price/areaSqM, 2)
+		perVara2 := roundTo(price/(areaSqM/SqMPerVara2), 2)
+		perManzana := roundTo(price/(areaSqM/SqMPerManzana), 2)
+		areas.PricePerSqM, areas.PricePerVara2, areas.PricePerManzana = &perSqM, &perVara2, &perManzana
+	}
+	return areas
+}
+
+func roundTo(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tipos de operación para las reglas de comisión
+const (
+	OperationSale = "sale"
+	OperationRent = "rent"
+)
+
+// Participantes y roles del libro de comisiones
+const (
+	CommissionParticipantAgent  = "agent"
+	CommissionParticipantAgency = "agency"
+	CommissionRoleListing       = "listing"
+	CommissionRoleSelling       = "selling"
+)
+
+var (
+	ErrCommissionRuleNotFound = errors.New("commission rule not found")
+	ErrInvalidCommissionRule  = errors.New("invalid commission rule")
+	// ErrCommissionAlreadySettled indica que el cierre ya tiene su liquidación
+	ErrCommissionAlreadySettled = errors.New("commission already settled")
+	// ErrPropertyNotClosed indica que la propiedad no está vendida ni rentada
+	ErrPropertyNotClosed      = errors.New("property is not sold or rented")
+	ErrCommissionAccessDenied = errors.New("commission access denied")
+)
+
+// OperationForStatus retorna la operación que corresponde al estado de cierre ("" si no aplica)
+func OperationForStatus(status string) string {
+	switch status {
+	case PropertyStatusSold:
+		return OperationSale
+	case PropertyStatusRented:
+		return OperationRent
+	}
+	return ""
+}
+
+// CommissionRule define cómo se calcula y reparte la comisión de un tipo de operación.
+// La comisión es RatePercent del precio; la agencia retiene AgencySharePercent y el
+// resto se reparte entre agente captador (ListingSplitPercent) y agente vendedor.
+// El IVA se calcula sobre cada parte.
+type CommissionRule struct {
+	OperationType       string    `json:"operation_type"`
+	RatePercent         float64   `json:"rate_percent"`
+	ListingSplitPercent float64   `json:"listing_split_percent"`
+	AgencySharePercent  float64   `json:"agency_share_percent"`
+	IVAPercent          float64   `json:"iva_percent"`
+	UpdatedBy           *string   `json:"updated_by,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Validate verifica que los porcentajes estén en rango
+func (r *CommissionRule) Validate() error {
+	if r.OperationType != OperationSale && r.OperationType != OperationRent {
+		return fmt.Errorf("%w: operation_type must be sale or rent", ErrInvalidCommissionRule)
+	}
+	if r.RatePercent <= 0 || r.RatePercent > 200 {
+		return fmt.Errorf("%w: rate_percent must be between 0 and 200", ErrInvalidCommissionRule)
+	}
+	for _, p := range []float64{r.ListingSplitPercent, r.AgencySharePercent, r.IVAPercent} {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("%w: percentages must be between 0 and 100", ErrInvalidCommissionRule)
+		}
+	}
+	return nil
+}
+
+// CommissionSettlement es la liquidación de un cierre con la regla aplicada
+type CommissionSettlement struct {
+	ID               int64             `json:"id"`
+	PropertyID       int64             `json:"property_id"`
+	OperationType    string            `json:"operation_type"`
+	ClosedAt         time.Time         `json:"closed_at"`
+	Price            float64           `json:"price"`
+	Currency         string            `json:"currency"`
+	OfferID          *int64            `json:"offer_id,omitempty"`
+	ListingAgentID   *string           `json:"listing_agent_id,omitempty"`
+	SellingAgentID   *string           `json:"selling_agent_id,omitempty"`
+	Rule             CommissionRule    `json:"rule"`
+	CommissionAmount float64           `json:"commission_amount"` // Antes de IVA
+	IVAAmount        float64           `json:"iva_amount"`
+	Entries          []CommissionEntry `json:"entries"`
+	CreatedBy        *string           `json:"created_by,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// CommissionEntry es la parte de un participante en una liquidación
+type CommissionEntry struct {
+	ID           int64     `json:"id"`
+	SettlementID int64     `json:"settlement_id"`
+	PropertyID   int64     `json:"property_id"`
+	Participant  string    `json:"participant"`
+	AgentID      *string   `json:"agent_id,omitempty"`
+	Roles        []string  `json:"roles"`
+	BaseAmount   float64   `json:"base_amount"`
+	IVAAmount    float64   `json:"iva_amount"`
+	TotalAmount  float64   `json:"total_amount"`
+	Currency     string    `json:"currency"`
+	EntryDate    time.Time `json:"entry_date"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CommissionTotal suma las comisiones de una moneda
+type CommissionTotal struct {
+	Currency    string  `json:"currency"`
+	BaseAmount  float64 `json:"base_amount"`
+	IVAAmount   float64 `json:"iva_amount"`
+	TotalAmount float64 `json:"total_amount"`
+	Entries     int     `json:"entries"`
+}
+
+// AgentStatement es el estado de cuenta de un agente en un rango de fechas
+type AgentStatement struct {
+	AgentID string            `json:"agent_id"`
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Entries []CommissionEntry `json:"entries"`
+	Totals  []CommissionTotal `json:"totals"`
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+)
+
+// Límites de propiedades en una comparación
+const (
+	MinCompareProperties = 2
+	MaxCompareProperties = 4
+)
+
+// ErrPropertyNotComparable indica propiedades inexistentes o no visibles para compradores
+var ErrPropertyNotComparable = errors.New("property not available for comparison")
+
+// IsListedStatus indica si la propiedad es visible para compradores (publicada o reservada)
+func IsListedStatus(status string) bool {
+	return status == PropertyStatusPublished || status == PropertyStatusReserved
+}
+
+// ComparedAttribute alinea un atributo de todas las propiedades (mismo orden que Properties)
+type ComparedAttribute struct {
+	Attribute string `json:"attribute"`
+	Values    []any  `json:"values"`
+	Same      bool   `json:"same"`
+}
+
+// PropertyAmenityDiff son las amenidades que solo tiene o que le faltan a una propiedad
+type PropertyAmenityDiff struct {
+	PropertyID int64    `json:"property_id"`
+	Unique     []string `json:"unique"`
+	Missing    []string `json:"missing"`
+}
+
+// AmenityDiff resume las amenidades compartidas y las diferencias por propiedad
+type AmenityDiff struct {
+	Common     []string              `json:"common"`
+	ByProperty []PropertyAmenityDiff `json:"by_property"`
+}
+
+// PropertyDistance es la distancia en línea recta entre dos propiedades con coordenadas
+type PropertyDistance struct {
+	FromID int64   `json:"from_id"`
+	ToID   int64   `json:"to_id"`
+	Meters float64 `json:"meters"`
+}
+
+// PropertyComparison es el resultado de comparar propiedades lado a lado
+type PropertyComparison struct {
+	Currency   string              `json:"currency"` // Moneda común de precios y precio por m²
+	Properties []Property          `json:"properties"`
+	Attributes []ComparedAttribute `json:"attributes"`
+	Amenities  AmenityDiff         `json:"amenities"`
+	Distances  []PropertyDistance  `json:"distances"`
+}
+
+// NewAmenityDiff calcula las amenidades comunes y las diferencias de cada propiedad
+func NewAmenityDiff(properties []Property) AmenityDiff {
+	var all []string
+	for _, p := range properties {
+		for _, a := range p.Amenities {
+			if !slices.Contains(all, a) {
+				all = append(all, a)
+			}
+		}
+	}
+	slices.Sort(all)
+
+	diff := AmenityDiff{Common: []string{}, ByProperty: make([]PropertyAmenityDiff, len(properties))}
+	for _, a := range all {
+		shared := true
+		for _, p := range properties {
+			shared = shared && slices.Contains(p.Amenities, a)
+		}
+		if shared {
+			diff.Common = append(diff.Common, a)
+		}
+	}
+	for i, p := range properties {
+		d := PropertyAmenityDiff{PropertyID: p.ID, Unique: []string{}, Missing: []string{}}
+		for _, a := range all {
+			if slices.Contains(diff.Common, a) {
+				continue
+			}
+			if slices.Contains(p.Amenities, a) {
+				d.Unique = append(d.Unique, a)
+			} else {
+				d.Missing = append(d.Missing, a)
+			}
+		}
+		diff.ByProperty[i] = d
+	}
+	return diff
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Tipos de documento de una propiedad
+const (
+	DocumentTypeDeed      = "deed"                  // Escritura
+	DocumentTypeCadastral = "cadastral_certificate" // Certificación del Registro / catastro
+	DocumentTypeMandate   = "mandate"               // Mandato o contrato de corretaje firmado
+	DocumentTypeOther     = "other"
+)
+
+// MaxDocumentSize limita el tamaño de cada archivo subido
+const MaxDocumentSize int64 = 20 << 20
+
+// DocumentTypes son los tipos aceptados
+var DocumentTypes = []string{DocumentTypeDeed, DocumentTypeCadastral, DocumentTypeMandate, DocumentTypeOther}
+
+// DocumentContentTypes son los formatos aceptados (detectados por contenido, no por extensión)
+var DocumentContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+var (
+	ErrDocumentNotFound     = errors.New("document not found")
+	ErrDocumentAccessDenied = errors.New("document access denied")
+	ErrInvalidDocument      = errors.New("invalid document")
+)
+
+// PropertyDocument agrupa las versiones de un documento de la propiedad
+type PropertyDocument struct {
+	ID            int64            `json:"id"`
+	PropertyID    int64            `json:"property_id"`
+	DocumentType  string           `json:"document_type"`
+	Title         string           `json:"title"`
+	CreatedBy     *string          `json:"created_by,omitempty"`
+	LatestVersion *DocumentVersion `json:"latest_version,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// DocumentVersion es un archivo subido; las versiones anteriores se conservan
+type DocumentVersion struct {
+	ID          int64     `json:"id"`
+	DocumentID  int64     `json:"document_id"`
+	Version     int       `json:"version"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Checksum    string    `json:"checksum_sha256"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  *string   `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DocumentUpload es un archivo por subir; DocumentID = 0 crea un documento nuevo
+type DocumentUpload struct {
+	PropertyID   int64
+	DocumentID   int64
+	DocumentType string
+	Title        string
+	FileName     string
+	Content      io.Reader
+}
+
+// RequestOrigin identifica al cliente para la auditoría
+type RequestOrigin struct {
+	IPAddress string
+	UserAgent string
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "strings"
+
+// Department es un departamento de Guatemala
+type Department struct {
+	ID   int64  `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Municipality es un municipio dentro de un departamento
+type Municipality struct {
+	ID           int64    `json:"id"`
+	DepartmentID int64    `json:"department_id"`
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Lat          *float64 `json:"lat,omitempty"` // Centro del casco urbano
+	Lng          *float64 `json:"lng,omitempty"`
+}
+
+// Zone es una zona municipal (ej. "Zona 10" de la Ciudad de Guatemala)
+type Zone struct {
+	ID             int64  `json:"id"`
+	MunicipalityID int64  `json:"municipality_id"`
+	Number         int    `json:"number"`
+	Name           string `json:"name"`
+}
+
+// GeoLocation es la ubicación resuelta contra el catálogo. Un municipio que no está
+// en el catálogo (que es parcial) queda sin resolver: se conserva el texto sin IDs.
+type GeoLocation struct {
+	Department   Department
+	Municipality Municipality
+	Zone         *Zone
+	Unresolved   bool
+}
+
+// nameReplacer elimina tildes y puntuación de nombres geográficos
+var nameReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	".", " ", ",", " ", "-", " ",
+)
+
+// NormalizeName produce la forma canónica de un nombre para compararlo con el catálogo
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(nameReplacer.Replace(strings.ToLower(name))), " ")
+}
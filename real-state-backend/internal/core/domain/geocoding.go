@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Fuentes de las coordenadas de una propiedad
+const (
+	GeocodeSourceManual    = "manual"
+	GeocodeSourceNominatim = "nominatim"
+	GeocodeSourceCatalog   = "catalog"
+)
+
+// ErrAddressNotFound indica que el geocodificador no encontró la dirección
+var ErrAddressNotFound = errors.New("address not found")
+
+// ErrCoordinatesAlreadySet indica que un resultado automático no se guardó porque la
+// propiedad recibió coordenadas mientras tanto (ej. manualmente)
+var ErrCoordinatesAlreadySet = errors.New("property already has coordinates")
+
+// GeocodeQuery es la dirección a geocodificar junto con la ubicación ya normalizada
+type GeocodeQuery struct {
+	Address        string
+	Neighbourhood  string
+	Zone           string
+	City           string
+	Department     string
+	MunicipalityID *int64
+	ZoneID         *int64
+}
+
+// NewGeocodeQuery arma la consulta a partir de los datos de la propiedad
+func NewGeocodeQuery(p *Property) GeocodeQuery {
+	return GeocodeQuery{
+		Address:        p.Address,
+		Neighbourhood:  p.Neighbourhood,
+		Zone:           p.Zone,
+		City:           p.City,
+		Department:     p.Department,
+		MunicipalityID: p.MunicipalityID,
+		ZoneID:         p.ZoneID,
+	}
+}
+
+// Text retorna la dirección completa en texto libre ("5a Avenida 10-20, Zona 10, Guatemala, Guatemala")
+func (q GeocodeQuery) Text() string {
+	parts := make([]string, 0, 5)
+	for _, part := range []string{q.Address, q.Neighbourhood, q.Zone, q.City, q.Department} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GeocodeResult son las coordenadas encontradas y qué tan precisas se consideran (0 a 1)
+type GeocodeResult struct {
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source"`
+}
+
+// SetGeocode asigna las coordenadas y registra su origen y confianza
+func (p *Property) SetGeocode(r *GeocodeResult, at time.Time) {
+	p.Lat, p.Lng = r.Lat, r.Lng
+	p.GeocodeConfidence = &r.Confidence
+	p.GeocodeSource = r.Source
+	p.GeocodedAt = &at
+}
+
+// ClearGeocode elimina las coordenadas para que se vuelvan a geocodificar
+func (p *Property) ClearGeocode() {
+	p.Lat, p.Lng = 0, 0
+	p.GeocodeConfidence, p.GeocodeSource, p.GeocodedAt = nil, "", nil
+}
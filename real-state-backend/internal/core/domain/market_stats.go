@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// MarketStatsFilter define los filtros de las estadísticas de mercado
+type MarketStatsFilter struct {
+	City string
+	Type string
+	From time.Time // Primer mes incluido
+	To   time.Time // Último mes incluido
+}
+
+// MarketStat agrega el inventario de una agencia en una ciudad y tipo de inmueble en un mes
+type MarketStat struct {
+	Month             string   `json:"month"` // YYYY-MM
+	OrganizationID    string   `json:"organization_id"`
+	City              string   `json:"city"`
+	Type              string   `json:"type"`
+	Currency          string   `json:"currency"`
+	InventoryCount    int      `json:"inventory_count"`
+	NewListings       int      `json:"new_listings"`
+	ClosedCount       int      `json:"closed_count"`
+	MedianPrice       *float64 `json:"median_price"`
+	MedianPricePerSqM *float64 `json:"median_price_per_sqm"`
+	AvgDaysOnMarket   *float64 `json:"avg_days_on_market"`
+	PriceReductions   int      `json:"price_reductions"`
+	// UnconvertedCount es el inventario en monedas sin tipo de cambio, excluido de las medianas
+	UnconvertedCount int `json:"unconverted_count"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMortgageProductNotFound indica que el producto hipotecario no existe
+var ErrMortgageProductNotFound = errors.New("mortgage product not found")
+
+// MortgageProduct es un producto hipotecario bancario editable por administradores
+type MortgageProduct struct {
+	ID                 int64     `json:"id"`
+	BankName           string    `json:"bank_name"`
+	ProductName        string    `json:"product_name"`
+	Currency           string    `json:"currency"`
+	AnnualRate         float64   `json:"annual_rate"` // 0.085 = 8.5%
+	MaxTermYears       int       `json:"max_term_years"`
+	MinDownPaymentPct  float64   `json:"min_down_payment_pct"`
+	MaxPaymentToIncome float64   `json:"max_payment_to_income"`
+	Active             bool      `json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// MortgageInput son los parámetros de un cálculo hipotecario
+type MortgageInput struct {
+	PropertyID    int64
+	Price         float64
+	Currency      string
+	DownPayment   float64
+	AnnualRate    *float64
+	TermYears     int
+	ProductID     int64
+	MonthlyIncome float64
+	MonthlyDebts  float64
+}
+
+// AmortizationRow es una cuota del plan de pagos
+type AmortizationRow struct {
+	Number    int     `json:"number"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"`
+}
+
+// AffordabilityCheck compara la cuota contra el ingreso declarado
+type AffordabilityCheck struct {
+	MonthlyIncome      float64 `json:"monthly_income"`
+	MonthlyDebts       float64 `json:"monthly_debts"`
+	PaymentToIncome    float64 `json:"payment_to_income"`
+	MaxPaymentToIncome float64 `json:"max_payment_to_income"`
+	Affordable         bool    `json:"affordable"`
+	MaxAffordableLoan  float64 `json:"max_affordable_loan"`
+}
+
+// MortgageQuote es el resultado del cálculo hipotecario
+type MortgageQuote struct {
+	PropertyID     int64               `json:"property_id,omitempty"`
+	Product        *MortgageProduct    `json:"product,omitempty"`
+	Currency       string              `json:"currency"`
+	Price          float64             `json:"price"`
+	DownPayment    float64             `json:"down_payment"`
+	Principal      float64             `json:"principal"`
+	AnnualRate     float64             `json:"annual_rate"`
+	TermYears      int                 `json:"term_years"`
+	MonthlyPayment float64             `json:"monthly_payment"`
+	TotalInterest  float64             `json:"total_interest"`
+	TotalPaid      float64             `json:"total_paid"`
+	Affordability  *AffordabilityCheck `json:"affordability,omitempty"`
+	Schedule       []AmortizationRow   `json:"schedule"`
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Tipos de notificación (cada uno tiene su plantilla por idioma)
+const (
+	NotificationLeadAlert        = "lead_alert"         // Consulta nueva sobre una propiedad del agente
+	NotificationSavedSearchMatch = "saved_search_match" // Propiedad nueva que cumple una búsqueda guardada
+	NotificationPasswordReset    = "password_reset"     // Enlace de recuperación de contraseña; ignora las preferencias
+	NotificationTest             = "test"               // Prueba de la configuración de correo; ignora las preferencias
+)
+
+// NotificationKinds son los tipos que el usuario puede silenciar
+var NotificationKinds = []string{NotificationLeadAlert, NotificationSavedSearchMatch}
+
+// Estados de una notificación en la cola de envío
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // Agotó los reintentos
+)
+
+// Reintentos con espera exponencial: 1m, 2m, 4m... hasta MaxNotificationRetryDelay
+const (
+	MaxNotificationAttempts    = 8
+	notificationBaseRetryDelay = time.Minute
+	MaxNotificationRetryDelay  = 6 * time.Hour
+)
+
+// ErrNotificationRecipientMissing indica que el usuario no tiene correo registrado
+var ErrNotificationRecipientMissing = errors.New("user has no email address")
+
+// ErrNotificationExpired indica que el contenido que se arma al enviar ya no es válido
+// (p. ej. un enlace de recuperación usado o reemplazado); la notificación se descarta
+var ErrNotificationExpired = errors.New("notification content is no longer valid")
+
+// EmailMessage es un correo listo para enviar; el remitente lo define el adaptador
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Notification es un correo en la cola persistente de envío
+type Notification struct {
+	ID            int64      `json:"id"`
+	UserID        string     `json:"user_id"`
+	Kind          string     `json:"kind"`
+	Language      string     `json:"language"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	TextBody      string     `json:"-"`
+	HTMLBody      string     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	DedupeKey     string     `json:"-"` // Evita duplicar la notificación de un mismo evento
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Message arma el correo de la notificación
+func (n *Notification) Message() *EmailMessage {
+	return &EmailMessage{To: n.Recipient, Subject: n.Subject, Text: n.TextBody, HTML: n.HTMLBody}
+}
+
+// NotificationRetryDelay es la espera antes del siguiente intento tras el intento número attempt (desde 1)
+func NotificationRetryDelay(attempt int) time.Duration {
+	return exponentialDelay(notificationBaseRetryDelay, MaxNotificationRetryDelay, attempt)
+}
+
+// RecordAttempt aplica el resultado de un envío: errMsg vacío es éxito; si no, se
+// reintenta con espera exponencial o falla al agotar MaxNotificationAttempts
+func (n *Notification) RecordAttempt(errMsg string, at time.Time) {
+	n.Attempts++
+	n.LastError, n.UpdatedAt = errMsg, at
+	switch {
+	case errMsg == "":
+		n.Status, n.NextAttemptAt, n.SentAt = NotificationSent, nil, &at
+	case n.Attempts >= MaxNotificationAttempts:
+		n.Status, n.NextAttemptAt = NotificationFailed, nil
+	default:
+		next := at.Add(NotificationRetryDelay(n.Attempts))
+		n.Status, n.NextAttemptAt = NotificationPending, &next
+	}
+}
+
+// Discard marca la notificación como fallida sin enviarla ni consumir un intento
+func (n *Notification) Discard(reason string, at time.Time) {
+	n.LastError, n.UpdatedAt = reason, at
+	n.Status, n.NextAttemptAt = NotificationFailed, nil
+}
+
+// NotificationPreferences son las preferencias de correo de un usuario
+type NotificationPreferences struct {
+	UserID       string    `json:"-"`
+	Language     string    `json:"language"`
+	EmailEnabled bool      `json:"email_enabled"`
+	MutedKinds   []string  `json:"muted_kinds"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NotificationPreferencesChanges son los campos modificables (nil = sin cambio)
+type NotificationPreferencesChanges struct {
+	Language     *string
+	EmailEnabled *bool
+	MutedKinds   []string
+}
+
+// DefaultNotificationPreferences aplica a los usuarios que no han guardado preferencias
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{UserID: userID, Language: DefaultLanguage, EmailEnabled: true, MutedKinds: []string{}}
+}
+
+// Allows indica si el usuario recibe por correo las notificaciones del tipo
+func (p *NotificationPreferences) Allows(kind string) bool {
+	if kind == NotificationTest || kind == NotificationPasswordReset {
+		return true
+	}
+	return p.EmailEnabled && !slices.Contains(p.MutedKinds, kind)
+}
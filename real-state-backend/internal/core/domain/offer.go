@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Estados de una oferta
+const (
+	OfferStatusSubmitted = "submitted"
+	OfferStatusCountered = "countered"
+	OfferStatusAccepted  = "accepted"
+	OfferStatusRejected  = "rejected"
+	OfferStatusWithdrawn = "withdrawn"
+	OfferStatusExpired   = "expired"
+)
+
+// Partes de la negociación
+const (
+	OfferPartyBuyer  = "buyer"
+	OfferPartySeller = "seller" // Agente responsable o usuario con manage_offers
+	OfferPartySystem = "system"
+)
+
+// OfferEventMessage es una entrada del hilo que no cambia el estado
+const OfferEventMessage = "message"
+
+// MaxOfferTTL limita la vigencia de una oferta o contraoferta
+const MaxOfferTTL = 30 * 24 * time.Hour
+
+var (
+	ErrOfferNotFound     = errors.New("offer not found")
+	ErrOfferAccessDenied = errors.New("offer access denied")
+	ErrInvalidOffer      = errors.New("invalid offer")
+	// ErrInvalidOfferTransition indica una acción no permitida en el estado actual o por esa parte
+	ErrInvalidOfferTransition = errors.New("invalid offer transition")
+	// ErrOfferExpired indica que la oferta venció antes de la acción
+	ErrOfferExpired = errors.New("offer expired")
+	// ErrPropertyNotAvailable indica que la propiedad no está publicada (no admite ofertas ni reservas)
+	ErrPropertyNotAvailable = errors.New("property is not available for offers")
+)
+
+// offerStatusTransitions define los cambios de estado permitidos
+var offerStatusTransitions = map[string][]string{
+	OfferStatusSubmitted: {OfferStatusCountered, OfferStatusAccepted, OfferStatusRejected, OfferStatusWithdrawn, OfferStatusExpired},
+	OfferStatusCountered: {OfferStatusCountered, OfferStatusAccepted, OfferStatusRejected, OfferStatusWithdrawn, OfferStatusExpired},
+	OfferStatusAccepted:  {},
+	OfferStatusRejected:  {},
+	OfferStatusWithdrawn: {},
+	OfferStatusExpired:   {},
+}
+
+// OfferTerms son las condiciones propuestas en una oferta o contraoferta
+type OfferTerms struct {
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	Conditions string    `json:"conditions"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Offer es una oferta sobre una propiedad; sus términos son los de la última propuesta
+type Offer struct {
+	ID         int64   `json:"id"`
+	PropertyID int64   `json:"property_id"`
+	BuyerID    *string `json:"buyer_id,omitempty"`
+	OfferTerms
+	Status string `json:"status"`
+	// AwaitingParty es la parte que debe responder (vacío si la oferta está cerrada)
+	AwaitingParty string       `json:"awaiting_party,omitempty"`
+	Events        []OfferEvent `json:"events,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// OfferEvent es una entrada del hilo de negociación (cambio de estado o mensaje)
+type OfferEvent struct {
+	ID        int64       `json:"id"`
+	OfferID   int64       `json:"offer_id"`
+	AuthorID  *string     `json:"author_id,omitempty"`
+	Party     string      `json:"party"`
+	Kind      string      `json:"kind"` // Estado resultante o "message"
+	Terms     *OfferTerms `json:"terms,omitempty"`
+	Message   string      `json:"message,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// IsOpen indica si la oferta sigue en negociación
+func (o *Offer) IsOpen() bool {
+	return o.Status == OfferStatusSubmitted || o.Status == OfferStatusCountered
+}
+
+// IsExpired indica si una oferta abierta ya pasó su vencimiento
+func (o *Offer) IsExpired(now time.Time) bool {
+	return o.IsOpen() && !now.Before(o.ExpiresAt)
+}
+
+// CanTransitionTo indica si la parte puede llevar la oferta al estado indicado.
+// Solo la parte que espera respuesta acepta, rechaza o contraoferta; el comprador
+// puede retirar su oferta mientras siga abierta.
+func (o *Offer) CanTransitionTo(status, party string) bool {
+	if !slices.Contains(offerStatusTransitions[o.Status], status) {
+		return false
+	}
+	switch status {
+	case OfferStatusWithdrawn:
+		return party == OfferPartyBuyer
+	case OfferStatusExpired:
+		return party == OfferPartySystem
+	default:
+		return party == o.AwaitingParty
+	}
+}
+
+// Counterparty retorna la otra parte de la negociación
+func Counterparty(party string) string {
+	if party == OfferPartyBuyer {
+		return OfferPartySeller
+	}
+	return OfferPartyBuyer
+}
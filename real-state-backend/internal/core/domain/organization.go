@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultOrganizationID es la agencia a la que se migraron los datos existentes
+const DefaultOrganizationID = "00000000-0000-0000-0000-000000000001"
+
+// PlatformScope es el valor de organization_id en el contexto de los workers y de las
+// rutas públicas: las consultas abarcan todas las agencias. Sin organización en el
+// contexto las consultas acotadas no retornan filas.
+const PlatformScope = "*"
+
+// RoleAgencyAdmin es el rol global que se asigna al primer usuario de una agencia
+const RoleAgencyAdmin = "agency_admin"
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationSlugTaken = errors.New("organization slug already taken")
+	// ErrOrganizationInactive indica que la agencia fue desactivada por la plataforma
+	ErrOrganizationInactive = errors.New("organization is inactive")
+	// ErrOrganizationProtected: la agencia por defecto (la de la plataforma) no se desactiva
+	ErrOrganizationProtected = errors.New("default organization cannot be deactivated")
+	// ErrTenantRequired indica una escritura sin organización en el contexto
+	ErrTenantRequired = errors.New("organization required")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrUserTaken      = errors.New("username or email already taken")
+	// ErrRoleNotAssignable indica un rol de otra agencia o con permisos de plataforma
+	ErrRoleNotAssignable = errors.New("role not assignable")
+	ErrRoleNameTaken     = errors.New("role name already taken")
+	// ErrPermissionNotAssignable indica un permiso inexistente o de plataforma en un rol de agencia
+	ErrPermissionNotAssignable = errors.New("permission not assignable")
+)
+
+// Organization es una agencia: sus usuarios solo ven y modifican los datos de la agencia
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationChanges son los campos modificables de una agencia (nil = sin cambio)
+type OrganizationChanges struct {
+	Name   *string
+	Active *bool
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Eventos de dominio registrados en el outbox
+const (
+	EventPropertyCreated       = "property.created"
+	EventPropertyUpdated       = "property.updated"
+	EventPropertyStatusChanged = "property.status_changed"
+	EventLeadCreated           = "lead.created"
+	// EventAuditLogged se persiste en audit_logs al despacharse
+	EventAuditLogged = "audit.logged"
+)
+
+// Tipos de agregado de los eventos
+const (
+	AggregateProperty = "property"
+	AggregateUser     = "user"
+)
+
+// Reintentos de los suscriptores: 5s, 10s, 20s... hasta MaxOutboxRetryDelay (sin límite de intentos)
+const (
+	outboxBaseRetryDelay = 5 * time.Second
+	MaxOutboxRetryDelay  = 15 * time.Minute
+)
+
+// OutboxEvent es un evento confirmado en la misma transacción que el cambio que lo
+// origina. Se entrega al menos una vez: los suscriptores deben ser idempotentes (EventID).
+type OutboxEvent struct {
+	ID             int64           `json:"id"`
+	EventID        string          `json:"event_id"`
+	Type           string          `json:"type"`
+	OrganizationID *string         `json:"organization_id,omitempty"` // Por defecto la de la petición
+	AggregateType  string          `json:"aggregate_type"`
+	AggregateID    string          `json:"aggregate_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	PublishedAt    *time.Time      `json:"published_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// NewOutboxEvent serializa data como payload del evento
+func NewOutboxEvent(eventType, aggregateType, aggregateID string, data interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{Type: eventType, AggregateType: aggregateType, AggregateID: aggregateID, Payload: payload}, nil
+}
+
+// OutboxRetryDelay es la espera antes de reintentar tras el intento número attempt (desde 1)
+func OutboxRetryDelay(attempt int) time.Duration {
+	return exponentialDelay(outboxBaseRetryDelay, MaxOutboxRetryDelay, attempt)
+}
+
+// exponentialDelay duplica base por cada intento previo, con tope max
+func exponentialDelay(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// PasswordResetTTL es la vigencia de un enlace de recuperación de contraseña
+const PasswordResetTTL = time.Hour
+
+// ErrPasswordResetInvalid indica un token inexistente, usado o vencido
+var ErrPasswordResetInvalid = errors.New("password reset token is invalid or expired")
+
+// PasswordReset es un enlace de recuperación. El token se emite al enviar el correo y
+// solo se guarda su hash.
+type PasswordReset struct {
+	ID        int64
+	UserID    string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -1,24 +1,322 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Property representa un inmueble en el sistema.
 // Se usan etiquetas JSON para la respuesta de la API.
 type Property struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Currency    string    `json:"currency"` // USD, GTQ
-	Address     string    `json:"address"`
-	City        string    `json:"city"`
-	Type        string    `json:"type"` // Casa, Apartamento, Terreno
-	Bedrooms    int       `json:"bedrooms,omitempty"`
-	Bathrooms   int       `json:"bathrooms,omitempty"`
-	AreaSqM     float64   `json:"area_sqm"`
-	Lat         float64   `json:"lat,omitempty"` // Para mapas en la app móvil
-	Lng         float64   `json:"lng,omitempty"`
-	MainImage   string    `json:"main_image"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                int64          `json:"id"`
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	SourceLanguage    string         `json:"source_language"`    // Idioma en que se capturó la propiedad
+	Language          string         `json:"language,omitempty"` // Idioma del título/descripción retornados
+	Price             float64        `json:"price"`
+	Currency          string         `json:"currency"` // USD, GTQ
+	Address           string         `json:"address"`
+	City              string         `json:"city"` // Nombre canónico del municipio
+	Department        string         `json:"department,omitempty"`
+	Zone              string         `json:"zone,omitempty"`
+	DepartmentID      *int64         `json:"department_id,omitempty"`
+	MunicipalityID    *int64         `json:"municipality_id,omitempty"`
+	ZoneID            *int64         `json:"zone_id,omitempty"`
+	ZoneBoundaryID    *int64         `json:"zone_boundary_id,omitempty"` // Polígono que contiene las coordenadas
+	Neighbourhood     string         `json:"neighbourhood,omitempty"`
+	Type              string         `json:"type"` // Casa, Apartamento, Terreno
+	Bedrooms          int            `json:"bedrooms,omitempty"`
+	Bathrooms         int            `json:"bathrooms,omitempty"`
+	Amenities         []string       `json:"amenities,omitempty"` // Códigos de domain.PropertyAmenities
+	AreaSqM           float64        `json:"area_sqm"`
+	Lat               float64        `json:"lat,omitempty"` // Para mapas en la app móvil
+	Lng               float64        `json:"lng,omitempty"`
+	GeocodeConfidence *float64       `json:"geocode_confidence,omitempty"` // 0 a 1; 1 = ingresadas manualmente
+	GeocodeSource     string         `json:"geocode_source,omitempty"`     // manual, nominatim, catalog
+	GeocodedAt        *time.Time     `json:"geocoded_at,omitempty"`
+	MainImage         string         `json:"main_image"`
+	Areas             *PropertyAreas `json:"areas,omitempty"`          // Calculado: área en m², v² y manzanas
+	AgentID           *string        `json:"agent_id,omitempty"`       // Agente responsable (quien la registró)
+	RegistryFinca     string         `json:"registry_finca,omitempty"` // Registro General de la Propiedad (solo usuarios internos)
+	RegistryFolio     string         `json:"registry_folio,omitempty"`
+	RegistryLibro     string         `json:"registry_libro,omitempty"`
+	RegistryIUSI      string         `json:"registry_iusi,omitempty"` // Matrícula fiscal / número catastral
+	Status            string         `json:"status"`                  // draft, published, reserved, sold, rented, withdrawn
+	ClosedAt          *time.Time     `json:"closed_at,omitempty"`     // Salida del mercado
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// PropertyFilter define los filtros del listado de propiedades (área siempre en m²)
+type PropertyFilter struct {
+	Type           string
+	DepartmentID   int64
+	MunicipalityID int64
+	ZoneID         int64
+	ZoneName       string // Nombre de zona o colonia (polígono o catálogo)
+	MinAreaSqM     float64
+	MaxAreaSqM     float64
+	Statuses       []string // Estados incluidos; vacío = todos
+	RegistryFinca  string   // Búsqueda registral (solo usuarios internos)
+	RegistryFolio  string
+	RegistryLibro  string
+	RegistryIUSI   string
+	Fields         []string       // Campos a proyectar (?fields=); vacío = todos
+	Sort           []PropertySort // Orden del listado (?sort=); vacío = más recientes primero
+}
+
+// PropertySort es un criterio de orden del listado
+type PropertySort struct {
+	Field string
+	Desc  bool
+}
+
+// PropertyFields son los campos (nombres JSON) que se pueden pedir con ?fields=
+var PropertyFields = []string{
+	"id", "title", "description", "source_language", "language", "price", "currency",
+	"address", "city", "department", "zone", "department_id", "municipality_id", "zone_id",
+	"zone_boundary_id", "neighbourhood", "type", "bedrooms", "bathrooms", "amenities", "area_sqm",
+	"lat", "lng", "geocode_confidence", "geocode_source", "geocoded_at", "main_image",
+	"areas", "agent_id", "registry_finca", "registry_folio", "registry_libro", "registry_iusi", "status", "closed_at", "created_at", "updated_at",
+}
+
+// PropertySortFields son los campos por los que se puede ordenar el listado
+var PropertySortFields = []string{"price", "created_at", "updated_at", "area_sqm", "bedrooms", "bathrooms", "title"}
+
+// SetLocation asigna la ubicación normalizada del catálogo
+func (p *Property) SetLocation(loc *GeoLocation) {
+	p.Department = loc.Department.Name
+	p.City = loc.Municipality.Name
+	p.DepartmentID = catalogID(loc.Department.ID)
+	p.MunicipalityID = catalogID(loc.Municipality.ID)
+	p.Zone, p.ZoneID = "", nil
+	if loc.Zone != nil {
+		p.Zone = loc.Zone.Name
+		p.ZoneID = catalogID(loc.Zone.ID)
+	}
+}
+
+// catalogID retorna nil para las entradas que no están en el catálogo
+func catalogID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// SetZoneBoundary asigna el polígono que contiene a la propiedad; si está
+// asociado a una zona del catálogo también se actualiza la zona
+func (p *Property) SetZoneBoundary(b *ZoneBoundary) {
+	if b == nil {
+		p.ZoneBoundaryID, p.Neighbourhood = nil, ""
+		return
+	}
+	p.ZoneBoundaryID = &b.ID
+	p.Neighbourhood = b.Name
+	if b.GeoZoneID != nil {
+		p.ZoneID = b.GeoZoneID
+	}
+}
+
+// ComputeAreas calcula el área y el precio por unidad en m², v² y manzanas
+func (p *Property) ComputeAreas() {
+	p.Areas = NewPropertyAreas(p.AreaSqM, p.Price)
+}
+
+// Estados de publicación de una propiedad
+const (
+	PropertyStatusDraft     = "draft"
+	PropertyStatusPublished = "published"
+	PropertyStatusReserved  = "reserved"
+	PropertyStatusSold      = "sold"
+	PropertyStatusRented    = "rented"
+	PropertyStatusWithdrawn = "withdrawn"
+)
+
+// propertyStatusTransitions define los cambios de estado permitidos
+var propertyStatusTransitions = map[string][]string{
+	PropertyStatusDraft:     {PropertyStatusPublished, PropertyStatusWithdrawn},
+	PropertyStatusPublished: {PropertyStatusReserved, PropertyStatusSold, PropertyStatusRented, PropertyStatusWithdrawn},
+	PropertyStatusReserved:  {PropertyStatusPublished, PropertyStatusSold, PropertyStatusRented, PropertyStatusWithdrawn},
+	PropertyStatusWithdrawn: {PropertyStatusPublished},
+	PropertyStatusSold:      {},
+	PropertyStatusRented:    {PropertyStatusPublished}, // Vuelve al mercado al terminar el contrato
+}
+
+// Tipos de propiedad
+const (
+	PropertyTypeHouse     = "Casa"
+	PropertyTypeApartment = "Apartamento"
+	PropertyTypeLand      = "Terreno"
+	PropertyTypeOffice    = "Oficina"
+)
+
+// PropertyAmenities es el catálogo de amenidades que se pueden asignar a una propiedad
+var PropertyAmenities = []string{
+	"parking", "garden", "pool", "security", "gym", "terrace", "furnished",
+	"elevator", "laundry", "storage", "pet_friendly", "air_conditioning",
+}
+
+// MaxRooms limita habitaciones y baños para detectar errores de captura
+const MaxRooms = 50
+
+// Motivos de FieldViolation; coinciden con los códigos de las respuestas de validación
+const (
+	ViolationOutOfRange    = "out_of_range"
+	ViolationConflict      = "conflict"
+	ViolationInvalidFormat = "invalid_format"
+	ViolationInvalidChoice = "invalid_choice"
+)
+
+// FieldViolation es una regla de negocio incumplida por un campo de la propiedad
+type FieldViolation struct {
+	Field   string
+	Reason  string
+	Message string
+}
+
+// RoomViolations aplica las reglas de habitaciones y baños según el tipo: terrenos
+// sin habitaciones ni baños y oficinas sin habitaciones
+func RoomViolations(propertyType string, bedrooms, bathrooms int) []FieldViolation {
+	var violations []FieldViolation
+	rangeMessage := fmt.Sprintf("must be between 0 and %d", MaxRooms)
+	if bedrooms < 0 || bedrooms > MaxRooms {
+		violations = append(violations, FieldViolation{"bedrooms", ViolationOutOfRange, "bedrooms " + rangeMessage})
+	} else if bedrooms != 0 && (propertyType == PropertyTypeLand || propertyType == PropertyTypeOffice) {
+		violations = append(violations, FieldViolation{"bedrooms", ViolationConflict, "bedrooms must be 0 for " + propertyType})
+	}
+	if bathrooms < 0 || bathrooms > MaxRooms {
+		violations = append(violations, FieldViolation{"bathrooms", ViolationOutOfRange, "bathrooms " + rangeMessage})
+	} else if bathrooms != 0 && propertyType == PropertyTypeLand {
+		violations = append(violations, FieldViolation{"bathrooms", ViolationConflict, "bathrooms must be 0 for " + propertyType})
+	}
+	return violations
+}
+
+// AmenityViolations rechaza amenidades fuera de PropertyAmenities o repetidas
+func AmenityViolations(amenities []string) []FieldViolation {
+	var violations []FieldViolation
+	for i, a := range amenities {
+		if !slices.Contains(PropertyAmenities, a) {
+			violations = append(violations, FieldViolation{"amenities", ViolationInvalidChoice, "invalid amenity " + a})
+		} else if slices.Contains(amenities[:i], a) {
+			violations = append(violations, FieldViolation{"amenities", ViolationConflict, "repeated amenity " + a})
+		}
+	}
+	return violations
+}
+
+// ErrInvalidProperty indica datos incoherentes con el tipo de propiedad
+var ErrInvalidProperty = errors.New("invalid property")
+
+// ErrPropertyNotFound indica que la propiedad no existe o no pertenece a la organización
+var ErrPropertyNotFound = errors.New("property not found")
+
+// ErrPropertyStatusChanged indica que el estado cambió desde que se validó la transición
+var ErrPropertyStatusChanged = errors.New("property status changed")
+
+// Validate aplica las reglas que dependen del tipo de propiedad
+func (p *Property) Validate() error {
+	switch p.Type {
+	case PropertyTypeHouse, PropertyTypeApartment, PropertyTypeLand, PropertyTypeOffice:
+	default:
+		return fmt.Errorf("%w: invalid type %q", ErrInvalidProperty, p.Type)
+	}
+	if violations := RoomViolations(p.Type, p.Bedrooms, p.Bathrooms); len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidProperty, violations[0].Message)
+	}
+	if err := p.validateRegistry(); err != nil {
+		return err
+	}
+	if violations := AmenityViolations(p.Amenities); len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidProperty, violations[0].Message)
+	}
+	switch p.Type {
+	case PropertyTypeLand:
+		if p.AreaSqM == 0 {
+			return fmt.Errorf("%w: area is required for Terreno", ErrInvalidProperty)
+		}
+	}
+	return nil
+}
+
+// IsValidPropertyStatus indica si el estado existe
+func IsValidPropertyStatus(status string) bool {
+	_, ok := propertyStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionTo indica si la propiedad puede pasar al estado indicado
+func (p *Property) CanTransitionTo(status string) bool {
+	return slices.Contains(propertyStatusTransitions[p.Status], status)
+}
+
+// IsClosedStatus indica si el estado implica que el inmueble salió del mercado
+func IsClosedStatus(status string) bool {
+	return status == PropertyStatusSold || status == PropertyStatusRented || status == PropertyStatusWithdrawn
+}
+
+// DuplicateCandidate es una propiedad existente que probablemente es el mismo inmueble
+type DuplicateCandidate struct {
+	Property       Property `json:"property"`
+	Score          float64  `json:"score"` // 0..1
+	Reasons        []string `json:"reasons"`
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+}
+
+// DuplicatePair es un par de propiedades del catálogo que parecen duplicadas
+type DuplicatePair struct {
+	First          Property `json:"first"`
+	Second         Property `json:"second"`
+	Score          float64  `json:"score"`
+	Reasons        []string `json:"reasons"`
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+}
+
+// DuplicatePropertyError se retorna al crear una propiedad con posibles duplicados
+type DuplicatePropertyError struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *DuplicatePropertyError) Error() string {
+	return "possible duplicate property"
+}
+
+// addressReplacer elimina tildes y signos de puntuación de una dirección
+var addressReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	".", " ", ",", " ", "#", " ", "-", " ", "/", " ", "(", " ", ")", " ",
+)
+
+// addressAbbreviations unifica abreviaturas comunes en direcciones guatemaltecas
+var addressAbbreviations = map[string]string{
+	"avenida":       "av",
+	"ave":           "av",
+	"avda":          "av",
+	"calle":         "c",
+	"cll":           "c",
+	"zona":          "z",
+	"numero":        "no",
+	"num":           "no",
+	"kilometro":     "km",
+	"carretera":     "carr",
+	"colonia":       "col",
+	"residencial":   "res",
+	"residenciales": "res",
+}
+
+// NormalizeAddress produce una forma canónica de la dirección para comparar
+// duplicados: minúsculas, sin tildes ni puntuación y con abreviaturas unificadas.
+func NormalizeAddress(address string) string {
+	normalized := addressReplacer.Replace(strings.ToLower(address))
+	tokens := strings.Fields(normalized)
+	for i, t := range tokens {
+		if abbr, ok := addressAbbreviations[t]; ok {
+			tokens[i] = abbr
+		}
+	}
+	return strings.Join(tokens, " ")
 }
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// PublicProperty son los campos de una propiedad visibles sin autenticación.
+// Se arma campo por campo para que los datos internos nuevos no se expongan por omisión:
+// no incluye la dirección exacta, metadatos de geocodificación ni datos del responsable.
+type PublicProperty struct {
+	ID            int64          `json:"id"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Language      string         `json:"language,omitempty"`
+	Price         float64        `json:"price"`
+	Currency      string         `json:"currency"`
+	City          string         `json:"city"`
+	Department    string         `json:"department,omitempty"`
+	Zone          string         `json:"zone,omitempty"`
+	Neighbourhood string         `json:"neighbourhood,omitempty"`
+	Type          string         `json:"type"`
+	Bedrooms      int            `json:"bedrooms,omitempty"`
+	Bathrooms     int            `json:"bathrooms,omitempty"`
+	Amenities     []string       `json:"amenities,omitempty"`
+	AreaSqM       float64        `json:"area_sqm"`
+	Areas         *PropertyAreas `json:"areas,omitempty"`
+	Lat           float64        `json:"lat,omitempty"` // Redondeadas a la precisión configurada
+	Lng           float64        `json:"lng,omitempty"`
+	MainImage     string         `json:"main_image"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NewPublicProperty copia los campos públicos y redondea las coordenadas a precision decimales
+func NewPublicProperty(p *Property, precision int) PublicProperty {
+	return PublicProperty{
+		ID:            p.ID,
+		Title:         p.Title,
+		Description:   p.Description,
+		Language:      p.Language,
+		Price:         p.Price,
+		Currency:      p.Currency,
+		City:          p.City,
+		Department:    p.Department,
+		Zone:          p.Zone,
+		Neighbourhood: p.Neighbourhood,
+		Type:          p.Type,
+		Bedrooms:      p.Bedrooms,
+		Bathrooms:     p.Bathrooms,
+		Amenities:     p.Amenities,
+		AreaSqM:       p.AreaSqM,
+		Areas:         p.Areas,
+		Lat:           roundCoordinate(p.Lat, precision),
+		Lng:           roundCoordinate(p.Lng, precision),
+		MainImage:     p.MainImage,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+func roundCoordinate(v float64, precision int) float64 {
+	factor := math.Pow(10, float64(precision))
+	return math.Round(v*factor) / factor
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Formatos del Registro General de la Propiedad: finca y folio numéricos, libro
+// numérico con sufijo opcional (ej. "123E"); la matrícula IUSI admite guiones
+var (
+	registryNumberPattern = regexp.MustCompile(`^[0-9]{1,7}$`)
+	registryLibroPattern  = regexp.MustCompile(`^[0-9]{1,6}[A-Z]?$`)
+	registryIUSIPattern   = regexp.MustCompile(`^[0-9A-Z]{1,12}(-[0-9A-Z]{1,12}){0,4}$`)
+)
+
+// registryPatterns asocia cada campo registral (nombre JSON) con su formato
+var registryPatterns = map[string]*regexp.Regexp{
+	"registry_finca": registryNumberPattern,
+	"registry_folio": registryNumberPattern,
+	"registry_libro": registryLibroPattern,
+	"registry_iusi":  registryIUSIPattern,
+}
+
+// ValidRegistryValue indica si el valor, ya normalizado, tiene el formato del campo registral
+func ValidRegistryValue(field, value string) bool {
+	pattern, ok := registryPatterns[field]
+	return ok && pattern.MatchString(value)
+}
+
+// IsRegistryField indica si el campo (nombre JSON) es un dato registral
+func IsRegistryField(field string) bool {
+	_, ok := registryPatterns[field]
+	return ok
+}
+
+// RegistryConflictError indica que otra propiedad activa tiene la misma identidad registral
+type RegistryConflictError struct {
+	Field      string // registry (finca/folio/libro) o registry_iusi
+	PropertyID int64  // 0 si no se conoce (detectado por el índice único)
+}
+
+func (e *RegistryConflictError) Error() string {
+	if e.PropertyID != 0 {
+		return fmt.Sprintf("%s already used by active property %d", e.Field, e.PropertyID)
+	}
+	return e.Field + " already used by another active property"
+}
+
+// IsActiveStatus indica si la propiedad cuenta para la unicidad registral
+func IsActiveStatus(status string) bool {
+	return status == PropertyStatusDraft || status == PropertyStatusPublished || status == PropertyStatusReserved
+}
+
+// HasRegistry indica si la propiedad tiene finca/folio/libro
+func (p *Property) HasRegistry() bool {
+	return p.RegistryFinca != ""
+}
+
+// NormalizeRegistry quita espacios y usa mayúsculas en los identificadores
+func (p *Property) NormalizeRegistry() {
+	for _, f := range []*string{&p.RegistryFinca, &p.RegistryFolio, &p.RegistryLibro, &p.RegistryIUSI} {
+		*f = strings.ToUpper(strings.TrimSpace(*f))
+	}
+}
+
+// HideRegistry elimina los datos registrales de respuestas para usuarios no internos
+func (p *Property) HideRegistry() {
+	p.RegistryFinca, p.RegistryFolio, p.RegistryLibro, p.RegistryIUSI = "", "", "", ""
+}
+
+// validateRegistry exige finca, folio y libro juntos y con formato válido
+func (p *Property) validateRegistry() error {
+	set := 0
+	for _, v := range []string{p.RegistryFinca, p.RegistryFolio, p.RegistryLibro} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 0 && set != 3 {
+		return fmt.Errorf("%w: registry finca, folio and libro must be sent together", ErrInvalidProperty)
+	}
+	if set == 3 && (!ValidRegistryValue("registry_finca", p.RegistryFinca) ||
+		!ValidRegistryValue("registry_folio", p.RegistryFolio) ||
+		!ValidRegistryValue("registry_libro", p.RegistryLibro)) {
+		return fmt.Errorf("%w: invalid registry finca/folio/libro format", ErrInvalidProperty)
+	}
+	if p.RegistryIUSI != "" && !ValidRegistryValue("registry_iusi", p.RegistryIUSI) {
+		return fmt.Errorf("%w: invalid registry_iusi format", ErrInvalidProperty)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Tipos de cambio registrados en el historial
+const (
+	RevisionCreate      = "create"
+	RevisionUpdate      = "update"
+	RevisionStatus      = "status"
+	RevisionCoordinates = "coordinates"
+	RevisionGeocode     = "geocode"
+	RevisionZone        = "zone"
+	RevisionRevert      = "revert"
+)
+
+// ErrRevisionNotFound indica que la revisión no existe para la propiedad
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// PropertyRevision es una copia completa de la propiedad después de un cambio
+type PropertyRevision struct {
+	ID           int64     `json:"id"`
+	PropertyID   int64     `json:"property_id"`
+	Revision     int       `json:"revision"`
+	ChangeType   string    `json:"change_type"`
+	ChangedBy    *string   `json:"changed_by,omitempty"`    // NULL = proceso automático
+	RevertedFrom *int      `json:"reverted_from,omitempty"` // Revisión restaurada
+	Snapshot     *Property `json:"snapshot,omitempty"`      // Se omite en el listado
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FieldChange es la diferencia de un campo entre dos revisiones
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// RevisionDiff son los campos que cambiaron entre dos revisiones
+type RevisionDiff struct {
+	PropertyID int64         `json:"property_id"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// diffIgnoredFields son campos calculados o de control que no se comparan
+var diffIgnoredFields = map[string]bool{
+	"areas":      true,
+	"updated_at": true,
+	"created_at": true,
+}
+
+// DiffProperties compara dos propiedades campo a campo usando sus nombres JSON
+func DiffProperties(from, to *Property) []FieldChange {
+	a, b := propertyFields(from), propertyFields(to)
+
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]FieldChange, 0)
+	for _, k := range keys {
+		if diffIgnoredFields[k] || reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: k, From: a[k], To: b[k]})
+	}
+	return changes
+}
+
+// HideRegistry quita los cambios de datos registrales para usuarios sin acceso a ellos
+func (d *RevisionDiff) HideRegistry() {
+	changes := d.Changes[:0]
+	for _, c := range d.Changes {
+		if !IsRegistryField(c.Field) {
+			changes = append(changes, c)
+		}
+	}
+	d.Changes = changes
+}
+
+func propertyFields(p *Property) map[string]any {
+	fields := map[string]any{}
+	raw, _ := json.Marshal(p)
+	json.Unmarshal(raw, &fields)
+	return fields
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// MaxSavedSearches limita las búsquedas guardadas por usuario
+const MaxSavedSearches = 20
+
+var (
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	ErrSavedSearchLimit    = errors.New("saved search limit reached")
+)
+
+// SavedSearch son criterios que el usuario quiere vigilar: cada propiedad nueva de su
+// agencia que los cumpla le llega por correo
+type SavedSearch struct {
+	ID             int64               `json:"id"`
+	UserID         string              `json:"-"`
+	OrganizationID string              `json:"-"`
+	Name           string              `json:"name"`
+	Criteria       SavedSearchCriteria `json:"criteria"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// SavedSearchCriteria son los filtros de la búsqueda; los vacíos no filtran
+type SavedSearchCriteria struct {
+	Type           string   `json:"type,omitempty"`
+	DepartmentID   int64    `json:"department_id,omitempty"`
+	MunicipalityID int64    `json:"municipality_id,omitempty"`
+	ZoneID         int64    `json:"zone_id,omitempty"`
+	Currency       string   `json:"currency,omitempty"` // Moneda de min_price/max_price
+	MinPrice       float64  `json:"min_price,omitempty"`
+	MaxPrice       float64  `json:"max_price,omitempty"`
+	MinBedrooms    int      `json:"min_bedrooms,omitempty"`
+	MinAreaSqM     float64  `json:"min_area_sqm,omitempty"`
+	MaxAreaSqM     float64  `json:"max_area_sqm,omitempty"`
+	Amenities      []string `json:"amenities,omitempty"` // Se exigen todas
+}
+
+// Matches indica si la propiedad cumple todos los criterios
+func (c *SavedSearchCriteria) Matches(p *Property) bool {
+	if c.Type != "" && p.Type != c.Type {
+		return false
+	}
+	if !matchesCatalogID(c.DepartmentID, p.DepartmentID) || !matchesCatalogID(c.MunicipalityID, p.MunicipalityID) ||
+		!matchesCatalogID(c.ZoneID, p.ZoneID) {
+		return false
+	}
+	if c.Currency != "" && p.Currency != c.Currency {
+		return false
+	}
+	if (c.MinPrice > 0 && p.Price < c.MinPrice) || (c.MaxPrice > 0 && p.Price > c.MaxPrice) {
+		return false
+	}
+	if p.Bedrooms < c.MinBedrooms {
+		return false
+	}
+	if (c.MinAreaSqM > 0 && p.AreaSqM < c.MinAreaSqM) || (c.MaxAreaSqM > 0 && p.AreaSqM > c.MaxAreaSqM) {
+		return false
+	}
+	for _, a := range c.Amenities {
+		if !slices.Contains(p.Amenities, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesCatalogID: sin criterio (0) cumple; con criterio la propiedad debe tener ese ID
+func matchesCatalogID(want int64, got *int64) bool {
+	return want == 0 || (got != nil && *got == want)
+}
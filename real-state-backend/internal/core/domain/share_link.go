@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Vigencia de los enlaces públicos
+const (
+	DefaultShareLinkTTL = 72 * time.Hour
+	MaxShareLinkTTL     = 30 * 24 * time.Hour
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	// ErrShareLinkInvalid agrupa firma inválida, enlace vencido o revocado (no se distingue al público)
+	ErrShareLinkInvalid = errors.New("invalid or expired share link")
+	// ErrPropertyNotListed indica que la propiedad no está publicada ni reservada
+	ErrPropertyNotListed = errors.New("property is not listed")
+)
+
+// ShareLink es un enlace firmado para ver una propiedad sin cuenta
+type ShareLink struct {
+	ID           int64      `json:"id"`
+	PropertyID   int64      `json:"property_id"`
+	CreatedBy    *string    `json:"created_by,omitempty"`
+	Token        string     `json:"token,omitempty"` // Solo al crearlo; no se almacena
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	OpenCount    int        `json:"open_count"`
+	LastOpenedAt *time.Time `json:"last_opened_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsActive indica si el enlace todavía se puede abrir
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
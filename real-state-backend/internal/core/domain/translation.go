@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLanguage es el idioma en que se capturan las propiedades
+const DefaultLanguage = "es"
+
+// SupportedLanguages son los idiomas en que se publican las propiedades
+var SupportedLanguages = []string{"es", "en"}
+
+// ErrTranslationNotFound indica que la propiedad no tiene traducción en el idioma
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// PropertyTranslation es el título y la descripción de una propiedad en otro idioma
+type PropertyTranslation struct {
+	PropertyID  int64     `json:"property_id"`
+	Language    string    `json:"language"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NormalizeLanguage reduce una etiqueta de idioma a su código base ("en-US" -> "en").
+// Retorna "" si el idioma no está soportado.
+func NormalizeLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if !slices.Contains(SupportedLanguages, tag) {
+		return ""
+	}
+	return tag
+}
+
+// ParseAcceptLanguage retorna los idiomas soportados de un encabezado
+// Accept-Language ordenados por preferencia (q), sin repetidos
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	entries := make([]weighted, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := NormalizeLanguage(fields[0])
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			entries = append(entries, weighted{lang, q})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	languages := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !slices.Contains(languages, e.lang) {
+			languages = append(languages, e.lang)
+		}
+	}
+	return languages
+}
+
+// Localize aplica la primera traducción disponible según los idiomas preferidos.
+// Si el idioma original aparece antes que una traducción, se conserva el original.
+func (p *Property) Localize(languages []string, translations []PropertyTranslation) {
+	p.Language = p.SourceLanguage
+	for _, lang := range languages {
+		if lang == p.SourceLanguage {
+			return
+		}
+		for _, t := range translations {
+			if t.Language != lang {
+				continue
+			}
+			p.Title, p.Language = t.Title, t.Language
+			if t.Description != "" {
+				p.Description = t.Description
+			}
+			return
+		}
+	}
+}
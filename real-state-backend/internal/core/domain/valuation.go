@@ -0,0 +1,41 @@
+package domain
+
+// ComparableCriteria describe el inmueble a valuar para buscar comparables
+type ComparableCriteria struct {
+	Type     string
+	City     string
+	AreaSqM  float64
+	Bedrooms int
+	Lat      float64
+	Lng      float64
+	RadiusKm float64 // Solo aplica si se envían coordenadas
+}
+
+// Comparable es una propiedad usada como referencia en una valuación
+type Comparable struct {
+	PropertyID     int64    `json:"property_id"`
+	Title          string   `json:"title"`
+	City           string   `json:"city"`
+	Price          float64  `json:"price"`
+	Currency       string   `json:"currency"`
+	AreaSqM        float64  `json:"area_sqm"`
+	Bedrooms       int      `json:"bedrooms,omitempty"`
+	PricePerSqM    float64  `json:"price_per_sqm"` // En la moneda de la valuación
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+}
+
+// ValuationEstimate es el resultado de una valuación automática
+type ValuationEstimate struct {
+	Currency          string       `json:"currency"`
+	AreaSqM           float64      `json:"area_sqm"`
+	EstimateLow       float64      `json:"estimate_low"`
+	Estimate          float64      `json:"estimate"`
+	EstimateHigh      float64      `json:"estimate_high"`
+	MedianPricePerSqM float64      `json:"median_price_per_sqm"`
+	Q1PricePerSqM     float64      `json:"q1_price_per_sqm"`
+	Q3PricePerSqM     float64      `json:"q3_price_per_sqm"`
+	IQR               float64      `json:"iqr"`
+	SampleSize        int          `json:"sample_size"`
+	OutliersDiscarded int          `json:"outliers_discarded"`
+	Comparables       []Comparable `json:"comparables"`
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEventPing solo se envía a pedido para probar una suscripción. Los demás
+// eventos son los de propiedades y leads del outbox (ver dto.webhookEventTypes).
+const WebhookEventPing = "webhook.ping"
+
+// Estados de una entrega
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed" // Agotó los reintentos
+)
+
+// Encabezados de los envíos
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderEventID   = "X-Webhook-Id"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderSignature = "X-Webhook-Signature" // t=<unix>,v1=<hex>
+)
+
+// Reintentos con espera exponencial: 30s, 1m, 2m... hasta MaxWebhookRetryDelay
+const (
+	MaxWebhookAttempts    = 10
+	webhookBaseRetryDelay = 30 * time.Second
+	MaxWebhookRetryDelay  = 6 * time.Hour
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrWebhookURLNotAllowed indica un destino que no resuelve o resuelve a una dirección privada o local
+	ErrWebhookURLNotAllowed = errors.New("webhook url not allowed")
+)
+
+// WebhookSubscription es un destino HTTP de los eventos de una agencia
+type WebhookSubscription struct {
+	ID             int64     `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	URL            string    `json:"url"`
+	Description    string    `json:"description"`
+	EventTypes     []string  `json:"event_types"`
+	Active         bool      `json:"active"`
+	Secret         string    `json:"secret,omitempty"` // Solo al crearla o rotarlo
+	CreatedBy      *string   `json:"created_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// WebhookSubscriptionChanges son los campos modificables (nil = sin cambio)
+type WebhookSubscriptionChanges struct {
+	URL          *string
+	Description  *string
+	EventTypes   []string
+	Active       *bool
+	RotateSecret bool
+}
+
+// WebhookEvent es el cuerpo JSON enviado a los suscriptores
+type WebhookEvent struct {
+	ID         string      `json:"id"` // Igual en reintentos y reenvíos (idempotencia del receptor)
+	Type       string      `json:"type"`
+	PropertyID int64       `json:"property_id,omitempty"` // Vacío en webhook.ping
+	CreatedAt  time.Time   `json:"created_at"`
+	Data       interface{} `json:"data"`
+}
+
+// PropertyEventData es el contenido de los eventos de propiedad (sin datos registrales)
+type PropertyEventData struct {
+	Property       Property `json:"property"`
+	PreviousStatus string   `json:"previous_status,omitempty"`
+}
+
+// WebhookDelivery es el envío de un evento a una suscripción
+type WebhookDelivery struct {
+	ID             int64            `json:"id"`
+	SubscriptionID int64            `json:"subscription_id"`
+	EventID        string           `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	Payload        json.RawMessage  `json:"payload"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int             `json:"last_status_code,omitempty"`
+	LastError      string           `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	RedeliveryOf   *int64           `json:"redelivery_of,omitempty"`
+	History        []WebhookAttempt `json:"history,omitempty"` // Solo en el detalle
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Target         WebhookTarget    `json:"-"`
+}
+
+// WebhookTarget es el destino de una entrega tomada por el despachador
+type WebhookTarget struct {
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt es un intento de envío de una entrega
+type WebhookAttempt struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   *int      `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	DurationMs   int       `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// WebhookRetryDelay es la espera antes del siguiente intento tras el intento número attempt (desde 1)
+func WebhookRetryDelay(attempt int) time.Duration {
+	return exponentialDelay(webhookBaseRetryDelay, MaxWebhookRetryDelay, attempt)
+}
+
+// RecordAttempt aplica el resultado de un intento: éxito con 2xx, reintento con
+// espera exponencial o fallo definitivo al agotar MaxWebhookAttempts
+func (d *WebhookDelivery) RecordAttempt(a WebhookAttempt) {
+	d.Attempts = a.Attempt
+	d.LastStatusCode, d.LastError = a.StatusCode, a.Error
+	d.UpdatedAt = a.CreatedAt
+	switch {
+	case a.StatusCode != nil && *a.StatusCode >= 200 && *a.StatusCode < 300:
+		d.Status, d.NextAttemptAt, d.DeliveredAt = WebhookDeliverySucceeded, nil, &a.CreatedAt
+	case d.Attempts >= MaxWebhookAttempts:
+		d.Status, d.NextAttemptAt = WebhookDeliveryFailed, nil
+	default:
+		next := a.CreatedAt.Add(WebhookRetryDelay(d.Attempts))
+		d.Status, d.NextAttemptAt = WebhookDeliveryPending, &next
+	}
+}
+
+// SignWebhook firma "<timestamp>.<body>" con HMAC-SHA256 y retorna el encabezado X-Webhook-Signature
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, webhookMAC(secret, t, body))
+}
+
+// VerifyWebhookSignature valida el encabezado X-Webhook-Signature. tolerance limita
+// la antigüedad del timestamp para rechazar reenvíos de un tercero.
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) bool {
+	var t, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || signature == "" {
+		return false
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(webhookMAC(secret, t, body)))
+}
+
+func webhookMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
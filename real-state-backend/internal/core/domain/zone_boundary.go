@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// Tipos de límite geográfico
+const (
+	BoundaryKindZone          = "zone"
+	BoundaryKindNeighbourhood = "neighbourhood"
+)
+
+// Point es una coordenada GeoJSON [lng, lat]
+type Point [2]float64
+
+// Ring es un anillo cerrado de un polígono
+type Ring []Point
+
+// Polygon es un anillo exterior seguido de sus huecos
+type Polygon []Ring
+
+// ZoneBoundary es el polígono de una zona o colonia cargado por administradores
+type ZoneBoundary struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Kind           string          `json:"kind"` // zone, neighbourhood
+	MunicipalityID *int64          `json:"municipality_id,omitempty"`
+	GeoZoneID      *int64          `json:"geo_zone_id,omitempty"` // Zona del catálogo asociada
+	Geometry       json.RawMessage `json:"geometry,omitempty"`    // GeoJSON Polygon/MultiPolygon
+	Polygons       []Polygon       `json:"-"`
+	MinLat         float64         `json:"min_lat"`
+	MinLng         float64         `json:"min_lng"`
+	MaxLat         float64         `json:"max_lat"`
+	MaxLng         float64         `json:"max_lng"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ErrInvalidGeometry indica un GeoJSON no soportado o mal formado
+var ErrInvalidGeometry = errors.New("invalid GeoJSON geometry")
+
+// ParseGeometry interpreta un GeoJSON Polygon o MultiPolygon
+func ParseGeometry(raw json.RawMessage) ([]Polygon, error) {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(raw, &geometry); err != nil {
+		return nil, ErrInvalidGeometry
+	}
+
+	var polygons []Polygon
+	switch geometry.Type {
+	case "Polygon":
+		var polygon Polygon
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, ErrInvalidGeometry
+		}
+		polygons = []Polygon{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, ErrInvalidGeometry
+		}
+	default:
+		return nil, ErrInvalidGeometry
+	}
+
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return nil, ErrInvalidGeometry
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				return nil, ErrInvalidGeometry
+			}
+			for _, pt := range ring {
+				if pt[0] < -180 || pt[0] > 180 || pt[1] < -90 || pt[1] > 90 {
+					return nil, ErrInvalidGeometry
+				}
+			}
+		}
+	}
+	return polygons, nil
+}
+
+// ComputeBounds calcula la caja envolvente de los polígonos
+func (b *ZoneBoundary) ComputeBounds() {
+	b.MinLat, b.MinLng = math.Inf(1), math.Inf(1)
+	b.MaxLat, b.MaxLng = math.Inf(-1), math.Inf(-1)
+	for _, polygon := range b.Polygons {
+		for _, pt := range polygon[0] {
+			b.MinLng, b.MaxLng = math.Min(b.MinLng, pt[0]), math.Max(b.MaxLng, pt[0])
+			b.MinLat, b.MaxLat = math.Min(b.MinLat, pt[1]), math.Max(b.MaxLat, pt[1])
+		}
+	}
+}
+
+// BoundsArea es el área aproximada de la caja envolvente (para preferir el límite más específico)
+func (b *ZoneBoundary) BoundsArea() float64 {
+	return (b.MaxLat - b.MinLat) * (b.MaxLng - b.MinLng)
+}
+
+// Centroid retorna el centroide del polígono exterior más grande; si cae fuera
+// (polígonos cóncavos) se usa el centro de la caja envolvente.
+func (b *ZoneBoundary) Centroid() (lat, lng float64) {
+	largest := 0.0
+	for _, polygon := range b.Polygons {
+		ring := polygon[0]
+		area, cx, cy := 0.0, 0.0, 0.0
+		for i := 0; i < len(ring)-1; i++ {
+			cross := ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+			area += cross
+			cx += (ring[i][0] + ring[i+1][0]) * cross
+			cy += (ring[i][1] + ring[i+1][1]) * cross
+		}
+		if area == 0 || math.Abs(area) <= largest {
+			continue
+		}
+		largest = math.Abs(area)
+		lng, lat = cx/(3*area), cy/(3*area)
+	}
+	if largest == 0 || !b.Contains(lat, lng) {
+		return (b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2
+	}
+	return lat, lng
+}
+
+// Contains indica si la coordenada cae dentro del límite (ray casting, respetando huecos)
+func (b *ZoneBoundary) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat || lng < b.MinLng || lng > b.MaxLng {
+		return false
+	}
+	for _, polygon := range b.Polygons {
+		if !ringContains(polygon[0], lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+func ringContains(ring Ring, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
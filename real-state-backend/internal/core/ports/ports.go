@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"io"
 	"real-state-backend/internal/core/domain"
 	"real-state-backend/internal/dto"
 	"time"
@@ -10,16 +11,85 @@ import (
 // PropertyRepository define las operaciones de base de datos.
 type PropertyRepository interface {
 	GetByID(ctx context.Context, id int64) (*domain.Property, error)
-	GetAll(ctx context.Context, limit, offset int) ([]domain.Property, error)
+	// GetByIDForUpdate lee la propiedad bloqueando la fila hasta el fin de la transacción del contexto
+	GetByIDForUpdate(ctx context.Context, id int64) (*domain.Property, error)
+	GetAll(ctx context.Context, filter domain.PropertyFilter, limit, offset int) ([]domain.Property, error)
+	// GetByIDs retorna las propiedades encontradas, sin orden garantizado
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Property, error)
 	Create(ctx context.Context, property *domain.Property) error
+	// Update guarda el contenido editable; no toca coordenadas, geocodificación ni polígono
+	Update(ctx context.Context, property *domain.Property) error
+	// ReplaceGeocode sobrescribe coordenadas, geocodificación y polígono (cambio de
+	// dirección o reversión)
+	ReplaceGeocode(ctx context.Context, property *domain.Property) error
+	// UpdateStatus retorna domain.ErrPropertyStatusChanged si el estado ya no es from
+	UpdateStatus(ctx context.Context, id int64, from, to string, closedAt *time.Time) error
+	ListWithCoordinates(ctx context.Context, afterID int64, limit int) ([]domain.Property, error)
+	UpdateZone(ctx context.Context, id int64, zoneBoundaryID, zoneID *int64) error
+	// UpdateGeocode retorna domain.ErrCoordinatesAlreadySet si un resultado automático
+	// encuentra la propiedad ya con coordenadas
+	UpdateGeocode(ctx context.Context, property *domain.Property) error
+	// ClaimPendingGeocodes marca como intentadas y retorna propiedades con dirección y sin
+	// coordenadas cuyo último intento es anterior a retryBefore
+	ClaimPendingGeocodes(ctx context.Context, now, retryBefore time.Time, limit int) ([]int64, error)
+	// FindRegistryConflict retorna otra propiedad activa con la misma identidad registral (nil si no hay)
+	FindRegistryConflict(ctx context.Context, property *domain.Property) (*domain.RegistryConflictError, error)
+	// FindSimilar retorna propiedades con misma dirección normalizada, coordenadas cercanas o precio
+	// parecido, de la coincidencia más fuerte a la más débil
+	FindSimilar(ctx context.Context, property *domain.Property, limit int) ([]domain.Property, error)
+	// FindDuplicatePairs retorna pares del catálogo que comparten dirección, ubicación o título/precio,
+	// ordenados por (first, second) y posteriores al par del cursor
+	FindDuplicatePairs(ctx context.Context, afterFirst, afterSecond int64, limit int) ([]domain.DuplicatePair, error)
+	// ListAddresses retorna ID y dirección por páginas de ID (para recalcular la dirección normalizada)
+	ListAddresses(ctx context.Context, afterID int64, limit int) ([]domain.Property, error)
+	// UpdateAddressNormalized retorna false si la dirección normalizada no cambió
+	UpdateAddressNormalized(ctx context.Context, id int64, normalized string) (bool, error)
+	// FindComparables retorna propiedades del mismo tipo en la ciudad o radio indicado
+	FindComparables(ctx context.Context, criteria domain.ComparableCriteria, limit int) ([]domain.Property, error)
 	// Aquí agregarías métodos de filtro avanzados más adelante
 }
 
 // PropertyService define la lógica de negocio.
 type PropertyService interface {
 	GetProperty(ctx context.Context, id int64) (*domain.Property, error)
-	ListProperties(ctx context.Context, page, pageSize int) ([]domain.Property, error)
-	CreateProperty(ctx context.Context, property *domain.Property) error
+	ListProperties(ctx context.Context, filter domain.PropertyFilter, page, pageSize int) ([]domain.Property, error)
+	// CreateProperty retorna *domain.DuplicatePropertyError si hay posibles duplicados y no se confirmó
+	CreateProperty(ctx context.Context, property *domain.Property, confirmDuplicate bool) error
+	// UpdateProperty aplica apply sobre la fila bloqueada y guarda el resultado en la
+	// misma transacción
+	UpdateProperty(ctx context.Context, id int64, apply func(p *domain.Property) error) (*domain.Property, error)
+	FindDuplicates(ctx context.Context, limit int) ([]domain.DuplicatePair, error)
+	// BackfillAddresses recalcula la dirección normalizada de las propiedades existentes
+	BackfillAddresses(ctx context.Context, batchSize int) (int, error)
+	// ChangeStatus liquida las comisiones en la misma transacción al vender o rentar
+	ChangeStatus(ctx context.Context, id int64, status string, sellingAgentID *string) (*domain.Property, error)
+	// SetCoordinates fija coordenadas manuales, que el geocodificador ya no sobrescribe
+	SetCoordinates(ctx context.Context, id int64, lat, lng float64) (*domain.Property, error)
+	ListRevisions(ctx context.Context, id int64) ([]domain.PropertyRevision, error)
+	// GetRevision retorna la revisión con su copia completa (revision 0 = la más reciente)
+	GetRevision(ctx context.Context, id int64, revision int) (*domain.PropertyRevision, error)
+	DiffRevisions(ctx context.Context, id int64, from, to int) (*domain.RevisionDiff, error)
+	// RevertToRevision restaura el contenido de una revisión anterior como una revisión nueva
+	RevertToRevision(ctx context.Context, id int64, revision int) (*domain.Property, error)
+	// Localize aplica las traducciones según los idiomas preferidos (con respaldo al idioma original)
+	Localize(ctx context.Context, properties []domain.Property, languages []string) error
+	ListTranslations(ctx context.Context, id int64) ([]domain.PropertyTranslation, error)
+	SaveTranslation(ctx context.Context, translation *domain.PropertyTranslation) error
+	DeleteTranslation(ctx context.Context, id int64, language string) error
+}
+
+// PropertyRevisionRepository define operaciones de BD para el historial de propiedades.
+type PropertyRevisionRepository interface {
+	Create(ctx context.Context, revision *domain.PropertyRevision) error
+	List(ctx context.Context, propertyID int64) ([]domain.PropertyRevision, error)
+	Get(ctx context.Context, propertyID int64, revision int) (*domain.PropertyRevision, error)
+}
+
+// PropertyTranslationRepository define operaciones de BD para traducciones de propiedades.
+type PropertyTranslationRepository interface {
+	Upsert(ctx context.Context, translation *domain.PropertyTranslation) error
+	List(ctx context.Context, propertyIDs []int64, languages []string) ([]domain.PropertyTranslation, error)
+	Delete(ctx context.Context, propertyID int64, language string) error
 }
 
 // AuthService define la lógica de autenticación.
@@ -30,6 +100,8 @@ type AuthService interface {
 	Logout(ctx context.Context, tokenJTI string) error
 	ValidateSession(ctx context.Context, tokenJTI string) (*domain.UserSession, error)
 	GetUserPermissions(ctx context.Context, userID string) ([]domain.Permission, error)
+	// HashPassword genera el hash para guardar la contraseña de un usuario nuevo
+	HashPassword(password string) (string, error)
 }
 
 // UserRepository define operaciones de BD para usuarios.
@@ -37,12 +109,34 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	UpdateFailedAttempts(ctx context.Context, id string, attempts int, lockedUntil *time.Time) error
+	// UpdatePassword guarda el hash nuevo y desbloquea la cuenta
+	UpdatePassword(ctx context.Context, id string, passwordHash string) error
 	UpdateMFASecret(ctx context.Context, id string, secret string) error
 	// GetPermissions retorna los permisos efectivos de un usuario (a través de roles)
 	GetPermissions(ctx context.Context, userID string) ([]domain.Permission, error)
 }
 
+// PasswordResetRepository define operaciones de BD para los enlaces de recuperación de contraseña.
+type PasswordResetRepository interface {
+	// Create invalida los enlaces pendientes del usuario y registra uno sin token
+	Create(ctx context.Context, reset *domain.PasswordReset) error
+	// IssueToken guarda el hash de un token nuevo para un enlace vigente del usuario y
+	// retorna su vencimiento (ErrPasswordResetInvalid si fue usado, reemplazado o venció)
+	IssueToken(ctx context.Context, id int64, userID, tokenHash string, now time.Time) (time.Time, error)
+	// Consume marca usado un enlace vigente y retorna su usuario (ErrPasswordResetInvalid si no lo hay)
+	Consume(ctx context.Context, tokenHash string, now time.Time) (string, error)
+}
+
+// PasswordResetService define la recuperación de contraseña por correo.
+type PasswordResetService interface {
+	// RequestReset envía el enlace; no revela si el correo está registrado
+	RequestReset(ctx context.Context, email string) error
+	// ResetPassword cambia la contraseña y revoca las sesiones del usuario
+	ResetPassword(ctx context.Context, token, password string) error
+}
+
 // SessionRepository define operaciones de BD para sesiones.
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.UserSession) error
@@ -64,3 +158,377 @@ type SecurityConfigRepository interface {
 	GetDuration(ctx context.Context, key string) (time.Duration, error)
 	UpdateConfig(ctx context.Context, key string, value string) error
 }
+
+// AnalyticsRepository define operaciones de BD para eventos de propiedades.
+type AnalyticsRepository interface {
+	// RecordEvent inserta el evento; las vistas/favoritos duplicados del día se ignoran
+	RecordEvent(ctx context.Context, event *domain.PropertyEvent) error
+	GetDailyStats(ctx context.Context, propertyID int64, from, to time.Time) ([]domain.PropertyDailyStats, error)
+	GetTopViewed(ctx context.Context, from, to time.Time, limit int) ([]domain.PropertyViewRank, error)
+}
+
+// AnalyticsService define la lógica de registro y consulta de analítica.
+type AnalyticsService interface {
+	// TrackView encola una vista de detalle sin bloquear la petición
+	TrackView(event domain.PropertyEvent)
+	RecordEvent(ctx context.Context, event *domain.PropertyEvent) error
+	GetDailyStats(ctx context.Context, propertyID int64, from, to time.Time) ([]domain.PropertyDailyStats, error)
+	GetTopViewed(ctx context.Context, from, to time.Time, limit int) ([]domain.PropertyViewRank, error)
+}
+
+// ExchangeRateRepository define operaciones de BD para tipos de cambio.
+type ExchangeRateRepository interface {
+	// GetRates retorna USD por unidad de cada moneda
+	GetRates(ctx context.Context) (map[string]float64, error)
+}
+
+// ValuationService define la lógica de valuación por comparables.
+type ValuationService interface {
+	Estimate(ctx context.Context, criteria domain.ComparableCriteria, currency string) (*domain.ValuationEstimate, error)
+}
+
+// MarketStatsRepository define operaciones sobre la vista materializada de mercado.
+type MarketStatsRepository interface {
+	// GetMonthly retorna las estadísticas en USD
+	GetMonthly(ctx context.Context, filter domain.MarketStatsFilter) ([]domain.MarketStat, error)
+	Refresh(ctx context.Context) error
+}
+
+// MarketStatsService define la lógica de estadísticas de mercado.
+type MarketStatsService interface {
+	GetMonthly(ctx context.Context, filter domain.MarketStatsFilter, currency string) ([]domain.MarketStat, error)
+	Refresh(ctx context.Context) error
+}
+
+// ShareLinkRepository define operaciones de BD para enlaces públicos de propiedades.
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *domain.ShareLink) error
+	Get(ctx context.Context, id int64) (*domain.ShareLink, error)
+	List(ctx context.Context, propertyID int64) ([]domain.ShareLink, error)
+	Revoke(ctx context.Context, propertyID, id int64) error
+	// RecordOpen suma una apertura solo si el enlace sigue activo
+	RecordOpen(ctx context.Context, id int64) error
+}
+
+// ShareLinkService define la generación y validación de enlaces públicos firmados.
+type ShareLinkService interface {
+	Create(ctx context.Context, propertyID int64, ttl time.Duration) (*domain.ShareLink, error)
+	List(ctx context.Context, propertyID int64) ([]domain.ShareLink, error)
+	Revoke(ctx context.Context, propertyID, id int64) error
+	// Open valida el token, registra la apertura y retorna la propiedad compartida
+	Open(ctx context.Context, token string) (*domain.Property, *domain.ShareLink, error)
+}
+
+// PublicCatalogService define el catálogo público de propiedades publicadas.
+type PublicCatalogService interface {
+	List(ctx context.Context, filter domain.PropertyFilter, page int, languages []string) ([]domain.PublicProperty, error)
+	// Get retorna domain.ErrPropertyNotListed si la propiedad no existe o no está publicada
+	Get(ctx context.Context, id int64, languages []string) (*domain.PublicProperty, error)
+}
+
+// DocumentStorage define dónde se guardan los archivos de documentos (disco, S3, ...).
+type DocumentStorage interface {
+	Save(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// PropertyDocumentRepository define operaciones de BD para documentos de propiedades.
+type PropertyDocumentRepository interface {
+	CreateDocument(ctx context.Context, doc *domain.PropertyDocument) error
+	// AddVersion asigna el siguiente número de versión del documento
+	AddVersion(ctx context.Context, version *domain.DocumentVersion) error
+	// ListDocuments incluye la última versión de cada documento
+	ListDocuments(ctx context.Context, propertyID int64) ([]domain.PropertyDocument, error)
+	GetDocument(ctx context.Context, propertyID, id int64) (*domain.PropertyDocument, error)
+	ListVersions(ctx context.Context, documentID int64) ([]domain.DocumentVersion, error)
+	// GetVersion retorna la versión indicada (version 0 = la más reciente)
+	GetVersion(ctx context.Context, documentID int64, version int) (*domain.DocumentVersion, error)
+}
+
+// PropertyDocumentService define la bóveda de documentos; solo accede el agente
+// responsable de la propiedad o quien tenga el permiso view_property_documents.
+type PropertyDocumentService interface {
+	Upload(ctx context.Context, upload domain.DocumentUpload) (*domain.PropertyDocument, error)
+	List(ctx context.Context, propertyID int64) ([]domain.PropertyDocument, error)
+	ListVersions(ctx context.Context, propertyID, documentID int64) ([]domain.DocumentVersion, error)
+	// Download registra la descarga en auditoría antes de abrir el archivo
+	Download(ctx context.Context, propertyID, documentID int64, version int, origin domain.RequestOrigin) (*domain.DocumentVersion, io.ReadCloser, error)
+}
+
+// ComparisonService define la comparación de propiedades lado a lado.
+type ComparisonService interface {
+	// Compare retorna las propiedades en el orden de ids con los precios convertidos a currency
+	Compare(ctx context.Context, ids []int64, currency string) (*domain.PropertyComparison, error)
+}
+
+// MortgageProductRepository define operaciones de BD para productos hipotecarios.
+type MortgageProductRepository interface {
+	List(ctx context.Context, activeOnly bool) ([]domain.MortgageProduct, error)
+	GetByID(ctx context.Context, id int64) (*domain.MortgageProduct, error)
+	Create(ctx context.Context, product *domain.MortgageProduct) error
+	Update(ctx context.Context, product *domain.MortgageProduct) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// MortgageService define la calculadora hipotecaria y la gestión de presets.
+type MortgageService interface {
+	Calculate(ctx context.Context, input domain.MortgageInput) (*domain.MortgageQuote, error)
+	ListProducts(ctx context.Context, activeOnly bool) ([]domain.MortgageProduct, error)
+	CreateProduct(ctx context.Context, product *domain.MortgageProduct) error
+	UpdateProduct(ctx context.Context, product *domain.MortgageProduct) error
+	DeleteProduct(ctx context.Context, id int64) error
+}
+
+// GeoRepository define operaciones de BD para el catálogo geográfico.
+type GeoRepository interface {
+	ListDepartments(ctx context.Context) ([]domain.Department, error)
+	ListMunicipalities(ctx context.Context, departmentID int64) ([]domain.Municipality, error)
+	ListZones(ctx context.Context, municipalityID int64) ([]domain.Zone, error)
+}
+
+// GeoService define la consulta del catálogo y la normalización de ubicaciones.
+type GeoService interface {
+	ListDepartments(ctx context.Context) ([]domain.Department, error)
+	ListMunicipalities(ctx context.Context, departmentID int64) ([]domain.Municipality, error)
+	ListZones(ctx context.Context, municipalityID int64) ([]domain.Zone, error)
+	// Resolve valida departamento/municipio/zona escritos libremente contra el catálogo;
+	// un municipio fuera del catálogo retorna una ubicación con Unresolved
+	Resolve(ctx context.Context, department, city string, zone int) (*domain.GeoLocation, error)
+}
+
+// ZoneBoundaryRepository define operaciones de BD para polígonos de zonas.
+type ZoneBoundaryRepository interface {
+	// Upsert crea o reemplaza el polígono identificado por nombre y tipo
+	Upsert(ctx context.Context, boundary *domain.ZoneBoundary) error
+	List(ctx context.Context) ([]domain.ZoneBoundary, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// ZoneBoundaryService define la carga de polígonos y la asignación de zonas.
+type ZoneBoundaryService interface {
+	// Import carga un GeoJSON FeatureCollection/Feature con properties.name
+	Import(ctx context.Context, geojson []byte) ([]domain.ZoneBoundary, error)
+	List(ctx context.Context) ([]domain.ZoneBoundary, error)
+	Delete(ctx context.Context, id int64) error
+	// Locate retorna el polígono más específico que contiene la coordenada (nil si ninguno)
+	Locate(ctx context.Context, lat, lng float64) (*domain.ZoneBoundary, error)
+	// Backfill asigna la zona a las propiedades existentes con coordenadas
+	Backfill(ctx context.Context, batchSize int) (int, error)
+}
+
+// Geocoder convierte una dirección en coordenadas (proveedor externo o catálogo local).
+type Geocoder interface {
+	// Geocode retorna domain.ErrAddressNotFound si no hay resultados
+	Geocode(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error)
+}
+
+// OfferRepository define operaciones de BD para ofertas y su hilo de negociación.
+// Los cambios de estado se guardan junto con su evento en una transacción.
+type OfferRepository interface {
+	Create(ctx context.Context, offer *domain.Offer, event *domain.OfferEvent) error
+	Get(ctx context.Context, propertyID, id int64) (*domain.Offer, error)
+	// List retorna las ofertas de la propiedad; con buyerID solo las de ese comprador
+	List(ctx context.Context, propertyID int64, buyerID string) ([]domain.Offer, error)
+	ListEvents(ctx context.Context, offerID int64) ([]domain.OfferEvent, error)
+	AddEvent(ctx context.Context, event *domain.OfferEvent) error
+	// Transition aplica el nuevo estado solo si la oferta sigue en fromStatus (si no, ErrInvalidOfferTransition)
+	Transition(ctx context.Context, offer *domain.Offer, fromStatus string, event *domain.OfferEvent) error
+	// Accept además reserva la propiedad publicada y rechaza las demás ofertas abiertas
+	Accept(ctx context.Context, offer *domain.Offer, fromStatus string, event *domain.OfferEvent) error
+	// ExpireDue vence las ofertas abiertas cuya vigencia terminó y retorna cuántas
+	ExpireDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// OfferService define la negociación de ofertas sobre propiedades.
+type OfferService interface {
+	Submit(ctx context.Context, propertyID int64, terms domain.OfferTerms, message string) (*domain.Offer, error)
+	List(ctx context.Context, propertyID int64) ([]domain.Offer, error)
+	// Get incluye el hilo de negociación
+	Get(ctx context.Context, propertyID, id int64) (*domain.Offer, error)
+	Counter(ctx context.Context, propertyID, id int64, terms domain.OfferTerms, message string) (*domain.Offer, error)
+	// Respond acepta, rechaza o retira la oferta según el estado indicado
+	Respond(ctx context.Context, propertyID, id int64, status, message string) (*domain.Offer, error)
+	AddMessage(ctx context.Context, propertyID, id int64, message string) (*domain.OfferEvent, error)
+}
+
+// CommissionRepository define operaciones de BD para reglas y libro de comisiones.
+type CommissionRepository interface {
+	ListRules(ctx context.Context) ([]domain.CommissionRule, error)
+	GetRule(ctx context.Context, operationType string) (*domain.CommissionRule, error)
+	SaveRule(ctx context.Context, rule *domain.CommissionRule) error
+	// CreateSettlement guarda la liquidación y sus entradas en una transacción
+	// (ErrCommissionAlreadySettled si el cierre ya se liquidó)
+	CreateSettlement(ctx context.Context, settlement *domain.CommissionSettlement) error
+	ListSettlements(ctx context.Context, propertyID int64) ([]domain.CommissionSettlement, error)
+	// ListAgentEntries retorna las entradas del agente con entry_date en [from, to)
+	ListAgentEntries(ctx context.Context, agentID string, from, to time.Time) ([]domain.CommissionEntry, error)
+}
+
+// CommissionService define el cálculo de comisiones al cerrar operaciones.
+type CommissionService interface {
+	ListRules(ctx context.Context) ([]domain.CommissionRule, error)
+	UpdateRule(ctx context.Context, rule *domain.CommissionRule) error
+	// Settle liquida el último cierre de la propiedad; sellingAgentID nil = el agente captador
+	Settle(ctx context.Context, propertyID int64, sellingAgentID *string) (*domain.CommissionSettlement, error)
+	ListSettlements(ctx context.Context, propertyID int64) ([]domain.CommissionSettlement, error)
+	// Statement retorna el estado de cuenta; un agente solo puede consultar el suyo sin manage_commissions
+	Statement(ctx context.Context, agentID string, from, to time.Time) (*domain.AgentStatement, error)
+}
+
+// OrganizationRepository define operaciones de BD para agencias, sus usuarios y roles.
+type OrganizationRepository interface {
+	// Create copia las reglas de comisión de la agencia por defecto
+	Create(ctx context.Context, org *domain.Organization) error
+	Get(ctx context.Context, id string) (*domain.Organization, error)
+	List(ctx context.Context) ([]domain.Organization, error)
+	Update(ctx context.Context, org *domain.Organization) error
+	ListUsers(ctx context.Context, organizationID string) ([]domain.User, error)
+	GetUser(ctx context.Context, organizationID, userID string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User, roleIDs []string) error
+	SetUserRoles(ctx context.Context, organizationID, userID string, roleIDs []string) error
+	// ListRoles retorna los roles globales y los propios de la agencia
+	ListRoles(ctx context.Context, organizationID string) ([]domain.Role, error)
+	// CreateRole rechaza permisos inexistentes o de plataforma
+	CreateRole(ctx context.Context, role *domain.Role) error
+}
+
+// OrganizationService define la gestión de agencias (plataforma) y de sus usuarios (agencia).
+type OrganizationService interface {
+	List(ctx context.Context) ([]domain.Organization, error)
+	Create(ctx context.Context, org *domain.Organization) error
+	Update(ctx context.Context, id string, changes domain.OrganizationChanges) (*domain.Organization, error)
+	// CreateAgencyAdmin crea el primer administrador de una agencia
+	CreateAgencyAdmin(ctx context.Context, organizationID string, user *domain.User, password string) error
+	// Los siguientes métodos operan sobre la agencia del usuario autenticado
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User, password string, roleIDs []string) error
+	SetUserRoles(ctx context.Context, userID string, roleIDs []string) (*domain.User, error)
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) error
+}
+
+// TxManager ejecuta fn en una transacción; los repositorios que reciben el contexto
+// de fn participan en ella y un error de fn revierte todos los cambios.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepository define operaciones de BD para el outbox de eventos de dominio.
+type OutboxRepository interface {
+	// Add registra los eventos; dentro de TxManager.WithinTx se confirman con el cambio
+	Add(ctx context.Context, events ...*domain.OutboxEvent) error
+	// ClaimDue toma hasta limit eventos pendientes y los aparta por lease
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+	// MarkRetry guarda intentos, próximo intento y último error
+	MarkRetry(ctx context.Context, event *domain.OutboxEvent) error
+	// DeletePublished elimina los eventos publicados antes de before y retorna cuántos
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+}
+
+// EventHandler procesa un evento del outbox; un error hace que se reintente
+// (también para los demás suscriptores del evento).
+type EventHandler func(ctx context.Context, event *domain.OutboxEvent) error
+
+// WebhookRepository define operaciones de BD para suscripciones y entregas de webhooks.
+// Las suscripciones se acotan a la agencia del usuario autenticado.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// UpdateSubscription reemplaza el secreto solo si sub.Secret no está vacío
+	UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+	// Enqueue crea una entrega por cada suscripción activa al evento de la agencia dueña
+	// de la propiedad y retorna cuántas
+	Enqueue(ctx context.Context, event *domain.WebhookEvent, payload []byte) (int, error)
+	// EnqueueTo crea una entrega para una suscripción (activa o no)
+	EnqueueTo(ctx context.Context, subscriptionID int64, event *domain.WebhookEvent, payload []byte) (*domain.WebhookDelivery, error)
+	// ClaimDue toma hasta limit entregas vencidas de suscripciones activas y las aparta
+	// por lease para que otra instancia no las envíe a la vez
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	// RecordAttempt guarda el intento y el nuevo estado de la entrega en una transacción
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error)
+	// GetDelivery incluye el historial de intentos
+	GetDelivery(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error)
+	// Redeliver crea una entrega pendiente con el mismo evento y payload
+	Redeliver(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error)
+}
+
+// WebhookService define la gestión de webhooks y el despacho de sus entregas.
+type WebhookService interface {
+	// EnqueueEvent crea las entregas de un evento del outbox (idempotente por EventID)
+	EnqueueEvent(ctx context.Context, event *domain.OutboxEvent) error
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	Get(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]domain.WebhookSubscription, error)
+	Update(ctx context.Context, id int64, changes domain.WebhookSubscriptionChanges) (*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id int64) error
+	// Ping encola un evento de prueba para la suscripción
+	Ping(ctx context.Context, id int64) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error)
+}
+
+// EmailSender envía un correo (SMTP u otro proveedor).
+type EmailSender interface {
+	Send(ctx context.Context, msg *domain.EmailMessage) error
+}
+
+// NotificationRepository define operaciones de BD para la cola de correos y las preferencias.
+type NotificationRepository interface {
+	// GetPreferences retorna las preferencias por defecto si el usuario no ha guardado
+	GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *domain.NotificationPreferences) error
+	// Enqueue no hace nada si ya existe una notificación con el mismo DedupeKey
+	Enqueue(ctx context.Context, n *domain.Notification) error
+	// ClaimDue toma hasta limit notificaciones pendientes vencidas y las aparta por lease
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error)
+	// RecordAttempt guarda estado, intentos, próximo intento y último error
+	RecordAttempt(ctx context.Context, n *domain.Notification) error
+	ListByUser(ctx context.Context, userID string, limit int) ([]domain.Notification, error)
+}
+
+// SavedSearchRepository define operaciones de BD para las búsquedas guardadas.
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *domain.SavedSearch) error
+	ListByUser(ctx context.Context, userID string) ([]domain.SavedSearch, error)
+	Delete(ctx context.Context, userID string, id int64) error
+	// ListCandidates retorna las búsquedas de la agencia que pueden coincidir con el tipo
+	ListCandidates(ctx context.Context, organizationID, propertyType string) ([]domain.SavedSearch, error)
+}
+
+// SavedSearchService define las búsquedas guardadas del usuario autenticado y sus avisos.
+type SavedSearchService interface {
+	Create(ctx context.Context, search *domain.SavedSearch) error
+	List(ctx context.Context) ([]domain.SavedSearch, error)
+	Delete(ctx context.Context, id int64) error
+	// HandlePropertyCreated avisa a los usuarios cuyas búsquedas cumple la propiedad nueva (suscriptor del outbox)
+	HandlePropertyCreated(ctx context.Context, event *domain.OutboxEvent) error
+	// HandlePropertyStatusChanged avisa cuando una propiedad existente pasa a publicada
+	HandlePropertyStatusChanged(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// NotificationDataFunc retorna, al momento de enviar, datos de plantilla que no deben
+// guardarse en la cola (p. ej. tokens). ErrNotificationExpired descarta la notificación.
+type NotificationDataFunc func(ctx context.Context, n *domain.Notification) (map[string]interface{}, error)
+
+// NotificationService define el envío de correos y las preferencias del usuario autenticado.
+type NotificationService interface {
+	// Notify encola la notificación del tipo para el usuario según sus preferencias.
+	// dedupeKey (opcional) evita duplicarla al reprocesar un evento.
+	Notify(ctx context.Context, userID, kind string, data map[string]interface{}, dedupeKey string) error
+	// RenderOnSend hace que el tipo se guarde sin cuerpo y se renderice al enviar con los
+	// datos de fn
+	RenderOnSend(kind string, fn NotificationDataFunc)
+	// HandleLeadCreated avisa al agente responsable de una consulta nueva (suscriptor del outbox)
+	HandleLeadCreated(ctx context.Context, event *domain.OutboxEvent) error
+	GetPreferences(ctx context.Context) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, changes domain.NotificationPreferencesChanges) (*domain.NotificationPreferences, error)
+	List(ctx context.Context, limit int) ([]domain.Notification, error)
+	// SendTest encola un correo de prueba para el usuario
+	SendTest(ctx context.Context) (*domain.Notification, error)
+}
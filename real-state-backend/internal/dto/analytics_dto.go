@@ -0,0 +1,16 @@
+package dto
+
+// CreateInquiryDTO representa una consulta de un interesado sobre un inmueble
+type CreateInquiryDTO struct {
+	Message string `json:"message"`
+	Contact string `json:"contact"` // Email o teléfono opcional
+}
+
+// Validate valida los campos del DTO
+func (d *CreateInquiryDTO) Validate() error {
+	var v Validator
+	v.Check(d.Message != "", "message", CodeRequired, "message is required")
+	v.Check(len(d.Message) <= 2000, "message", CodeTooLong, "message must be at most 2000 characters")
+	v.Check(len(d.Contact) <= 255, "contact", CodeTooLong, "contact must be at most 255 characters")
+	return v.Err()
+}
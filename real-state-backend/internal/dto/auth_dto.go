@@ -1,8 +1,6 @@
 package dto
 
-import (
-	"errors"
-)
+import "strings"
 
 // LoginRequestDTO representa las credenciales de login
 type LoginRequestDTO struct {
@@ -12,14 +10,11 @@ type LoginRequestDTO struct {
 
 // Validate valida los campos del DTO
 func (dto *LoginRequestDTO) Validate() error {
-	if dto.Username == "" {
-		return errors.New("username is required")
-	}
-	if dto.Password == "" {
-		return errors.New("password is required")
-	}
+	var v Validator
+	v.Check(dto.Username != "", "username", CodeRequired, "username is required")
+	v.Check(dto.Password != "", "password", CodeRequired, "password is required")
 	// Aquí podrías agregar validaciones adicionales, como longitud mínima
-	return nil
+	return v.Err()
 }
 
 // LoginResponseDTO representa la respuesta de login con el token
@@ -72,7 +67,54 @@ type MFAVerifyRequestDTO struct {
 	Code string `json:"code"`
 }
 
+// Validate valida los campos del DTO
+func (dto *MFAVerifyRequestDTO) Validate() error {
+	var v Validator
+	if dto.Code == "" {
+		v.Add("code", CodeRequired, "code is required")
+	} else {
+		v.Check(len(dto.Code) == 6, "code", CodeInvalidFormat, "code must have 6 digits")
+	}
+	return v.Err()
+}
+
 // RefreshTokenRequestDTO para refresh token
 type RefreshTokenRequestDTO struct {
 	RefreshToken string `json:"refresh_token"`
 }
+
+// Validate valida los campos del DTO
+func (dto *RefreshTokenRequestDTO) Validate() error {
+	var v Validator
+	v.Check(dto.RefreshToken != "", "refresh_token", CodeRequired, "refresh_token is required")
+	return v.Err()
+}
+
+// ForgotPasswordDTO solicita el enlace de recuperación de contraseña
+type ForgotPasswordDTO struct {
+	Email string `json:"email"`
+}
+
+// Validate valida los campos del DTO
+func (d *ForgotPasswordDTO) Validate() error {
+	var v Validator
+	d.Email = strings.TrimSpace(d.Email)
+	v.Check(d.Email != "", "email", CodeRequired, "email is required")
+	v.Check(len(d.Email) <= 100, "email", CodeTooLong, "email must have at most 100 characters")
+	return v.Err()
+}
+
+// ResetPasswordDTO cambia la contraseña con el token recibido por correo
+type ResetPasswordDTO struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Validate valida los campos del DTO
+func (d *ResetPasswordDTO) Validate() error {
+	var v Validator
+	v.Check(d.Token != "", "token", CodeRequired, "token is required")
+	v.Check(len(d.Token) <= 128, "token", CodeTooLong, "token must have at most 128 characters")
+	validatePassword(&v, d.Password)
+	return v.Err()
+}
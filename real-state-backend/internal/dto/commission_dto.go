@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CommissionRuleDTO actualiza la regla de un tipo de operación (porcentajes 0-100)
+type CommissionRuleDTO struct {
+	RatePercent         float64 `json:"rate_percent"` // Sobre el precio; en renta 100 = un mes
+	ListingSplitPercent float64 `json:"listing_split_percent"`
+	AgencySharePercent  float64 `json:"agency_share_percent"`
+	IVAPercent          float64 `json:"iva_percent"`
+}
+
+// Validate valida los campos del DTO
+func (d *CommissionRuleDTO) Validate() error {
+	var v Validator
+	v.Check(d.RatePercent > 0 && d.RatePercent <= 200, "rate_percent", CodeOutOfRange, "rate_percent must be between 0 and 200")
+	v.Check(d.ListingSplitPercent >= 0 && d.ListingSplitPercent <= 100, "listing_split_percent", CodeOutOfRange, "listing_split_percent must be between 0 and 100")
+	v.Check(d.AgencySharePercent >= 0 && d.AgencySharePercent <= 100, "agency_share_percent", CodeOutOfRange, "agency_share_percent must be between 0 and 100")
+	v.Check(d.IVAPercent >= 0 && d.IVAPercent <= 100, "iva_percent", CodeOutOfRange, "iva_percent must be between 0 and 100")
+	return v.Err()
+}
+
+// SettleCommissionDTO liquida manualmente el cierre de una propiedad
+type SettleCommissionDTO struct {
+	SellingAgentID *string `json:"selling_agent_id"` // Opcional; por defecto el agente captador
+}
+
+// Validate valida los campos del DTO
+func (d *SettleCommissionDTO) Validate() error {
+	var v Validator
+	validateAgentID(&v, d.SellingAgentID)
+	return v.Err()
+}
+
+func validateAgentID(v *Validator, id *string) {
+	if id == nil {
+		return
+	}
+	_, err := uuid.Parse(*id)
+	v.Check(err == nil, "selling_agent_id", CodeInvalidFormat, "selling_agent_id must be a UUID")
+}
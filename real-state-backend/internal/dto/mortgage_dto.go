@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"slices"
+)
+
+// MortgageCalculationDTO son los parámetros de la calculadora hipotecaria.
+// Se debe enviar property_id o price.
+type MortgageCalculationDTO struct {
+	PropertyID    int64    `json:"property_id"`
+	Price         float64  `json:"price"`
+	Currency      string   `json:"currency"`
+	DownPayment   float64  `json:"down_payment"`
+	AnnualRate    *float64 `json:"annual_rate"` // 0.085 = 8.5%
+	TermYears     int      `json:"term_years"`
+	ProductID     int64    `json:"product_id"`
+	MonthlyIncome float64  `json:"monthly_income"`
+	MonthlyDebts  float64  `json:"monthly_debts"`
+}
+
+// Validate valida los campos del DTO
+func (d *MortgageCalculationDTO) Validate() error {
+	var v Validator
+	v.Check(d.PropertyID != 0 || d.Price > 0, "price", CodeRequired, "property_id or price is required")
+	v.Check(d.PropertyID == 0 || d.Price == 0, "price", CodeConflict, "send either property_id or price, not both")
+	v.Check(d.Currency == "" || slices.Contains([]string{"USD", "GTQ"}, d.Currency), "currency", CodeInvalidChoice, "invalid currency")
+	v.Check(d.DownPayment >= 0, "down_payment", CodeOutOfRange, "down_payment must not be negative")
+	v.Check(d.AnnualRate != nil || d.ProductID != 0, "annual_rate", CodeRequired, "annual_rate or product_id is required")
+	if d.TermYears < 0 || d.TermYears > 40 {
+		v.Add("term_years", CodeOutOfRange, "term_years must be between 1 and 40")
+	} else {
+		v.Check(d.TermYears != 0 || d.ProductID != 0, "term_years", CodeRequired, "term_years or product_id is required")
+	}
+	v.Check(d.MonthlyIncome >= 0, "monthly_income", CodeOutOfRange, "monthly_income must not be negative")
+	v.Check(d.MonthlyDebts >= 0, "monthly_debts", CodeOutOfRange, "monthly_debts must not be negative")
+	return v.Err()
+}
+
+// MortgageProductDTO crea o actualiza un producto hipotecario
+type MortgageProductDTO struct {
+	BankName           string   `json:"bank_name"`
+	ProductName        string   `json:"product_name"`
+	Currency           string   `json:"currency"`
+	AnnualRate         float64  `json:"annual_rate"`
+	MaxTermYears       int      `json:"max_term_years"`
+	MinDownPaymentPct  float64  `json:"min_down_payment_pct"`
+	MaxPaymentToIncome *float64 `json:"max_payment_to_income"`
+	Active             *bool    `json:"active"`
+}
+
+// Validate valida los campos del DTO
+func (d *MortgageProductDTO) Validate() error {
+	var v Validator
+	v.Check(d.BankName != "", "bank_name", CodeRequired, "bank_name is required")
+	v.Check(d.ProductName != "", "product_name", CodeRequired, "product_name is required")
+	v.Check(slices.Contains([]string{"USD", "GTQ"}, d.Currency), "currency", CodeInvalidChoice, "invalid currency")
+	v.Check(d.AnnualRate >= 0 && d.AnnualRate < 1, "annual_rate", CodeOutOfRange, "annual_rate must be a fraction between 0 and 1")
+	v.Check(d.MaxTermYears >= 1 && d.MaxTermYears <= 40, "max_term_years", CodeOutOfRange, "max_term_years must be between 1 and 40")
+	v.Check(d.MinDownPaymentPct >= 0 && d.MinDownPaymentPct <= 1, "min_down_payment_pct", CodeOutOfRange, "min_down_payment_pct must be between 0 and 1")
+	v.Check(d.MaxPaymentToIncome == nil || (*d.MaxPaymentToIncome > 0 && *d.MaxPaymentToIncome <= 1), "max_payment_to_income", CodeOutOfRange, "max_payment_to_income must be between 0 and 1")
+	return v.Err()
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"slices"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// UpdateNotificationPreferencesDTO modifica las preferencias del usuario (campos omitidos no cambian)
+type UpdateNotificationPreferencesDTO struct {
+	Language     *string  `json:"language"` // es o en
+	EmailEnabled *bool    `json:"email_enabled"`
+	MutedKinds   []string `json:"muted_kinds"` // Lista completa; [] reactiva todos
+}
+
+// Validate valida los campos del DTO
+func (d *UpdateNotificationPreferencesDTO) Validate() error {
+	var v Validator
+	if d.Language != nil {
+		v.Check(slices.Contains(domain.SupportedLanguages, *d.Language), "language", CodeInvalidChoice, "invalid language")
+	}
+	for i, kind := range d.MutedKinds {
+		if !slices.Contains(domain.NotificationKinds, kind) {
+			v.Add("muted_kinds", CodeInvalidChoice, "invalid notification kind "+kind)
+		} else if slices.Contains(d.MutedKinds[:i], kind) {
+			v.Add("muted_kinds", CodeConflict, "repeated notification kind "+kind)
+		}
+	}
+	return v.Err()
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"slices"
+	"time"
+)
+
+// maxOfferTextLength limita condiciones y mensajes del hilo de negociación
+const maxOfferTextLength = 2000
+
+// OfferTermsDTO son los términos de una oferta o contraoferta
+type OfferTermsDTO struct {
+	Amount     float64    `json:"amount"`
+	Currency   string     `json:"currency"`   // USD o GTQ
+	Conditions string     `json:"conditions"` // Forma de pago, plazos, financiamiento...
+	ExpiresAt  *time.Time `json:"expires_at"` // RFC 3339, máximo 30 días
+	Message    string     `json:"message"`    // Opcional, se agrega al hilo
+}
+
+// Validate valida los campos del DTO
+func (d *OfferTermsDTO) Validate() error {
+	var v Validator
+	v.Check(d.Amount > 0, "amount", CodeOutOfRange, "amount must be positive")
+	v.Check(slices.Contains([]string{"USD", "GTQ"}, d.Currency), "currency", CodeInvalidChoice, "invalid currency")
+	v.Check(len(d.Conditions) <= maxOfferTextLength, "conditions", CodeTooLong, "conditions must be at most 2000 characters")
+	v.Check(d.ExpiresAt != nil, "expires_at", CodeRequired, "expires_at is required")
+	v.Check(len(d.Message) <= maxOfferTextLength, "message", CodeTooLong, "message must be at most 2000 characters")
+	return v.Err()
+}
+
+// OfferMessageDTO es un mensaje del hilo o el comentario de una respuesta (aceptar, rechazar, retirar)
+type OfferMessageDTO struct {
+	Message string `json:"message"`
+}
+
+// Validate valida los campos del DTO; required exige el mensaje
+func (d *OfferMessageDTO) Validate(required bool) error {
+	var v Validator
+	v.Check(!required || d.Message != "", "message", CodeRequired, "message is required")
+	v.Check(len(d.Message) <= maxOfferTextLength, "message", CodeTooLong, "message must be at most 2000 characters")
+	return v.Err()
+}
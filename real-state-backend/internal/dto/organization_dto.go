@@ -0,0 +1,154 @@
+package dto
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// slugPattern: minúsculas, dígitos y guiones simples (ej. "inmobiliaria-centro")
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CreateOrganizationDTO registra una agencia
+type CreateOrganizationDTO struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Validate valida los campos del DTO
+func (d *CreateOrganizationDTO) Validate() error {
+	var v Validator
+	d.Name = strings.TrimSpace(d.Name)
+	d.Slug = strings.TrimSpace(d.Slug)
+	validateOrganizationName(&v, d.Name)
+	if d.Slug == "" {
+		v.Add("slug", CodeRequired, "slug is required")
+	} else {
+		v.Check(len(d.Slug) <= 60, "slug", CodeTooLong, "slug must have at most 60 characters")
+		v.Check(slugPattern.MatchString(d.Slug), "slug", CodeInvalidFormat, "slug must contain lowercase letters, digits and single hyphens")
+	}
+	return v.Err()
+}
+
+// UpdateOrganizationDTO modifica nombre o estado de una agencia (campos omitidos no cambian)
+type UpdateOrganizationDTO struct {
+	Name   *string `json:"name"`
+	Active *bool   `json:"active"`
+}
+
+// Validate valida los campos del DTO
+func (d *UpdateOrganizationDTO) Validate() error {
+	var v Validator
+	if d.Name != nil {
+		*d.Name = strings.TrimSpace(*d.Name)
+		validateOrganizationName(&v, *d.Name)
+	}
+	return v.Err()
+}
+
+func validateOrganizationName(v *Validator, name string) {
+	if name == "" {
+		v.Add("name", CodeRequired, "name is required")
+		return
+	}
+	v.Check(utf8.RuneCountInString(name) <= 120, "name", CodeTooLong, "name must have at most 120 characters")
+}
+
+// CreateUserDTO registra un usuario en una agencia. Para el primer administrador
+// de una agencia role_ids se ignora (siempre recibe agency_admin).
+type CreateUserDTO struct {
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Password string   `json:"password"`
+	RoleIDs  []string `json:"role_ids"`
+}
+
+// Validate valida los campos del DTO
+func (d *CreateUserDTO) Validate() error {
+	var v Validator
+	d.Username = strings.TrimSpace(d.Username)
+	d.Email = strings.TrimSpace(d.Email)
+	if d.Username == "" {
+		v.Add("username", CodeRequired, "username is required")
+	} else {
+		v.Check(len(d.Username) >= 3, "username", CodeTooShort, "username must have at least 3 characters")
+		v.Check(len(d.Username) <= 50, "username", CodeTooLong, "username must have at most 50 characters")
+	}
+	if d.Email == "" {
+		v.Add("email", CodeRequired, "email is required")
+	} else {
+		_, err := mail.ParseAddress(d.Email)
+		v.Check(err == nil && !strings.ContainsAny(d.Email, "<> "), "email", CodeInvalidFormat, "email is not valid")
+		v.Check(len(d.Email) <= 100, "email", CodeTooLong, "email must have at most 100 characters")
+	}
+	validatePassword(&v, d.Password)
+	validateRoleIDs(&v, d.RoleIDs)
+	return v.Err()
+}
+
+func validatePassword(v *Validator, password string) {
+	if password == "" {
+		v.Add("password", CodeRequired, "password is required")
+		return
+	}
+	// bcrypt solo usa los primeros 72 bytes (incluido el pepper)
+	v.Check(utf8.RuneCountInString(password) >= 12, "password", CodeTooShort, "password must have at least 12 characters")
+	v.Check(len(password) <= 64, "password", CodeTooLong, "password must have at most 64 bytes")
+}
+
+// SetUserRolesDTO reemplaza los roles de un usuario
+type SetUserRolesDTO struct {
+	RoleIDs []string `json:"role_ids"`
+}
+
+// Validate valida los campos del DTO
+func (d *SetUserRolesDTO) Validate() error {
+	var v Validator
+	v.Check(d.RoleIDs != nil, "role_ids", CodeRequired, "role_ids is required (use [] to remove all roles)")
+	validateRoleIDs(&v, d.RoleIDs)
+	return v.Err()
+}
+
+func validateRoleIDs(v *Validator, ids []string) {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			v.Add("role_ids", CodeInvalidFormat, "role_ids must contain UUIDs")
+			return
+		}
+	}
+}
+
+// CreateRoleDTO registra un rol de la agencia con permisos por nombre
+type CreateRoleDTO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Validate valida los campos del DTO
+func (d *CreateRoleDTO) Validate() error {
+	var v Validator
+	d.Name = strings.TrimSpace(d.Name)
+	d.Description = strings.TrimSpace(d.Description)
+	if d.Name == "" {
+		v.Add("name", CodeRequired, "name is required")
+	} else {
+		v.Check(len(d.Name) <= 50, "name", CodeTooLong, "name must have at most 50 characters")
+	}
+	v.Check(utf8.RuneCountInString(d.Description) <= 500, "description", CodeTooLong, "description must have at most 500 characters")
+	if len(d.Permissions) == 0 {
+		v.Add("permissions", CodeRequired, "permissions is required")
+	}
+	seen := make(map[string]bool, len(d.Permissions))
+	for _, name := range d.Permissions {
+		if strings.TrimSpace(name) == "" || seen[name] {
+			v.Add("permissions", CodeInvalidFormat, "permissions must contain distinct permission names")
+			break
+		}
+		seen[name] = true
+	}
+	return v.Err()
+}
@@ -1,19 +1,42 @@
 package dto
 
 import (
-	"errors"
+	"net/url"
 	"slices"
+	"strings"
+
+	"real-state-backend/internal/core/domain"
 )
 
 // CreatePropertyDTO define la estructura de datos que esperamos del móvil
 // Usamos etiquetas `json` para que Go sepa cómo mapear el cuerpo del request.
 type CreatePropertyDTO struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Currency    string  `json:"currency"`
-	Location    string  `json:"location"`
-	Type        string  `json:"type"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	Currency    string   `json:"currency"`
+	Location    string   `json:"location"` // Dirección libre
+	Department  string   `json:"department"`
+	City        string   `json:"city"` // Municipio, validado contra el catálogo
+	Zone        int      `json:"zone"`
+	Type        string   `json:"type"`
+	Bedrooms    int      `json:"bedrooms"`
+	Bathrooms   int      `json:"bathrooms"`
+	Amenities   []string `json:"amenities"` // parking, garden, pool, security, ...
+	Area        float64  `json:"area"`
+	AreaUnit    string   `json:"area_unit"` // m2 (default), v2 o mz
+	Lat         *float64 `json:"lat"`       // Opcional; sin coordenadas se geocodifica la dirección
+	Lng         *float64 `json:"lng"`
+	MainImage   string   `json:"main_image"` // URL http(s) de la imagen principal
+	Status      string   `json:"status"`     // draft o published (default)
+	Language    string   `json:"language"`   // Idioma del título/descripción: es (default) o en
+	// Datos del Registro General de la Propiedad (finca, folio y libro van juntos) y matrícula IUSI
+	RegistryFinca string `json:"registry_finca"`
+	RegistryFolio string `json:"registry_folio"`
+	RegistryLibro string `json:"registry_libro"`
+	RegistryIUSI  string `json:"registry_iusi"`
+	// ConfirmDuplicate permite crear la propiedad aunque se detecten posibles duplicados
+	ConfirmDuplicate bool `json:"confirm_duplicate"`
 }
 
 // IsValid realiza una validación básica de seguridad de los datos de entrada
@@ -25,25 +48,212 @@ func (d *CreatePropertyDTO) IsValid() bool {
 	return true
 }
 func (d *CreatePropertyDTO) Validate() error {
+	var v Validator
 	if d.Title == "" {
-		return errors.New("title is required")
-	}
-	if len(d.Title) < 3 {
-		return errors.New("title must be at least 3 characters")
-	}
-	if d.Price <= 0 {
-		return errors.New("price must be positive")
+		v.Add("title", CodeRequired, "title is required")
+	} else {
+		v.Check(len(d.Title) >= 3, "title", CodeTooShort, "title must be at least 3 characters")
 	}
+	v.Check(d.Price > 0, "price", CodeOutOfRange, "price must be positive")
 	// Validar moneda
 	allowedCurrencies := []string{"USD", "GTQ"}
-	if !slices.Contains(allowedCurrencies, d.Currency) {
-		return errors.New("invalid currency")
-	}
+	v.Check(slices.Contains(allowedCurrencies, d.Currency), "currency", CodeInvalidChoice, "invalid currency")
 	// Validar tipo de propiedad
 	allowedTypes := []string{"Casa", "Apartamento", "Terreno", "Oficina"}
-	if !slices.Contains(allowedTypes, d.Type) {
-		return errors.New("invalid type")
+	v.Check(slices.Contains(allowedTypes, d.Type), "type", CodeInvalidChoice, "invalid type")
+	// Ubicación: la zona y el departamento requieren municipio
+	v.Check(d.City != "" || (d.Zone == 0 && d.Department == ""), "city", CodeRequired, "city is required when department or zone are sent")
+	v.Check(d.Zone >= 0 && d.Zone <= 25, "zone", CodeOutOfRange, "zone must be between 1 and 25")
+	// Validar área y unidad (obligatoria para terrenos)
+	v.Check(slices.Contains(allowedAreaUnits, d.AreaUnit), "area_unit", CodeInvalidChoice, "invalid area_unit")
+	v.Check(d.Area >= 0, "area", CodeOutOfRange, "area must not be negative")
+	if d.Type == "Terreno" && d.Area == 0 {
+		v.Add("area", CodeRequired, "area is required for Terreno")
+	}
+	// Habitaciones y baños según el tipo
+	addViolations(&v, domain.RoomViolations(d.Type, d.Bedrooms, d.Bathrooms))
+	addViolations(&v, domain.AmenityViolations(d.Amenities))
+	validateRegistry(&v, &d.RegistryFinca, &d.RegistryFolio, &d.RegistryLibro, &d.RegistryIUSI)
+	if (d.Lat == nil) != (d.Lng == nil) {
+		v.Add("lat", CodeConflict, "lat and lng must be sent together")
+	} else if d.Lat != nil {
+		v.Check(inGuatemala(*d.Lat, *d.Lng), "lat", CodeOutOfRange, "coordinates must be inside Guatemala")
+	}
+	v.Check(d.MainImage == "" || isImageURL(d.MainImage), "main_image", CodeInvalidFormat, "main_image must be an http(s) URL")
+	v.Check(d.Status == "" || d.Status == "draft" || d.Status == "published", "status", CodeInvalidChoice, "status must be draft or published")
+	v.Check(d.Language == "" || slices.Contains(domain.SupportedLanguages, d.Language), "language", CodeInvalidChoice, "invalid language")
+	return v.Err()
+}
+
+// allowedAreaUnits son las unidades de área aceptadas (vacío equivale a m²)
+var allowedAreaUnits = []string{"", "m2", "v2", "mz"}
+
+// addViolations registra las reglas de negocio incumplidas que reporta el dominio
+func addViolations(v *Validator, violations []domain.FieldViolation) {
+	for _, f := range violations {
+		v.Add(f.Field, f.Reason, f.Message)
+	}
+}
+
+// validateRegistry valida el formato de los datos registrales enviados (nil = no enviado).
+// Finca, folio y libro deben enviarse juntos; un valor vacío los elimina.
+func validateRegistry(v *Validator, finca, folio, libro, iusi *string) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"registry_finca", finca},
+		{"registry_folio", folio},
+		{"registry_libro", libro},
+		{"registry_iusi", iusi},
+	}
+	sent, filled := 0, 0
+	for i, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		value := strings.ToUpper(strings.TrimSpace(*f.value))
+		if i < 3 {
+			sent++
+			if value != "" {
+				filled++
+			}
+		}
+		v.Check(value == "" || domain.ValidRegistryValue(f.name, value), f.name, CodeInvalidFormat, "invalid "+f.name+" format")
+	}
+	if (sent != 0 && sent != 3) || (filled != 0 && filled != 3) {
+		v.Add("registry_finca", CodeConflict, "registry_finca, registry_folio and registry_libro must be sent together")
+	}
+}
+
+// inGuatemala verifica que la coordenada caiga en la caja envolvente del país
+func inGuatemala(lat, lng float64) bool {
+	return lat >= 13.6 && lat <= 17.9 && lng >= -92.3 && lng <= -88.1
+}
+
+// isImageURL acepta solo URLs absolutas http(s)
+func isImageURL(raw string) bool {
+	if len(raw) > 500 {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// UpdatePropertyDTO permite modificar parcialmente una propiedad; los campos nulos no cambian
+type UpdatePropertyDTO struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Price       *float64  `json:"price"`
+	Currency    *string   `json:"currency"`
+	Location    *string   `json:"location"`
+	Department  *string   `json:"department"`
+	City        *string   `json:"city"`
+	Zone        *int      `json:"zone"`
+	Type        *string   `json:"type"`
+	Bedrooms    *int      `json:"bedrooms"`
+	Bathrooms   *int      `json:"bathrooms"`
+	Amenities   *[]string `json:"amenities"` // Reemplaza la lista completa
+	MainImage   *string   `json:"main_image"`
+	Area        *float64  `json:"area"`
+	AreaUnit    string    `json:"area_unit"` // Unidad de "area": m2 (default), v2 o mz
+	// Finca, folio y libro se envían juntos; "" elimina el dato
+	RegistryFinca *string `json:"registry_finca"`
+	RegistryFolio *string `json:"registry_folio"`
+	RegistryLibro *string `json:"registry_libro"`
+	RegistryIUSI  *string `json:"registry_iusi"`
+}
+
+// Validate valida los campos enviados
+func (d *UpdatePropertyDTO) Validate() error {
+	var v Validator
+	v.Check(d.Title == nil || len(*d.Title) >= 3, "title", CodeTooShort, "title must be at least 3 characters")
+	v.Check(d.Price == nil || *d.Price > 0, "price", CodeOutOfRange, "price must be positive")
+	v.Check(d.Currency == nil || slices.Contains([]string{"USD", "GTQ"}, *d.Currency), "currency", CodeInvalidChoice, "invalid currency")
+	v.Check(d.Location == nil || *d.Location != "", "location", CodeRequired, "location must not be empty")
+	v.Check(d.City == nil || *d.City != "", "city", CodeRequired, "city must not be empty")
+	v.Check(d.Zone == nil || (*d.Zone >= 0 && *d.Zone <= 25), "zone", CodeOutOfRange, "zone must be between 1 and 25")
+	v.Check(d.Type == nil || slices.Contains([]string{"Casa", "Apartamento", "Terreno", "Oficina"}, *d.Type), "type", CodeInvalidChoice, "invalid type")
+	v.Check(slices.Contains(allowedAreaUnits, d.AreaUnit), "area_unit", CodeInvalidChoice, "invalid area_unit")
+	v.Check(d.Area == nil || *d.Area >= 0, "area", CodeOutOfRange, "area must not be negative")
+	// Sin el tipo solo se valida el rango; las reglas por tipo las aplica el dominio al guardar
+	if d.Bedrooms != nil || d.Bathrooms != nil {
+		propertyType, bedrooms, bathrooms := "", 0, 0
+		if d.Type != nil {
+			propertyType = *d.Type
+		}
+		if d.Bedrooms != nil {
+			bedrooms = *d.Bedrooms
+		}
+		if d.Bathrooms != nil {
+			bathrooms = *d.Bathrooms
+		}
+		addViolations(&v, domain.RoomViolations(propertyType, bedrooms, bathrooms))
+	}
+	v.Check(d.MainImage == nil || *d.MainImage == "" || isImageURL(*d.MainImage), "main_image", CodeInvalidFormat, "main_image must be an http(s) URL")
+	if d.Amenities != nil {
+		addViolations(&v, domain.AmenityViolations(*d.Amenities))
 	}
-	return nil
+	validateRegistry(&v, d.RegistryFinca, d.RegistryFolio, d.RegistryLibro, d.RegistryIUSI)
+	return v.Err()
+}
+
+// UpdatePropertyStatusDTO cambia el estado de publicación de una propiedad
+type UpdatePropertyStatusDTO struct {
+	Status string `json:"status"`
+	// SellingAgentID es el agente que cerró la operación (sold/rented); por defecto el captador
+	SellingAgentID *string `json:"selling_agent_id"`
+}
+
+// Validate valida los campos del DTO
+func (d *UpdatePropertyStatusDTO) Validate() error {
+	var v Validator
+	allowedStatuses := []string{"draft", "published", "reserved", "sold", "rented", "withdrawn"}
+	v.Check(slices.Contains(allowedStatuses, d.Status), "status", CodeInvalidChoice, "invalid status")
+	v.Check(d.SellingAgentID == nil || d.Status == "sold" || d.Status == "rented", "selling_agent_id", CodeConflict, "selling_agent_id only applies to sold or rented")
+	validateAgentID(&v, d.SellingAgentID)
+	return v.Err()
+}
+
+// UpdateCoordinatesDTO fija manualmente las coordenadas de una propiedad
+type UpdateCoordinatesDTO struct {
+	Lat *float64 `json:"lat"`
+	Lng *float64 `json:"lng"`
+}
+
+// Validate valida los campos del DTO
+func (d *UpdateCoordinatesDTO) Validate() error {
+	var v Validator
+	v.Check(d.Lat != nil, "lat", CodeRequired, "lat is required")
+	v.Check(d.Lng != nil, "lng", CodeRequired, "lng is required")
+	if d.Lat != nil && d.Lng != nil {
+		v.Check(*d.Lat >= -90 && *d.Lat <= 90 && *d.Lng >= -180 && *d.Lng <= 180, "lat", CodeOutOfRange, "coordinates out of range")
+		v.Check(*d.Lat != 0 || *d.Lng != 0, "lat", CodeInvalidFormat, "invalid coordinates")
+	}
+	return v.Err()
+}
+
+// PropertyTranslationDTO es el título y la descripción en otro idioma
+type PropertyTranslationDTO struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Validate valida los campos del DTO
+func (d *PropertyTranslationDTO) Validate() error {
+	var v Validator
+	v.Check(len(d.Title) >= 3, "title", CodeTooShort, "title must be at least 3 characters")
+	return v.Err()
+}
+
+// CreateShareLinkDTO define la vigencia del enlace público (0 = 72 horas)
+type CreateShareLinkDTO struct {
+	ExpiresInHours int `json:"expires_in_hours"`
+}
 
+// Validate valida los campos del DTO
+func (d *CreateShareLinkDTO) Validate() error {
+	var v Validator
+	v.Check(d.ExpiresInHours >= 0 && d.ExpiresInHours <= 720, "expires_in_hours", CodeOutOfRange, "expires_in_hours must be between 1 and 720")
+	return v.Err()
 }
@@ -6,6 +6,7 @@ type ErrorResponse struct {
 	Code      int                    `json:"code"`
 	Message   string                 `json:"message"`
 	ErrorCode string                 `json:"error_code"`
+	Errors    []FieldError           `json:"errors,omitempty"` // Violaciones por campo (validation_error)
 	Meta      map[string]interface{} `json:"meta"`
 	TraceID   string                 `json:"trace_id"`
 }
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// CreateSavedSearchDTO guarda una búsqueda; sin criterios avisa de toda propiedad nueva
+type CreateSavedSearchDTO struct {
+	Name     string                     `json:"name"`
+	Criteria domain.SavedSearchCriteria `json:"criteria"`
+}
+
+// Validate valida los campos del DTO
+func (d *CreateSavedSearchDTO) Validate() error {
+	var v Validator
+	d.Name = strings.TrimSpace(d.Name)
+	if d.Name == "" {
+		v.Add("name", CodeRequired, "name is required")
+	} else {
+		v.Check(utf8.RuneCountInString(d.Name) <= 100, "name", CodeTooLong, "name must have at most 100 characters")
+	}
+
+	c := &d.Criteria
+	propertyTypes := []string{domain.PropertyTypeHouse, domain.PropertyTypeApartment, domain.PropertyTypeLand, domain.PropertyTypeOffice}
+	v.Check(c.Type == "" || slices.Contains(propertyTypes, c.Type), "criteria.type", CodeInvalidChoice, "invalid type")
+	v.Check(c.DepartmentID >= 0 && c.MunicipalityID >= 0 && c.ZoneID >= 0, "criteria", CodeOutOfRange, "location ids must be positive")
+	v.Check(c.Currency == "" || slices.Contains([]string{"USD", "GTQ"}, c.Currency), "criteria.currency", CodeInvalidChoice, "invalid currency")
+	v.Check(c.MinPrice >= 0 && c.MaxPrice >= 0, "criteria", CodeOutOfRange, "prices must not be negative")
+	// Un rango de precio sin moneda compararía quetzales con dólares
+	v.Check(c.Currency != "" || (c.MinPrice == 0 && c.MaxPrice == 0), "criteria.currency", CodeRequired, "currency is required with a price range")
+	v.Check(c.MaxPrice == 0 || c.MinPrice <= c.MaxPrice, "criteria.max_price", CodeConflict, "max_price must be greater than min_price")
+	v.Check(c.MinBedrooms >= 0 && c.MinBedrooms <= domain.MaxRooms, "criteria.min_bedrooms", CodeOutOfRange, "min_bedrooms is out of range")
+	v.Check(c.MinAreaSqM >= 0 && c.MaxAreaSqM >= 0, "criteria", CodeOutOfRange, "areas must not be negative")
+	v.Check(c.MaxAreaSqM == 0 || c.MinAreaSqM <= c.MaxAreaSqM, "criteria.max_area_sqm", CodeConflict, "max_area_sqm must be greater than min_area_sqm")
+	for _, f := range domain.AmenityViolations(c.Amenities) {
+		v.Add("criteria."+f.Field, f.Reason, f.Message)
+	}
+	return v.Err()
+}
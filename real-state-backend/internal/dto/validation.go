@@ -0,0 +1,67 @@
+package dto
+
+import "strings"
+
+// Códigos de error de validación por campo
+const (
+	CodeRequired      = "required"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidChoice = "invalid_choice"
+	CodeInvalidFormat = "invalid_format"
+	CodeConflict      = "conflict" // Combinación de campos no permitida
+)
+
+// FieldError describe una violación sobre un campo del request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors son todas las violaciones de un request
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator acumula las violaciones en lugar de detenerse en la primera
+type Validator struct {
+	errors ValidationErrors
+}
+
+// Add registra una violación
+func (v *Validator) Add(field, code, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Check registra la violación si la condición no se cumple
+func (v *Validator) Check(ok bool, field, code, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Has indica si el campo ya tiene una violación (para no repetir reglas dependientes)
+func (v *Validator) Has(field string) bool {
+	for _, e := range v.errors {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err retorna ValidationErrors, o nil si no hubo violaciones
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
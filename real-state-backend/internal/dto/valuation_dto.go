@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"slices"
+)
+
+// ValuationRequestDTO describe el inmueble a valuar
+type ValuationRequestDTO struct {
+	Type     string   `json:"type"`
+	City     string   `json:"city"`
+	AreaSqM  float64  `json:"area_sqm"`
+	AreaUnit string   `json:"area_unit"` // Unidad de area_sqm: m2 (default), v2 o mz
+	Bedrooms int      `json:"bedrooms"`
+	Lat      *float64 `json:"lat"`
+	Lng      *float64 `json:"lng"`
+	RadiusKm float64  `json:"radius_km"`
+	Currency string   `json:"currency"` // Moneda de la estimación (default USD)
+}
+
+// Validate valida los campos del DTO
+func (d *ValuationRequestDTO) Validate() error {
+	var v Validator
+	allowedTypes := []string{"Casa", "Apartamento", "Terreno", "Oficina"}
+	v.Check(slices.Contains(allowedTypes, d.Type), "type", CodeInvalidChoice, "invalid type")
+	v.Check(d.AreaSqM > 0, "area_sqm", CodeOutOfRange, "area_sqm must be positive")
+	v.Check(slices.Contains(allowedAreaUnits, d.AreaUnit), "area_unit", CodeInvalidChoice, "invalid area_unit")
+	v.Check(d.Bedrooms >= 0, "bedrooms", CodeOutOfRange, "bedrooms must not be negative")
+	switch {
+	case (d.Lat == nil) != (d.Lng == nil):
+		v.Add("lat", CodeConflict, "lat and lng must be sent together")
+	case d.Lat == nil:
+		v.Check(d.City != "", "city", CodeRequired, "city or coordinates are required")
+	default:
+		v.Check(*d.Lat >= -90 && *d.Lat <= 90 && *d.Lng >= -180 && *d.Lng <= 180, "lat", CodeOutOfRange, "invalid coordinates")
+	}
+	v.Check(d.RadiusKm >= 0 && d.RadiusKm <= 50, "radius_km", CodeOutOfRange, "radius_km must be between 0 and 50")
+	if d.Currency == "" {
+		d.Currency = "USD"
+	}
+	allowedCurrencies := []string{"USD", "GTQ"}
+	v.Check(slices.Contains(allowedCurrencies, d.Currency), "currency", CodeInvalidChoice, "invalid currency")
+	return v.Err()
+}
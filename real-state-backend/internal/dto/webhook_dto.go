@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// CreateWebhookDTO registra una suscripción; el secreto de firma se genera en el servidor
+type CreateWebhookDTO struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+}
+
+// Validate valida los campos del DTO
+func (d *CreateWebhookDTO) Validate() error {
+	var v Validator
+	d.URL = strings.TrimSpace(d.URL)
+	d.Description = strings.TrimSpace(d.Description)
+	validateWebhookURL(&v, d.URL)
+	v.Check(utf8.RuneCountInString(d.Description) <= 200, "description", CodeTooLong, "description must have at most 200 characters")
+	validateWebhookEventTypes(&v, d.EventTypes)
+	return v.Err()
+}
+
+// UpdateWebhookDTO modifica una suscripción (campos omitidos no cambian)
+type UpdateWebhookDTO struct {
+	URL          *string  `json:"url"`
+	Description  *string  `json:"description"`
+	EventTypes   []string `json:"event_types"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"` // Genera un secreto nuevo y lo retorna una sola vez
+}
+
+// Validate valida los campos del DTO
+func (d *UpdateWebhookDTO) Validate() error {
+	var v Validator
+	if d.URL != nil {
+		*d.URL = strings.TrimSpace(*d.URL)
+		validateWebhookURL(&v, *d.URL)
+	}
+	if d.Description != nil {
+		*d.Description = strings.TrimSpace(*d.Description)
+		v.Check(utf8.RuneCountInString(*d.Description) <= 200, "description", CodeTooLong, "description must have at most 200 characters")
+	}
+	if d.EventTypes != nil {
+		validateWebhookEventTypes(&v, d.EventTypes)
+	}
+	return v.Err()
+}
+
+// validateWebhookURL exige una URL absoluta http(s). El servicio rechaza además los
+// destinos que resuelven a direcciones privadas o locales.
+func validateWebhookURL(v *Validator, raw string) {
+	if raw == "" {
+		v.Add("url", CodeRequired, "url is required")
+		return
+	}
+	u, err := url.Parse(raw)
+	v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.User == nil,
+		"url", CodeInvalidFormat, "url must be an absolute http or https URL without credentials")
+	v.Check(len(raw) <= 500, "url", CodeTooLong, "url must have at most 500 characters")
+}
+
+// webhookEventTypes son los eventos suscribibles; deben coincidir con los eventos de domain/outbox.go
+var webhookEventTypes = []string{"property.created", "property.updated", "property.status_changed", "lead.created"}
+
+// validateWebhookEventTypes exige al menos un evento y rechaza desconocidos o repetidos
+func validateWebhookEventTypes(v *Validator, eventTypes []string) {
+	if len(eventTypes) == 0 {
+		v.Add("event_types", CodeRequired, "event_types is required")
+		return
+	}
+	for i, t := range eventTypes {
+		if !slices.Contains(webhookEventTypes, t) {
+			v.Add("event_types", CodeInvalidChoice, "invalid event type "+t)
+		} else if slices.Contains(eventTypes[:i], t) {
+			v.Add("event_types", CodeConflict, "repeated event type "+t)
+		}
+	}
+}
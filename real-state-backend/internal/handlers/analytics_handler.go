@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+	"real-state-backend/internal/services"
+)
+
+type AnalyticsHandler struct {
+	service         ports.AnalyticsService
+	propertyService ports.PropertyService
+}
+
+// NewAnalyticsHandler crea el handler de analítica de propiedades
+func NewAnalyticsHandler(s ports.AnalyticsService, ps ports.PropertyService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: s, propertyService: ps}
+}
+
+// AddFavorite registra que el usuario marcó la propiedad como favorita
+func (h *AnalyticsHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.existingPropertyID(w, r)
+	if !ok {
+		return
+	}
+
+	event := viewerEvent(r, id)
+	event.EventType = domain.PropertyEventFavorite
+	if err := h.service.RecordEvent(r.Context(), &event); err != nil {
+		slog.Error("Error recording favorite", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al registrar favorito", "analytics_error", "analytics", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Favorite recorded"})
+}
+
+// CreateInquiry registra una consulta sobre la propiedad
+func (h *AnalyticsHandler) CreateInquiry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.existingPropertyID(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CreateInquiryDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "analytics", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "analytics")
+		return
+	}
+
+	event := viewerEvent(r, id)
+	event.EventType = domain.PropertyEventInquiry
+	event.Metadata = map[string]interface{}{
+		"message": input.Message,
+		"contact": input.Contact,
+	}
+	if err := h.service.RecordEvent(r.Context(), &event); err != nil {
+		slog.Error("Error recording inquiry", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al registrar consulta", "analytics_error", "analytics", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Inquiry recorded"})
+}
+
+// GetPropertyStats retorna la serie diaria de vistas, favoritos y consultas.
+// Acepta ?from=YYYY-MM-DD&to=YYYY-MM-DD (por defecto los últimos 30 días).
+func (h *AnalyticsHandler) GetPropertyStats(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "analytics", nil)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Rango de fechas inválido", "invalid_date_range", "analytics", nil)
+		return
+	}
+
+	stats, err := h.service.GetDailyStats(r.Context(), id, from, to)
+	if err != nil {
+		if writeDateRangeError(w, err) {
+			return
+		}
+		slog.Error("Error loading property stats", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al consultar la analítica", "analytics_error", "analytics", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"property_id": id,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+		"daily":       stats,
+	})
+}
+
+// GetTopViewed retorna el ranking de propiedades más vistas en el rango (?limit=10)
+func (h *AnalyticsHandler) GetTopViewed(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Rango de fechas inválido", "invalid_date_range", "analytics", nil)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	ranking, err := h.service.GetTopViewed(r.Context(), from, to, limit)
+	if err != nil {
+		if writeDateRangeError(w, err) {
+			return
+		}
+		slog.Error("Error loading top viewed properties", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al consultar la analítica", "analytics_error", "analytics", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// existingPropertyID valida el ID de la ruta y que la propiedad exista
+func (h *AnalyticsHandler) existingPropertyID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "analytics", nil)
+		return 0, false
+	}
+	if _, err := h.propertyService.GetProperty(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrPropertyNotFound) {
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "analytics", nil)
+			return 0, false
+		}
+		slog.Error("Error loading property", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error de base de datos", "db_error", "analytics", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeDateRangeError responde 400 si el servicio rechazó el rango de fechas
+func writeDateRangeError(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, services.ErrInvalidDateRange) && !errors.Is(err, services.ErrDateRangeTooLarge) {
+		return false
+	}
+	writeError(w, http.StatusBadRequest, err.Error(), "invalid_date_range", "analytics", nil)
+	return true
+}
+
+// viewerEvent construye un evento identificando al usuario y su dispositivo
+func viewerEvent(r *http.Request, propertyID int64) domain.PropertyEvent {
+	deviceID := r.Header.Get("X-Device-Fingerprint")
+	if deviceID == "" {
+		deviceID = "default-device"
+	}
+
+	event := domain.PropertyEvent{
+		PropertyID: propertyID,
+		DeviceID:   deviceID,
+		ViewerKey:  "anon:" + clientIP(r) + ":" + deviceID,
+		CreatedAt:  time.Now(),
+	}
+	if uid, ok := r.Context().Value("user_id").(string); ok && uid != "" {
+		event.UserID = &uid
+		event.ViewerKey = uid + ":" + deviceID
+	}
+	return event
+}
+
+// clientIP retorna la IP del cliente sin el puerto
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// parseDateRange lee ?from y ?to (YYYY-MM-DD); por defecto los últimos 30 días
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -29)
+
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = t
+	}
+	return from, to, nil
+}
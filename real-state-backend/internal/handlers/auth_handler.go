@@ -30,7 +30,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Validar DTO
 	if err := req.Validate(); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error(), "validation_error", "auth", nil)
+		writeValidationError(w, http.StatusBadRequest, err, "auth")
 		return
 	}
 
@@ -92,6 +92,10 @@ func (h *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "auth", nil)
 		return
 	}
+	if err := req.Validate(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "auth")
+		return
+	}
 
 	err := h.service.VerifyMFA(r.Context(), userID, req.Code)
 	if err != nil {
@@ -110,6 +114,10 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "auth", nil)
 		return
 	}
+	if err := req.Validate(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "auth")
+		return
+	}
 
 	deviceFingerprint := r.Header.Get("X-Device-Fingerprint")
 	if deviceFingerprint == "" {
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+type CommissionHandler struct {
+	service ports.CommissionService
+}
+
+func NewCommissionHandler(s ports.CommissionService) *CommissionHandler {
+	return &CommissionHandler{service: s}
+}
+
+// ListRules: Reglas de comisión por tipo de operación
+func (h *CommissionHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.ListRules(r.Context())
+	if err != nil {
+		writeCommissionError(w, err, "Error al listar reglas de comisión")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// UpdateRule: Reemplaza la regla de un tipo de operación (sale o rent)
+func (h *CommissionHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	operation := r.PathValue("operation")
+	if operation != domain.OperationSale && operation != domain.OperationRent {
+		writeError(w, http.StatusBadRequest, "Tipo de operación inválido (sale o rent)", "invalid_operation", "commission", nil)
+		return
+	}
+	var input dto.CommissionRuleDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "commission", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "commission")
+		return
+	}
+
+	rule := &domain.CommissionRule{
+		OperationType:       operation,
+		RatePercent:         input.RatePercent,
+		ListingSplitPercent: input.ListingSplitPercent,
+		AgencySharePercent:  input.AgencySharePercent,
+		IVAPercent:          input.IVAPercent,
+	}
+	if err := h.service.UpdateRule(r.Context(), rule); err != nil {
+		writeCommissionError(w, err, "Error al guardar regla de comisión")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// ListSettlements: Liquidaciones de comisiones de la propiedad con sus entradas
+func (h *CommissionHandler) ListSettlements(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "commission", nil)
+		return
+	}
+	settlements, err := h.service.ListSettlements(r.Context(), id)
+	if err != nil {
+		writeCommissionError(w, err, "Error al listar liquidaciones")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settlements)
+}
+
+// Settle: Liquida manualmente el cierre vigente (si la liquidación automática falló)
+func (h *CommissionHandler) Settle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "commission", nil)
+		return
+	}
+	var input dto.SettleCommissionDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "commission", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "commission")
+		return
+	}
+	settlement, err := h.service.Settle(r.Context(), id, input.SellingAgentID)
+	if err != nil {
+		writeCommissionError(w, err, "Error al liquidar comisiones")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(settlement)
+}
+
+// Statement: Estado de cuenta del agente (?from=YYYY-MM-DD&to=YYYY-MM-DD, por defecto el mes actual).
+// "to" es inclusivo.
+func (h *CommissionHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("agent")
+	if _, err := uuid.Parse(agentID); err != nil {
+		writeError(w, http.StatusBadRequest, "ID de agente inválido", "invalid_id", "commission", nil)
+		return
+	}
+	q := r.URL.Query()
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		if v := q.Get(param); v != "" {
+			t, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "Parámetro "+param+" inválido (YYYY-MM-DD)", "invalid_date_range", "commission", nil)
+				return
+			}
+			*target = t
+		}
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "Rango de fechas inválido", "invalid_date_range", "commission", nil)
+		return
+	}
+
+	statement, err := h.service.Statement(r.Context(), agentID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		writeCommissionError(w, err, "Error al generar estado de cuenta")
+		return
+	}
+	statement.To = to
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement)
+}
+
+func writeCommissionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrCommissionAccessDenied):
+		writeError(w, http.StatusForbidden, "Sin acceso al estado de cuenta de este agente", "commission_access_denied", "commission", nil)
+	case errors.Is(err, domain.ErrInvalidCommissionRule):
+		writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "commission", nil)
+	case errors.Is(err, domain.ErrCommissionRuleNotFound):
+		writeError(w, http.StatusNotFound, "No hay regla de comisión para la operación", "commission_rule_not_found", "commission", nil)
+	case errors.Is(err, domain.ErrPropertyNotClosed):
+		writeError(w, http.StatusConflict, "La propiedad no está vendida ni rentada", "property_not_closed", "commission", nil)
+	case errors.Is(err, domain.ErrCommissionAlreadySettled):
+		writeError(w, http.StatusConflict, "El cierre ya tiene su liquidación", "commission_already_settled", "commission", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "commissions_error", "commission", nil)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type ComparisonHandler struct {
+	service    ports.ComparisonService
+	properties ports.PropertyService
+}
+
+func NewComparisonHandler(s ports.ComparisonService, properties ports.PropertyService) *ComparisonHandler {
+	return &ComparisonHandler{service: s, properties: properties}
+}
+
+// Compare: Propiedades lado a lado (?ids=1,2,3&currency=USD|GTQ)
+func (h *ComparisonHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	var v dto.Validator
+	var ids []int64
+	for _, raw := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		switch {
+		case err != nil || id < 1:
+			v.Add("ids", dto.CodeInvalidFormat, "invalid id "+raw)
+		case slices.Contains(ids, id):
+			v.Add("ids", dto.CodeConflict, "repeated id "+raw)
+		default:
+			ids = append(ids, id)
+		}
+	}
+	if !v.Has("ids") {
+		v.Check(len(ids) >= domain.MinCompareProperties && len(ids) <= domain.MaxCompareProperties,
+			"ids", dto.CodeOutOfRange, "between 2 and 4 ids are required")
+	}
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = "USD"
+	}
+	v.Check(slices.Contains([]string{"USD", "GTQ"}, currency), "currency", dto.CodeInvalidChoice, "invalid currency")
+	if err := v.Err(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "compare")
+		return
+	}
+
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service.Compare(r.Context(), ids, currency)
+	if err != nil {
+		if errors.Is(err, domain.ErrPropertyNotComparable) {
+			writeError(w, http.StatusNotFound, "Propiedad no disponible para comparar", "property_not_comparable", "compare", map[string]interface{}{
+				"detail": err.Error(),
+			})
+			return
+		}
+		slog.Error("Error comparing properties", "ids", ids, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al comparar propiedades", "compare_error", "compare", nil)
+		return
+	}
+	if err := h.properties.Localize(r.Context(), comparison.Properties, languages); err != nil {
+		slog.Warn("Error loading translations", "error", err)
+	}
+	// La comparación es para compartir con compradores: sin datos registrales
+	for i := range comparison.Properties {
+		comparison.Properties[i].HideRegistry()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(comparison)
+}
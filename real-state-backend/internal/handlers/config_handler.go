@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
@@ -14,12 +15,14 @@ import (
 
 type ConfigHandler struct {
 	configRepo ports.SecurityConfigRepository
-	auditRepo  ports.AuditRepository // Para registrar cambios en configuración
+	outbox     ports.OutboxRepository // Auditoría de cambios en configuración
+	tx         ports.TxManager
 }
 
-// NewConfigHandler crea un handler con repositorios necesarios
-func NewConfigHandler(configRepo ports.SecurityConfigRepository, auditRepo ports.AuditRepository) *ConfigHandler {
-	return &ConfigHandler{configRepo: configRepo, auditRepo: auditRepo}
+// NewConfigHandler crea un handler con repositorios necesarios. El cambio y su
+// evento de auditoría se confirman en la misma transacción.
+func NewConfigHandler(configRepo ports.SecurityConfigRepository, outbox ports.OutboxRepository, tx ports.TxManager) *ConfigHandler {
+	return &ConfigHandler{configRepo: configRepo, outbox: outbox, tx: tx}
 }
 
 // GetSecurityConfig obtiene todas las configuraciones de seguridad
@@ -135,28 +138,23 @@ func (h *ConfigHandler) UpdateSecurityConfig(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	// Obtener valores antiguos para auditoría (intentar leer, si falla usar nil)
-	oldValPtr := interface{}(nil)
-	if ov, err := h.configRepo.GetInt(r.Context(), key); err == nil {
-		oldValPtr = ov
-	}
-
-	// Intentar actualizar en BD
-	if err := h.configRepo.UpdateConfig(r.Context(), key, strconv.Itoa(v)); err != nil {
-		slog.Error("Failed to update config", "error", err)
-		http.Error(w, `{"error": "Failed to update config"}`, http.StatusInternalServerError)
-		return
-	}
+	// Actualizar y registrar la auditoría en outbox: si cualquiera falla no se confirma ninguno
+	err := h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		// Valor anterior para auditoría (si no se puede leer queda nil)
+		oldValPtr := interface{}(nil)
+		if ov, err := h.configRepo.GetInt(ctx, key); err == nil {
+			oldValPtr = ov
+		}
+		if err := h.configRepo.UpdateConfig(ctx, key, strconv.Itoa(v)); err != nil {
+			return err
+		}
 
-	// Registrar auditoría (si auditRepo está disponible)
-	if h.auditRepo != nil {
 		userIDPtr := (*string)(nil)
-		if uid := r.Context().Value("user_id"); uid != nil {
-			if s, ok := uid.(string); ok {
-				userIDPtr = &s
-			}
+		var aggregateID string
+		if s, ok := ctx.Value("user_id").(string); ok {
+			userIDPtr, aggregateID = &s, s
 		}
-		log := &domain.AuditLog{
+		event, err := domain.NewOutboxEvent(domain.EventAuditLogged, domain.AggregateUser, aggregateID, &domain.AuditLog{
 			EventType: "config_change",
 			UserID:    userIDPtr,
 			Resource:  "security_config",
@@ -166,10 +164,16 @@ func (h *ConfigHandler) UpdateSecurityConfig(w http.ResponseWriter, r *http.Requ
 			IPAddress: r.RemoteAddr,
 			UserAgent: r.Header.Get("User-Agent"),
 			Timestamp: time.Now(),
+		})
+		if err != nil {
+			return err
 		}
-		if err := h.auditRepo.LogEvent(r.Context(), log); err != nil {
-			slog.Warn("Failed to log audit event", "error", err)
-		}
+		return h.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		slog.Error("Failed to update config", "error", err)
+		http.Error(w, `{"error": "Failed to update config"}`, http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/ports"
+)
+
+type GeoHandler struct {
+	service ports.GeoService
+}
+
+func NewGeoHandler(s ports.GeoService) *GeoHandler {
+	return &GeoHandler{service: s}
+}
+
+// ListDepartments: Departamentos para selectores de la app
+func (h *GeoHandler) ListDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, err := h.service.ListDepartments(r.Context())
+	if err != nil {
+		slog.Error("Error listing departments", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar departamentos", "geo_error", "geo", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(departments)
+}
+
+// ListMunicipalities: Municipios de un departamento
+func (h *GeoHandler) ListMunicipalities(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "geo", nil)
+		return
+	}
+	municipalities, err := h.service.ListMunicipalities(r.Context(), id)
+	if err != nil {
+		slog.Error("Error listing municipalities", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar municipios", "geo_error", "geo", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(municipalities)
+}
+
+// ListZones: Zonas de un municipio
+func (h *GeoHandler) ListZones(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "geo", nil)
+		return
+	}
+	zones, err := h.service.ListZones(r.Context(), id)
+	if err != nil {
+		slog.Error("Error listing zones", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar zonas", "geo_error", "geo", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(zones)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type MarketStatsHandler struct {
+	service ports.MarketStatsService
+}
+
+func NewMarketStatsHandler(s ports.MarketStatsService) *MarketStatsHandler {
+	return &MarketStatsHandler{service: s}
+}
+
+// GetMonthly retorna estadísticas de mercado por ciudad, tipo y mes.
+// Filtros: ?city=&type=&from=YYYY-MM&to=YYYY-MM&currency=USD|GTQ (por defecto últimos 12 meses).
+func (h *MarketStatsHandler) GetMonthly(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	now := time.Now()
+	filter := domain.MarketStatsFilter{
+		City: q.Get("city"),
+		Type: q.Get("type"),
+		From: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0),
+		To:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	}
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Parámetro from inválido (YYYY-MM)", "invalid_date_range", "market_stats", nil)
+			return
+		}
+		filter.From = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Parámetro to inválido (YYYY-MM)", "invalid_date_range", "market_stats", nil)
+			return
+		}
+		filter.To = t
+	}
+
+	currency := q.Get("currency")
+	if currency == "" {
+		currency = "USD"
+	}
+	if !slices.Contains([]string{"USD", "GTQ"}, currency) {
+		writeError(w, http.StatusBadRequest, "invalid currency", "validation_error", "market_stats", nil)
+		return
+	}
+
+	stats, err := h.service.GetMonthly(r.Context(), filter, currency)
+	if err != nil {
+		slog.Error("Error getting market stats", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al obtener estadísticas", "market_stats_error", "market_stats", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// Refresh fuerza el refresco de la vista materializada
+func (h *MarketStatsHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Refresh(r.Context()); err != nil {
+		slog.Error("Error refreshing market stats", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al refrescar estadísticas", "market_stats_error", "market_stats", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Market stats refreshed"})
+}
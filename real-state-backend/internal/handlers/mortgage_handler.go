@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+	"real-state-backend/internal/services"
+)
+
+type MortgageHandler struct {
+	service ports.MortgageService
+}
+
+func NewMortgageHandler(s ports.MortgageService) *MortgageHandler {
+	return &MortgageHandler{service: s}
+}
+
+// Calculate: Cuota mensual, plan de amortización y capacidad de pago
+func (h *MortgageHandler) Calculate(w http.ResponseWriter, r *http.Request) {
+	var input dto.MortgageCalculationDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "mortgage", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "mortgage")
+		return
+	}
+
+	quote, err := h.service.Calculate(r.Context(), domain.MortgageInput{
+		PropertyID:    input.PropertyID,
+		Price:         input.Price,
+		Currency:      input.Currency,
+		DownPayment:   input.DownPayment,
+		AnnualRate:    input.AnnualRate,
+		TermYears:     input.TermYears,
+		ProductID:     input.ProductID,
+		MonthlyIncome: input.MonthlyIncome,
+		MonthlyDebts:  input.MonthlyDebts,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMortgageInput) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "mortgage", nil)
+			return
+		}
+		if errors.Is(err, domain.ErrPropertyNotFound) || errors.Is(err, domain.ErrMortgageProductNotFound) {
+			writeError(w, http.StatusNotFound, "Propiedad o producto no encontrado", "mortgage_error", "mortgage", nil)
+			return
+		}
+		slog.Error("Error calculating mortgage", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al calcular la hipoteca", "mortgage_error", "mortgage", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+// ListProducts: Presets hipotecarios (?all=true incluye inactivos)
+func (h *MortgageHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("all") != "true"
+	products, err := h.service.ListProducts(r.Context(), activeOnly)
+	if err != nil {
+		slog.Error("Error listing mortgage products", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar productos", "mortgage_products_error", "mortgage", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// CreateProduct: Alta de un producto hipotecario
+func (h *MortgageHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	product, ok := decodeMortgageProduct(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CreateProduct(r.Context(), product); err != nil {
+		slog.Error("Error creating mortgage product", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error de base de datos", "db_error", "mortgage", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(product)
+}
+
+// UpdateProduct: Modificación de un producto hipotecario
+func (h *MortgageHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "mortgage", nil)
+		return
+	}
+	product, ok := decodeMortgageProduct(w, r)
+	if !ok {
+		return
+	}
+	product.ID = id
+
+	if err := h.service.UpdateProduct(r.Context(), product); err != nil {
+		if errors.Is(err, domain.ErrMortgageProductNotFound) {
+			writeError(w, http.StatusNotFound, "Producto no encontrado", "mortgage_product_not_found", "mortgage", nil)
+			return
+		}
+		slog.Error("Error updating mortgage product", "product_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al actualizar el producto", "mortgage_products_error", "mortgage", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// DeleteProduct: Baja de un producto hipotecario
+func (h *MortgageHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "mortgage", nil)
+		return
+	}
+
+	if err := h.service.DeleteProduct(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrMortgageProductNotFound) {
+			writeError(w, http.StatusNotFound, "Producto no encontrado", "mortgage_product_not_found", "mortgage", nil)
+			return
+		}
+		slog.Error("Error deleting mortgage product", "product_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al eliminar el producto", "mortgage_products_error", "mortgage", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeMortgageProduct decodifica y valida el DTO de producto hipotecario
+func decodeMortgageProduct(w http.ResponseWriter, r *http.Request) (*domain.MortgageProduct, bool) {
+	var input dto.MortgageProductDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "mortgage", nil)
+		return nil, false
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "mortgage")
+		return nil, false
+	}
+
+	product := &domain.MortgageProduct{
+		BankName:           input.BankName,
+		ProductName:        input.ProductName,
+		Currency:           input.Currency,
+		AnnualRate:         input.AnnualRate,
+		MaxTermYears:       input.MaxTermYears,
+		MinDownPaymentPct:  input.MinDownPaymentPct,
+		MaxPaymentToIncome: 0.35,
+		Active:             true,
+	}
+	if input.MaxPaymentToIncome != nil {
+		product.MaxPaymentToIncome = *input.MaxPaymentToIncome
+	}
+	if input.Active != nil {
+		product.Active = *input.Active
+	}
+	return product, true
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type NotificationHandler struct {
+	service ports.NotificationService
+}
+
+func NewNotificationHandler(s ports.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: s}
+}
+
+// List: Correos del usuario autenticado, más recientes primero (?limit=50)
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	notifications, err := h.service.List(r.Context(), limit)
+	if err != nil {
+		writeNotificationError(w, err, "Error al listar notificaciones")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.service.GetPreferences(r.Context())
+	if err != nil {
+		writeNotificationError(w, err, "Error al obtener preferencias")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdatePreferences: Idioma, correo activo y tipos silenciados
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateNotificationPreferencesDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "notification", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "notification")
+		return
+	}
+	prefs, err := h.service.UpdatePreferences(r.Context(), domain.NotificationPreferencesChanges{
+		Language:     input.Language,
+		EmailEnabled: input.EmailEnabled,
+		MutedKinds:   input.MutedKinds,
+	})
+	if err != nil {
+		writeNotificationError(w, err, "Error al guardar preferencias")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// SendTest: Encola un correo de prueba para el usuario autenticado
+func (h *NotificationHandler) SendTest(w http.ResponseWriter, r *http.Request) {
+	notification, err := h.service.SendTest(r.Context())
+	if err != nil {
+		writeNotificationError(w, err, "Error al encolar correo de prueba")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(notification)
+}
+
+func writeNotificationError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrNotificationRecipientMissing):
+		writeError(w, http.StatusUnprocessableEntity, "El usuario no tiene correo registrado", "email_missing", "notification", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "notifications_error", "notification", nil)
+	}
+}
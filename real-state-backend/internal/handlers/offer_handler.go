@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type OfferHandler struct {
+	service ports.OfferService
+}
+
+func NewOfferHandler(s ports.OfferService) *OfferHandler {
+	return &OfferHandler{service: s}
+}
+
+// Submit: Oferta de un comprador sobre una propiedad publicada
+func (h *OfferHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "offer", nil)
+		return
+	}
+	input, ok := decodeOfferTerms(w, r)
+	if !ok {
+		return
+	}
+	offer, err := h.service.Submit(r.Context(), propertyID, offerTerms(input), input.Message)
+	if err != nil {
+		writeOfferError(w, err, "Error al registrar oferta")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(offer)
+}
+
+// List: Ofertas de la propiedad (el comprador solo ve las suyas)
+func (h *OfferHandler) List(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "offer", nil)
+		return
+	}
+	offers, err := h.service.List(r.Context(), propertyID)
+	if err != nil {
+		writeOfferError(w, err, "Error al listar ofertas")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offers)
+}
+
+// Get: Oferta con su hilo de negociación
+func (h *OfferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	propertyID, offerID, ok := parseOfferPath(w, r)
+	if !ok {
+		return
+	}
+	offer, err := h.service.Get(r.Context(), propertyID, offerID)
+	if err != nil {
+		writeOfferError(w, err, "Error al obtener oferta")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
+
+// Counter: Contraoferta de la parte que debe responder
+func (h *OfferHandler) Counter(w http.ResponseWriter, r *http.Request) {
+	propertyID, offerID, ok := parseOfferPath(w, r)
+	if !ok {
+		return
+	}
+	input, ok := decodeOfferTerms(w, r)
+	if !ok {
+		return
+	}
+	offer, err := h.service.Counter(r.Context(), propertyID, offerID, offerTerms(input), input.Message)
+	if err != nil {
+		writeOfferError(w, err, "Error al registrar contraoferta")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
+
+// Accept: Acepta la oferta vigente y reserva la propiedad
+func (h *OfferHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, domain.OfferStatusAccepted)
+}
+
+// Reject: Rechaza la oferta vigente
+func (h *OfferHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, domain.OfferStatusRejected)
+}
+
+// Withdraw: El comprador retira su oferta
+func (h *OfferHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, domain.OfferStatusWithdrawn)
+}
+
+func (h *OfferHandler) respond(w http.ResponseWriter, r *http.Request, status string) {
+	propertyID, offerID, ok := parseOfferPath(w, r)
+	if !ok {
+		return
+	}
+	// El comentario es opcional: se acepta cuerpo vacío
+	var input dto.OfferMessageDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "offer", nil)
+		return
+	}
+	if err := input.Validate(false); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "offer")
+		return
+	}
+	offer, err := h.service.Respond(r.Context(), propertyID, offerID, status, input.Message)
+	if err != nil {
+		writeOfferError(w, err, "Error al responder oferta")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
+
+// AddMessage: Mensaje en el hilo de negociación
+func (h *OfferHandler) AddMessage(w http.ResponseWriter, r *http.Request) {
+	propertyID, offerID, ok := parseOfferPath(w, r)
+	if !ok {
+		return
+	}
+	var input dto.OfferMessageDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "offer", nil)
+		return
+	}
+	if err := input.Validate(true); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "offer")
+		return
+	}
+	event, err := h.service.AddMessage(r.Context(), propertyID, offerID, input.Message)
+	if err != nil {
+		writeOfferError(w, err, "Error al registrar mensaje")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
+
+func parseOfferPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	offerID, errOffer := strconv.ParseInt(r.PathValue("offer"), 10, 64)
+	if err != nil || errOffer != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "offer", nil)
+		return 0, 0, false
+	}
+	return propertyID, offerID, true
+}
+
+func decodeOfferTerms(w http.ResponseWriter, r *http.Request) (dto.OfferTermsDTO, bool) {
+	var input dto.OfferTermsDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "offer", nil)
+		return input, false
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "offer")
+		return input, false
+	}
+	return input, true
+}
+
+func offerTerms(input dto.OfferTermsDTO) domain.OfferTerms {
+	return domain.OfferTerms{Amount: input.Amount, Currency: input.Currency, Conditions: input.Conditions, ExpiresAt: *input.ExpiresAt}
+}
+
+func writeOfferError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrOfferAccessDenied):
+		writeError(w, http.StatusForbidden, "Sin acceso a esta oferta", "offer_access_denied", "offer", nil)
+	case errors.Is(err, domain.ErrInvalidOffer):
+		writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_offer", "offer", nil)
+	case errors.Is(err, domain.ErrOfferNotFound):
+		writeError(w, http.StatusNotFound, "Oferta o propiedad no encontrada", "offer_not_found", "offer", nil)
+	case errors.Is(err, domain.ErrOfferExpired):
+		writeError(w, http.StatusConflict, "La oferta ya venció", "offer_expired", "offer", nil)
+	case errors.Is(err, domain.ErrInvalidOfferTransition):
+		writeError(w, http.StatusConflict, "Acción no permitida en el estado actual de la oferta", "invalid_offer_transition", "offer", nil)
+	case errors.Is(err, domain.ErrPropertyNotAvailable):
+		writeError(w, http.StatusConflict, "La propiedad no está publicada", "property_not_available", "offer", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "offers_error", "offer", nil)
+	}
+}
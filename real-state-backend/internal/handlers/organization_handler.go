@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationHandler struct {
+	service ports.OrganizationService
+}
+
+func NewOrganizationHandler(s ports.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{service: s}
+}
+
+// List: Agencias de la plataforma
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.service.List(r.Context())
+	if err != nil {
+		writeOrganizationError(w, err, "Error al listar agencias")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orgs)
+}
+
+// Create: Registra una agencia (activa)
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateOrganizationDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "organization", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "organization")
+		return
+	}
+	org := &domain.Organization{Name: input.Name, Slug: input.Slug}
+	if err := h.service.Create(r.Context(), org); err != nil {
+		writeOrganizationError(w, err, "Error al registrar agencia")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(org)
+}
+
+// Update: Renombra o activa/desactiva una agencia
+func (h *OrganizationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUIDPath(w, r, "id")
+	if !ok {
+		return
+	}
+	var input dto.UpdateOrganizationDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "organization", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "organization")
+		return
+	}
+	org, err := h.service.Update(r.Context(), id, domain.OrganizationChanges{Name: input.Name, Active: input.Active})
+	if err != nil {
+		writeOrganizationError(w, err, "Error al actualizar agencia")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(org)
+}
+
+// CreateAgencyAdmin: Crea el administrador inicial de una agencia
+func (h *OrganizationHandler) CreateAgencyAdmin(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUIDPath(w, r, "id")
+	if !ok {
+		return
+	}
+	input, ok := decodeCreateUser(w, r)
+	if !ok {
+		return
+	}
+	user := &domain.User{Username: input.Username, Email: input.Email}
+	if err := h.service.CreateAgencyAdmin(r.Context(), id, user, input.Password); err != nil {
+		writeOrganizationError(w, err, "Error al crear administrador de agencia")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// ListUsers: Usuarios de la agencia del usuario autenticado
+func (h *OrganizationHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		writeOrganizationError(w, err, "Error al listar usuarios")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// CreateUser: Crea un usuario en la agencia del usuario autenticado
+func (h *OrganizationHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodeCreateUser(w, r)
+	if !ok {
+		return
+	}
+	user := &domain.User{Username: input.Username, Email: input.Email}
+	if err := h.service.CreateUser(r.Context(), user, input.Password, input.RoleIDs); err != nil {
+		writeOrganizationError(w, err, "Error al crear usuario")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// SetUserRoles: Reemplaza los roles de un usuario de la agencia
+func (h *OrganizationHandler) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseUUIDPath(w, r, "id")
+	if !ok {
+		return
+	}
+	var input dto.SetUserRolesDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "organization", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "organization")
+		return
+	}
+	user, err := h.service.SetUserRoles(r.Context(), id, input.RoleIDs)
+	if err != nil {
+		writeOrganizationError(w, err, "Error al asignar roles")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ListRoles: Roles asignables en la agencia (globales y propios)
+func (h *OrganizationHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		writeOrganizationError(w, err, "Error al listar roles")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roles)
+}
+
+// CreateRole: Crea un rol propio de la agencia (sin permisos de plataforma)
+func (h *OrganizationHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateRoleDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "organization", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "organization")
+		return
+	}
+	role := &domain.Role{Name: input.Name, Description: input.Description, Permissions: input.Permissions}
+	if err := h.service.CreateRole(r.Context(), role); err != nil {
+		writeOrganizationError(w, err, "Error al crear rol")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(role)
+}
+
+func parseUUIDPath(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	id := r.PathValue(name)
+	if _, err := uuid.Parse(id); err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "organization", nil)
+		return "", false
+	}
+	return id, true
+}
+
+func decodeCreateUser(w http.ResponseWriter, r *http.Request) (dto.CreateUserDTO, bool) {
+	var input dto.CreateUserDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "organization", nil)
+		return input, false
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "organization")
+		return input, false
+	}
+	return input, true
+}
+
+func writeOrganizationError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrOrganizationNotFound):
+		writeError(w, http.StatusNotFound, "Agencia no encontrada", "organization_not_found", "organization", nil)
+	case errors.Is(err, domain.ErrOrganizationSlugTaken):
+		writeError(w, http.StatusConflict, "El slug ya está en uso", "organization_slug_taken", "organization", nil)
+	case errors.Is(err, domain.ErrOrganizationProtected):
+		writeError(w, http.StatusConflict, "La agencia por defecto no se puede desactivar", "organization_protected", "organization", nil)
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeError(w, http.StatusNotFound, "Usuario no encontrado en la agencia", "user_not_found", "organization", nil)
+	case errors.Is(err, domain.ErrUserTaken):
+		writeError(w, http.StatusConflict, "El usuario o email ya está registrado", "user_taken", "organization", nil)
+	case errors.Is(err, domain.ErrRoleNotAssignable):
+		writeError(w, http.StatusForbidden, err.Error(), "role_not_assignable", "organization", nil)
+	case errors.Is(err, domain.ErrRoleNameTaken):
+		writeError(w, http.StatusConflict, "Ya existe un rol con ese nombre en la agencia", "role_name_taken", "organization", nil)
+	case errors.Is(err, domain.ErrPermissionNotAssignable):
+		writeError(w, http.StatusUnprocessableEntity, "Permiso inexistente o de plataforma", "permission_not_assignable", "organization", nil)
+	case errors.Is(err, domain.ErrTenantRequired):
+		writeError(w, http.StatusForbidden, "El usuario no pertenece a una agencia", "tenant_required", "organization", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "organizations_error", "organization", nil)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type PasswordResetHandler struct {
+	service ports.PasswordResetService
+}
+
+func NewPasswordResetHandler(s ports.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{service: s}
+}
+
+// Forgot: Envía el enlace de recuperación. Responde igual exista o no el correo.
+func (h *PasswordResetHandler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var input dto.ForgotPasswordDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "auth", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "auth")
+		return
+	}
+	if err := h.service.RequestReset(r.Context(), input.Email); err != nil {
+		writePasswordResetError(w, err, "Error al solicitar recuperación de contraseña")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Si el correo está registrado recibirás un enlace de recuperación"})
+}
+
+// Reset: Cambia la contraseña con el token del correo y cierra las sesiones abiertas
+func (h *PasswordResetHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	var input dto.ResetPasswordDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "auth", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "auth")
+		return
+	}
+	if err := h.service.ResetPassword(r.Context(), input.Token, input.Password); err != nil {
+		writePasswordResetError(w, err, "Error al cambiar la contraseña")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writePasswordResetError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrPasswordResetInvalid):
+		writeError(w, http.StatusBadRequest, "El enlace de recuperación no es válido o venció", "password_reset_invalid", "auth", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "password_reset_error", "auth", nil)
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type PropertyDocumentHandler struct {
+	service ports.PropertyDocumentService
+}
+
+func NewPropertyDocumentHandler(s ports.PropertyDocumentService) *PropertyDocumentHandler {
+	return &PropertyDocumentHandler{service: s}
+}
+
+// Upload: Sube un documento nuevo (multipart: file, document_type, title)
+func (h *PropertyDocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, 0)
+}
+
+// UploadVersion: Sube una nueva versión de un documento existente (multipart: file)
+func (h *PropertyDocumentHandler) UploadVersion(w http.ResponseWriter, r *http.Request) {
+	docID, err := strconv.ParseInt(r.PathValue("doc"), 10, 64)
+	if err != nil || docID < 1 {
+		writeError(w, http.StatusBadRequest, "ID de documento inválido", "invalid_id", "documents", nil)
+		return
+	}
+	h.upload(w, r, docID)
+}
+
+func (h *PropertyDocumentHandler) upload(w http.ResponseWriter, r *http.Request, docID int64) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "documents", nil)
+		return
+	}
+	// Margen de 1 MB para los demás campos del formulario
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxDocumentSize+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Se requiere el archivo en el campo 'file' (máx. 20 MB)", "invalid_upload", "documents", nil)
+		return
+	}
+	defer file.Close()
+
+	doc, err := h.service.Upload(r.Context(), domain.DocumentUpload{
+		PropertyID:   propertyID,
+		DocumentID:   docID,
+		DocumentType: r.FormValue("document_type"),
+		Title:        r.FormValue("title"),
+		FileName:     header.Filename,
+		Content:      file,
+	})
+	if err != nil {
+		writeDocumentError(w, err, "Error al subir documento")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(doc)
+}
+
+// List: Documentos de la propiedad con su última versión
+func (h *PropertyDocumentHandler) List(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "documents", nil)
+		return
+	}
+	docs, err := h.service.List(r.Context(), propertyID)
+	if err != nil {
+		writeDocumentError(w, err, "Error al listar documentos")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(docs)
+}
+
+// ListVersions: Historial de versiones de un documento
+func (h *PropertyDocumentHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	docID, errDoc := strconv.ParseInt(r.PathValue("doc"), 10, 64)
+	if err != nil || errDoc != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "documents", nil)
+		return
+	}
+	versions, err := h.service.ListVersions(r.Context(), propertyID, docID)
+	if err != nil {
+		writeDocumentError(w, err, "Error al listar versiones")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+// Download: Descarga la última versión o ?version=N; cada descarga queda en auditoría
+func (h *PropertyDocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	docID, errDoc := strconv.ParseInt(r.PathValue("doc"), 10, 64)
+	if err != nil || errDoc != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "documents", nil)
+		return
+	}
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		if version, err = strconv.Atoi(v); err != nil || version < 1 {
+			writeError(w, http.StatusBadRequest, "Versión inválida", "invalid_version", "documents", nil)
+			return
+		}
+	}
+
+	origin := domain.RequestOrigin{IPAddress: clientIP(r), UserAgent: r.Header.Get("User-Agent")}
+	v, file, err := h.service.Download(r.Context(), propertyID, docID, version, origin)
+	if err != nil {
+		writeDocumentError(w, err, "Error al descargar documento")
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", v.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(v.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", v.FileName))
+	w.Header().Set("X-Checksum-SHA256", v.Checksum)
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := io.Copy(w, file); err != nil {
+		slog.Warn("Error streaming document", "document_id", docID, "error", err)
+	}
+}
+
+func writeDocumentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrDocumentAccessDenied):
+		writeError(w, http.StatusForbidden, "Sin acceso a los documentos de esta propiedad", "document_access_denied", "documents", nil)
+	case errors.Is(err, domain.ErrInvalidDocument):
+		writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_document", "documents", map[string]interface{}{
+			"document_types": domain.DocumentTypes,
+			"content_types":  domain.DocumentContentTypes,
+		})
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		writeError(w, http.StatusNotFound, "Documento o propiedad no encontrado", "document_not_found", "documents", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "documents_error", "documents", nil)
+	}
+}
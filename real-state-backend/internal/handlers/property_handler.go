@@ -2,21 +2,29 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"real-state-backend/internal/core/domain"
 	"real-state-backend/internal/core/ports"
 	"real-state-backend/internal/dto"
+	"real-state-backend/internal/services"
 	"strconv"
 	//"strings"
 )
 
+// permissionViewRegistry permite ver y buscar por datos registrales (usuarios internos)
+const permissionViewRegistry = "view_land_registry"
+
 type PropertyHandler struct {
-	service ports.PropertyService
+	service   ports.PropertyService
+	analytics ports.AnalyticsService
+	geo       ports.GeoService
+	auth      ports.AuthService
 }
 
-func NewPropertyHandler(s ports.PropertyService) *PropertyHandler {
-	return &PropertyHandler{service: s}
+func NewPropertyHandler(s ports.PropertyService, analytics ports.AnalyticsService, geo ports.GeoService, auth ports.AuthService) *PropertyHandler {
+	return &PropertyHandler{service: s, analytics: analytics, geo: geo, auth: auth}
 }
 
 // GetAll: Resuelve el error de "undefined GetAll" en main.go
@@ -28,14 +36,46 @@ func (h *PropertyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		page = 1
 	}
 
-	properties, err := h.service.ListProperties(r.Context(), page, 10)
+	filter, ok := parsePropertyFilter(w, r)
+	if !ok {
+		return
+	}
+	// Proyección de campos (?fields=title,price)
+	var v dto.Validator
+	filter.Fields = parseFields(r.URL.Query().Get("fields"), &v)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "property")
+		return
+	}
+	// Búsqueda por datos registrales (?finca=&folio=&libro=&iusi=), solo usuarios internos
+	canViewRegistry := h.canViewRegistry(r)
+	if parseRegistryFilter(r, &filter) && !canViewRegistry {
+		writeError(w, http.StatusForbidden, "Sin permiso para buscar por datos registrales", "forbidden", "property", nil)
+		return
+	}
+
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+
+	properties, err := h.service.ListProperties(r.Context(), filter, page, 10)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "Error al listar propiedades", "list_properties_error", "property", nil)
 		return
 	}
+	if err := h.service.Localize(r.Context(), properties, languages); err != nil {
+		slog.Warn("Error loading translations", "error", err)
+	}
+	if !canViewRegistry {
+		for i := range properties {
+			properties[i].HideRegistry()
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(properties)
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(projectProperties(properties, filter.Fields))
 }
 
 // GetByID: Resuelve el error de "undefined GetByID" en main.go
@@ -48,14 +88,39 @@ func (h *PropertyHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var v dto.Validator
+	fields := parseFields(r.URL.Query().Get("fields"), &v)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "property")
+		return
+	}
+
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+
 	property, err := h.service.GetProperty(r.Context(), id)
 	if err != nil {
 		writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
 		return
 	}
+	localized := []domain.Property{*property}
+	if err := h.service.Localize(r.Context(), localized, languages); err != nil {
+		slog.Warn("Error loading translations", "property_id", id, "error", err)
+	}
+	property = &localized[0]
+	if !h.canViewRegistry(r) {
+		property.HideRegistry()
+	}
+
+	// Registro asíncrono de la vista (no bloquea la respuesta)
+	h.analytics.TrackView(viewerEvent(r, property.ID))
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(property)
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", property.Language)
+	json.NewEncoder(w).Encode(projectProperty(property, fields))
 }
 
 // CreateProperty: Registro de nuevas propiedades desde la App
@@ -68,22 +133,63 @@ func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request)
 
 	// Usar Validate() en lugar de IsValid()
 	if err := input.Validate(); err != nil {
-		writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "property", nil)
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "property")
 		return
 	}
 
+	// El área se almacena siempre en m²
+	areaSqM, _ := domain.AreaToSqM(input.Area, input.AreaUnit)
+
 	property := &domain.Property{
-		Title:       input.Title,
-		Price:       input.Price,
-		Description: input.Description,
-		Currency:    input.Currency,
-		Address:     input.Location,
-		Type:        input.Type,
+		Title:          input.Title,
+		Price:          input.Price,
+		Description:    input.Description,
+		Currency:       input.Currency,
+		Address:        input.Location,
+		Type:           input.Type,
+		Bedrooms:       input.Bedrooms,
+		Bathrooms:      input.Bathrooms,
+		Amenities:      input.Amenities,
+		AreaSqM:        areaSqM,
+		MainImage:      input.MainImage,
+		Status:         input.Status,
+		SourceLanguage: input.Language,
+		RegistryFinca:  input.RegistryFinca,
+		RegistryFolio:  input.RegistryFolio,
+		RegistryLibro:  input.RegistryLibro,
+		RegistryIUSI:   input.RegistryIUSI,
+	}
+	if input.Lat != nil {
+		property.Lat, property.Lng = *input.Lat, *input.Lng
+	}
+
+	// Normalizar ubicación contra el catálogo (Location sigue siendo dirección libre)
+	if input.City != "" {
+		loc, ok := h.resolveLocation(w, r, input.Department, input.City, input.Zone)
+		if !ok {
+			return
+		}
+		property.SetLocation(loc)
 	}
 
 	slog.Info("Creating property", "title", property.Title, "price", property.Price, "currency", property.Currency, "address", property.Address)
 
-	if err := h.service.CreateProperty(r.Context(), property); err != nil {
+	if err := h.service.CreateProperty(r.Context(), property, input.ConfirmDuplicate); err != nil {
+		var dupErr *domain.DuplicatePropertyError
+		if errors.As(err, &dupErr) {
+			writeError(w, http.StatusConflict, "Posible propiedad duplicada", "duplicate_property", "property", map[string]interface{}{
+				"candidates": dupErr.Candidates,
+				"hint":       "send confirm_duplicate=true to create it anyway",
+			})
+			return
+		}
+		if writeRegistryConflict(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProperty) || errors.Is(err, services.ErrInvalidLanguage) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "property", nil)
+			return
+		}
 		slog.Error("Error creating property", "error", err)
 		writeError(w, http.StatusInternalServerError, "Error de base de datos", "db_error", "property", nil)
 		return
@@ -93,3 +199,460 @@ func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(property)
 }
+
+// GetDuplicates: Reporte de posibles duplicados en el catálogo (?limit=100)
+func (h *PropertyHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	pairs, err := h.service.FindDuplicates(r.Context(), limit)
+	if err != nil {
+		slog.Error("Error finding duplicates", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al buscar duplicados", "duplicates_error", "property", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pairs)
+}
+
+// ChangeStatus: Cambio de estado de publicación (vendida, rentada, reservada...)
+func (h *PropertyHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+
+	var input dto.UpdatePropertyStatusDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "property", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "property")
+		return
+	}
+
+	property, err := h.service.ChangeStatus(r.Context(), id, input.Status, input.SellingAgentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidStatusTransition):
+			writeError(w, http.StatusConflict, "Cambio de estado no permitido", "invalid_status_transition", "property", nil)
+		case errors.Is(err, services.ErrInvalidStatus):
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "property", nil)
+		case errors.As(err, new(*domain.RegistryConflictError)):
+			writeRegistryConflict(w, err)
+		case errors.Is(err, domain.ErrPropertyNotFound):
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
+		default:
+			slog.Error("Error changing property status", "property_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "Error al cambiar el estado", "property_status_error", "property", nil)
+		}
+		return
+	}
+	if !h.canViewRegistry(r) {
+		property.HideRegistry()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(property)
+}
+
+// UpdateProperty: Modificación parcial de una propiedad
+func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+
+	var input dto.UpdatePropertyDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "property", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "property")
+		return
+	}
+
+	// El parche se aplica dentro de la transacción sobre la fila bloqueada
+	property, err := h.service.UpdateProperty(r.Context(), id, func(property *domain.Property) error {
+		if input.Title != nil {
+			property.Title = *input.Title
+		}
+		if input.Description != nil {
+			property.Description = *input.Description
+		}
+		if input.Price != nil {
+			property.Price = *input.Price
+		}
+		if input.Currency != nil {
+			property.Currency = *input.Currency
+		}
+		// Si cambia la dirección se vuelve a geocodificar, salvo coordenadas manuales
+		locationChanged := input.Location != nil || input.Department != nil || input.City != nil || input.Zone != nil
+		if input.Location != nil {
+			property.Address = *input.Location
+		}
+		if input.Type != nil {
+			property.Type = *input.Type
+		}
+		if input.Area != nil {
+			property.AreaSqM, _ = domain.AreaToSqM(*input.Area, input.AreaUnit)
+		}
+		if input.Bedrooms != nil {
+			property.Bedrooms = *input.Bedrooms
+		}
+		if input.Bathrooms != nil {
+			property.Bathrooms = *input.Bathrooms
+		}
+		if input.Amenities != nil {
+			property.Amenities = *input.Amenities
+		}
+		if input.MainImage != nil {
+			property.MainImage = *input.MainImage
+		}
+		if input.RegistryFinca != nil {
+			property.RegistryFinca, property.RegistryFolio, property.RegistryLibro = *input.RegistryFinca, *input.RegistryFolio, *input.RegistryLibro
+		}
+		if input.RegistryIUSI != nil {
+			property.RegistryIUSI = *input.RegistryIUSI
+		}
+		// Si cambia algún dato de ubicación se vuelve a resolver; la zona debe reenviarse
+		if input.Department != nil || input.City != nil || input.Zone != nil {
+			department, city, zone := property.Department, property.City, 0
+			if input.Department != nil {
+				department = *input.Department
+			}
+			if input.City != nil {
+				city = *input.City
+			}
+			if input.Zone != nil {
+				zone = *input.Zone
+			}
+			loc, err := h.geo.Resolve(r.Context(), department, city, zone)
+			if err != nil {
+				return err
+			}
+			property.SetLocation(loc)
+		}
+		if locationChanged && property.GeocodeSource != domain.GeocodeSourceManual {
+			property.ClearGeocode()
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPropertyNotFound) {
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
+			return
+		}
+		if errors.Is(err, services.ErrUnknownLocation) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_location", "property", nil)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProperty) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "property", nil)
+			return
+		}
+		if writeRegistryConflict(w, err) {
+			return
+		}
+		slog.Error("Error updating property", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error de base de datos", "db_error", "property", nil)
+		return
+	}
+	if !h.canViewRegistry(r) {
+		property.HideRegistry()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(property)
+}
+
+// SetCoordinates: Corrección manual de las coordenadas (tiene prioridad sobre la geocodificación)
+func (h *PropertyHandler) SetCoordinates(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+
+	var input dto.UpdateCoordinatesDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "property", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "property")
+		return
+	}
+
+	property, err := h.service.SetCoordinates(r.Context(), id, *input.Lat, *input.Lng)
+	if err != nil {
+		if errors.Is(err, domain.ErrPropertyNotFound) {
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
+			return
+		}
+		slog.Error("Error setting coordinates", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al guardar las coordenadas", "property_coordinates_error", "property", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(property)
+}
+
+// ListRevisions: Historial de cambios de una propiedad (sin las copias completas)
+func (h *PropertyHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		slog.Error("Error listing revisions", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar revisiones", "revisions_error", "property", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetRevision: Copia completa de la propiedad en una revisión
+func (h *PropertyHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	id, revision, ok := parseRevisionPath(w, r)
+	if !ok {
+		return
+	}
+	rev, err := h.service.GetRevision(r.Context(), id, revision)
+	if err != nil {
+		writeRevisionError(w, id, err)
+		return
+	}
+	rev.Snapshot.ComputeAreas()
+	if !h.canViewRegistry(r) {
+		rev.Snapshot.HideRegistry()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+// DiffRevisions: Campos que cambiaron entre dos revisiones (?from=&to=, to por defecto la última)
+func (h *PropertyHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || from < 1 {
+		writeError(w, http.StatusBadRequest, "Parámetro from inválido", "invalid_revision", "property", nil)
+		return
+	}
+	to := 0
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = strconv.Atoi(v); err != nil || to < 1 {
+			writeError(w, http.StatusBadRequest, "Parámetro to inválido", "invalid_revision", "property", nil)
+			return
+		}
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		writeRevisionError(w, id, err)
+		return
+	}
+	if !h.canViewRegistry(r) {
+		diff.HideRegistry()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// RevertRevision: Restaura una revisión anterior creando una revisión nueva
+func (h *PropertyHandler) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	id, revision, ok := parseRevisionPath(w, r)
+	if !ok {
+		return
+	}
+	property, err := h.service.RevertToRevision(r.Context(), id, revision)
+	if err != nil {
+		writeRevisionError(w, id, err)
+		return
+	}
+	if !h.canViewRegistry(r) {
+		property.HideRegistry()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(property)
+}
+
+// parseRevisionPath lee {id} y {revision} de la ruta
+func parseRevisionPath(w http.ResponseWriter, r *http.Request) (int64, int, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return 0, 0, false
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil || revision < 1 {
+		writeError(w, http.StatusBadRequest, "Revisión inválida", "invalid_revision", "property", nil)
+		return 0, 0, false
+	}
+	return id, revision, true
+}
+
+func writeRevisionError(w http.ResponseWriter, id int64, err error) {
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		writeError(w, http.StatusNotFound, "Revisión no encontrada", "revision_not_found", "property", nil)
+		return
+	}
+	if errors.Is(err, domain.ErrPropertyNotFound) {
+		writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
+		return
+	}
+	slog.Error("Error handling revision", "property_id", id, "error", err)
+	writeError(w, http.StatusInternalServerError, "Error de base de datos", "db_error", "property", nil)
+}
+
+// ListTranslations: Traducciones del título y la descripción de una propiedad
+func (h *PropertyHandler) ListTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+	translations, err := h.service.ListTranslations(r.Context(), id)
+	if err != nil {
+		slog.Error("Error listing translations", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar traducciones", "translations_error", "property", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translations)
+}
+
+// SaveTranslation: Crea o reemplaza la traducción de un idioma
+func (h *PropertyHandler) SaveTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+
+	var input dto.PropertyTranslationDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "property", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "property")
+		return
+	}
+
+	translation := &domain.PropertyTranslation{
+		PropertyID:  id,
+		Language:    r.PathValue("lang"),
+		Title:       input.Title,
+		Description: input.Description,
+	}
+	if err := h.service.SaveTranslation(r.Context(), translation); err != nil {
+		if errors.Is(err, services.ErrInvalidLanguage) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_language", "property", nil)
+			return
+		}
+		if errors.Is(err, domain.ErrPropertyNotFound) {
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "property", nil)
+			return
+		}
+		slog.Error("Error saving translation", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al guardar la traducción", "translation_error", "property", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translation)
+}
+
+// DeleteTranslation: Elimina la traducción de un idioma
+func (h *PropertyHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "property", nil)
+		return
+	}
+	if err := h.service.DeleteTranslation(r.Context(), id, r.PathValue("lang")); err != nil {
+		if errors.Is(err, domain.ErrTranslationNotFound) {
+			writeError(w, http.StatusNotFound, "Traducción no encontrada", "translation_not_found", "property", nil)
+			return
+		}
+		slog.Error("Error deleting translation", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al eliminar la traducción", "translation_error", "property", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requestLanguages obtiene los idiomas preferidos: ?lang= tiene prioridad sobre Accept-Language
+func requestLanguages(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		normalized := domain.NormalizeLanguage(lang)
+		if normalized == "" {
+			writeError(w, http.StatusBadRequest, "Idioma no soportado", "invalid_language", "property", map[string]interface{}{
+				"supported": domain.SupportedLanguages,
+			})
+			return nil, false
+		}
+		return []string{normalized}, true
+	}
+	return domain.ParseAcceptLanguage(r.Header.Get("Accept-Language")), true
+}
+
+// resolveLocation valida la ubicación contra el catálogo y escribe el error si no existe
+func (h *PropertyHandler) resolveLocation(w http.ResponseWriter, r *http.Request, department, city string, zone int) (*domain.GeoLocation, bool) {
+	loc, err := h.geo.Resolve(r.Context(), department, city, zone)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownLocation) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_location", "property", nil)
+			return nil, false
+		}
+		slog.Error("Error resolving location", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al validar la ubicación", "geo_error", "property", nil)
+		return nil, false
+	}
+	return loc, true
+}
+
+// canViewRegistry indica si el usuario puede ver y buscar datos registrales
+func (h *PropertyHandler) canViewRegistry(r *http.Request) bool {
+	userID, _ := r.Context().Value("user_id").(string)
+	if userID == "" {
+		return false
+	}
+	permissions, err := h.auth.GetUserPermissions(r.Context(), userID)
+	if err != nil {
+		slog.Warn("Error loading permissions", "user_id", userID, "error", err)
+		return false
+	}
+	for _, p := range permissions {
+		if p.Name == permissionViewRegistry {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRegistryConflict responde 409 si otra propiedad activa usa la misma identidad registral
+func writeRegistryConflict(w http.ResponseWriter, err error) bool {
+	var conflict *domain.RegistryConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	meta := map[string]interface{}{"field": conflict.Field}
+	if conflict.PropertyID != 0 {
+		meta["property_id"] = conflict.PropertyID
+	}
+	writeError(w, http.StatusConflict, "Otra propiedad activa tiene los mismos datos registrales", "registry_conflict", "property", meta)
+	return true
+}
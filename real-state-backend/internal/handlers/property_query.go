@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/dto"
+)
+
+// maxSortFields limita los criterios de ?sort=
+const maxSortFields = 3
+
+// parsePropertyFilter lee los filtros y el orden del listado; escribe el error si alguno es inválido
+func parsePropertyFilter(w http.ResponseWriter, r *http.Request) (domain.PropertyFilter, bool) {
+	// Filtros de área expresados en cualquier unidad (?min_area=&max_area=&area_unit=v2)
+	filter := domain.PropertyFilter{Type: r.URL.Query().Get("type")}
+	// Filtro por zona/colonia del polígono asignado (?zone=Cayalá)
+	filter.ZoneName = r.URL.Query().Get("zone")
+	// Filtros por catálogo geográfico (?department_id=&municipality_id=&zone_id=)
+	for param, target := range map[string]*int64{"department_id": &filter.DepartmentID, "municipality_id": &filter.MunicipalityID, "zone_id": &filter.ZoneID} {
+		if v := r.URL.Query().Get(param); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id < 1 {
+				writeError(w, http.StatusBadRequest, "Filtro de ubicación inválido", "invalid_location_filter", "property", nil)
+				return filter, false
+			}
+			*target = id
+		}
+	}
+	unit := r.URL.Query().Get("area_unit")
+	if !domain.IsValidAreaUnit(unit) {
+		writeError(w, http.StatusBadRequest, "Unidad de área inválida", "invalid_area_unit", "property", nil)
+		return filter, false
+	}
+	for param, target := range map[string]*float64{"min_area": &filter.MinAreaSqM, "max_area": &filter.MaxAreaSqM} {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil || value < 0 {
+			writeError(w, http.StatusBadRequest, "Filtro de área inválido", "invalid_area_filter", "property", nil)
+			return filter, false
+		}
+		*target, _ = domain.AreaToSqM(value, unit)
+	}
+
+	// Orden (?sort=price,-created_at)
+	var v dto.Validator
+	filter.Sort = parseSort(r.URL.Query().Get("sort"), &v)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, http.StatusBadRequest, err, "property")
+		return filter, false
+	}
+	return filter, true
+}
+
+// parseRegistryFilter lee ?finca=&folio=&libro=&iusi=; indica si se envió alguno
+func parseRegistryFilter(r *http.Request, filter *domain.PropertyFilter) bool {
+	q := r.URL.Query()
+	for param, target := range map[string]*string{"finca": &filter.RegistryFinca, "folio": &filter.RegistryFolio, "libro": &filter.RegistryLibro, "iusi": &filter.RegistryIUSI} {
+		*target = strings.ToUpper(strings.TrimSpace(q.Get(param)))
+	}
+	return filter.RegistryFinca != "" || filter.RegistryFolio != "" || filter.RegistryLibro != "" || filter.RegistryIUSI != ""
+}
+
+// parseFields valida ?fields=title,price contra domain.PropertyFields
+func parseFields(raw string, v *dto.Validator) []string {
+	if raw == "" {
+		return nil
+	}
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || slices.Contains(fields, f) {
+			continue
+		}
+		if !slices.Contains(domain.PropertyFields, f) {
+			v.Add("fields", dto.CodeInvalidChoice, "Campo desconocido: "+f)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// parseSort valida ?sort=price,-created_at; el prefijo "-" indica orden descendente
+func parseSort(raw string, v *dto.Validator) []domain.PropertySort {
+	if raw == "" {
+		return nil
+	}
+	var sorts []domain.PropertySort
+	seen := map[string]bool{}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		s := domain.PropertySort{Field: strings.TrimPrefix(f, "-"), Desc: strings.HasPrefix(f, "-")}
+		switch {
+		case s.Field == "":
+			continue
+		case !slices.Contains(domain.PropertySortFields, s.Field):
+			v.Add("sort", dto.CodeInvalidChoice, "No se puede ordenar por: "+s.Field)
+		case seen[s.Field]:
+			v.Add("sort", dto.CodeConflict, "Campo de orden repetido: "+s.Field)
+		default:
+			seen[s.Field] = true
+			sorts = append(sorts, s)
+		}
+	}
+	if len(sorts) > maxSortFields {
+		v.Add("sort", dto.CodeOutOfRange, "Máximo 3 criterios de orden")
+	}
+	return sorts
+}
+
+// projectProperties reduce cada propiedad a los campos pedidos (el id siempre se incluye)
+func projectProperties(properties []domain.Property, fields []string) any {
+	if len(fields) == 0 {
+		return properties
+	}
+	projected := make([]any, len(properties))
+	for i := range properties {
+		projected[i] = projectProperty(&properties[i], fields)
+	}
+	return projected
+}
+
+func projectProperty(p *domain.Property, fields []string) any {
+	if len(fields) == 0 {
+		return p
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return p
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return p
+	}
+	picked := map[string]json.RawMessage{"id": object["id"]}
+	for _, f := range fields {
+		if value, ok := object[f]; ok {
+			picked[f] = value
+		}
+	}
+	return picked
+}
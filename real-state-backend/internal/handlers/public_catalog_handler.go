@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/ports"
+)
+
+type PublicCatalogHandler struct {
+	service ports.PublicCatalogService
+	maxAge  time.Duration
+}
+
+// NewPublicCatalogHandler crea el handler del catálogo público; maxAge se anuncia en Cache-Control
+func NewPublicCatalogHandler(s ports.PublicCatalogService, maxAge time.Duration) *PublicCatalogHandler {
+	return &PublicCatalogHandler{service: s, maxAge: maxAge}
+}
+
+// List: Propiedades publicadas con los mismos filtros y orden que /properties
+func (h *PublicCatalogHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	filter, ok := parsePropertyFilter(w, r)
+	if !ok {
+		return
+	}
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+
+	properties, err := h.service.List(r.Context(), filter, page, languages)
+	if err != nil {
+		slog.Error("Error listing public properties", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar propiedades", "list_properties_error", "public", nil)
+		return
+	}
+	h.writeCached(w, properties)
+}
+
+// Get: Detalle de una propiedad publicada
+func (h *PublicCatalogHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "public", nil)
+		return
+	}
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+
+	property, err := h.service.Get(r.Context(), id, languages)
+	if err != nil {
+		// No se distingue entre inexistente y no publicada
+		writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "public", nil)
+		return
+	}
+	w.Header().Set("Content-Language", property.Language)
+	h.writeCached(w, property)
+}
+
+func (h *PublicCatalogHandler) writeCached(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	json.NewEncoder(w).Encode(body)
+}
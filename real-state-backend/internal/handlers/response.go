@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"net/http"
 	"real-state-backend/internal/dto"
@@ -9,17 +10,34 @@ import (
 
 // writeError escribe la respuesta de error estandarizada y añade un trace id
 func writeError(w http.ResponseWriter, status int, message, errorCode, tracePrefix string, meta map[string]interface{}) {
-	trace := tracePrefix + "-" + uuid.New().String()
-	resp := dto.ErrorResponse{
+	writeErrorResponse(w, dto.ErrorResponse{
 		Status:    "error",
 		Code:      status,
 		Message:   message,
 		ErrorCode: errorCode,
 		Meta:      meta,
-		TraceID:   trace,
+	}, tracePrefix)
+}
+
+// writeValidationError responde con todas las violaciones por campo del DTO
+func writeValidationError(w http.ResponseWriter, status int, err error, tracePrefix string) {
+	resp := dto.ErrorResponse{
+		Status:    "error",
+		Code:      status,
+		Message:   err.Error(),
+		ErrorCode: "validation_error",
+	}
+	var violations dto.ValidationErrors
+	if errors.As(err, &violations) {
+		resp.Errors = violations
 	}
+	writeErrorResponse(w, resp, tracePrefix)
+}
+
+func writeErrorResponse(w http.ResponseWriter, resp dto.ErrorResponse, tracePrefix string) {
+	resp.TraceID = tracePrefix + "-" + uuid.New().String()
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Trace-ID", trace)
-	w.WriteHeader(status)
+	w.Header().Set("X-Trace-ID", resp.TraceID)
+	w.WriteHeader(resp.Code)
 	json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type SavedSearchHandler struct {
+	service ports.SavedSearchService
+}
+
+func NewSavedSearchHandler(s ports.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: s}
+}
+
+// List: Búsquedas guardadas del usuario autenticado
+func (h *SavedSearchHandler) List(w http.ResponseWriter, r *http.Request) {
+	searches, err := h.service.List(r.Context())
+	if err != nil {
+		writeSavedSearchError(w, err, "Error al listar búsquedas guardadas")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(searches)
+}
+
+// Create: Guarda una búsqueda; las propiedades nuevas que la cumplan llegan por correo
+func (h *SavedSearchHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateSavedSearchDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "saved_search", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "saved_search")
+		return
+	}
+	search := &domain.SavedSearch{Name: input.Name, Criteria: input.Criteria}
+	if err := h.service.Create(r.Context(), search); err != nil {
+		writeSavedSearchError(w, err, "Error al guardar búsqueda")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(search)
+}
+
+// Delete: Elimina una búsqueda guardada del usuario
+func (h *SavedSearchHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "saved_search", nil)
+		return
+	}
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		writeSavedSearchError(w, err, "Error al eliminar búsqueda")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSavedSearchError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrSavedSearchNotFound):
+		writeError(w, http.StatusNotFound, "Búsqueda no encontrada", "saved_search_not_found", "saved_search", nil)
+	case errors.Is(err, domain.ErrSavedSearchLimit):
+		writeError(w, http.StatusConflict, "Se alcanzó el máximo de búsquedas guardadas", "saved_search_limit", "saved_search", nil)
+	case errors.Is(err, domain.ErrTenantRequired):
+		writeError(w, http.StatusForbidden, "El usuario no pertenece a una agencia", "tenant_required", "saved_search", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "saved_searches_error", "saved_search", nil)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+	"real-state-backend/internal/services"
+)
+
+type ShareLinkHandler struct {
+	service    ports.ShareLinkService
+	properties ports.PropertyService
+	analytics  ports.AnalyticsService
+	precision  int // Decimales de lat/lng expuestos, como en el catálogo público
+}
+
+func NewShareLinkHandler(s ports.ShareLinkService, properties ports.PropertyService, analytics ports.AnalyticsService, coordinatePrecision int) *ShareLinkHandler {
+	return &ShareLinkHandler{service: s, properties: properties, analytics: analytics, precision: coordinatePrecision}
+}
+
+// Create: Genera un enlace público firmado; el token solo se muestra en esta respuesta
+func (h *ShareLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "share", nil)
+		return
+	}
+	var input dto.CreateShareLinkDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "share", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "share")
+		return
+	}
+
+	link, err := h.service.Create(r.Context(), id, time.Duration(input.ExpiresInHours)*time.Hour)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPropertyNotListed):
+			writeError(w, http.StatusConflict, "Solo se pueden compartir propiedades publicadas o reservadas", "property_not_listed", "share", nil)
+		case errors.Is(err, services.ErrInvalidShareLinkTTL):
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "validation_error", "share", nil)
+		case errors.Is(err, domain.ErrPropertyNotFound):
+			writeError(w, http.StatusNotFound, "Propiedad no encontrada", "property_not_found", "share", nil)
+		default:
+			slog.Error("Error creating share link", "property_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "Error al crear el enlace", "share_links_error", "share", nil)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"link": link,
+		"path": "/public/share/" + link.Token,
+	})
+}
+
+// List: Enlaces de la propiedad con sus aperturas
+func (h *ShareLinkHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "share", nil)
+		return
+	}
+	links, err := h.service.List(r.Context(), id)
+	if err != nil {
+		slog.Error("Error listing share links", "property_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar enlaces", "share_links_error", "share", nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// Revoke: Invalida el enlace de inmediato
+func (h *ShareLinkHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	linkID, errLink := strconv.ParseInt(r.PathValue("link"), 10, 64)
+	if err != nil || errLink != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "share", nil)
+		return
+	}
+	if err := h.service.Revoke(r.Context(), id, linkID); err != nil {
+		if errors.Is(err, domain.ErrShareLinkNotFound) {
+			writeError(w, http.StatusNotFound, "Enlace no encontrado o ya revocado", "share_link_not_found", "share", nil)
+			return
+		}
+		slog.Error("Error revoking share link", "link_id", linkID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al revocar enlace", "share_links_error", "share", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Open: Endpoint público (sin JWT) que muestra la propiedad de un enlace válido
+func (h *ShareLinkHandler) Open(w http.ResponseWriter, r *http.Request) {
+	languages, ok := requestLanguages(w, r)
+	if !ok {
+		return
+	}
+	property, link, err := h.service.Open(r.Context(), r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrShareLinkInvalid) {
+			writeError(w, http.StatusGone, "El enlace no es válido o ya venció", "share_link_invalid", "share", nil)
+			return
+		}
+		slog.Error("Error opening share link", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al abrir el enlace", "share_links_error", "share", nil)
+		return
+	}
+	localized := []domain.Property{*property}
+	if err := h.properties.Localize(r.Context(), localized, languages); err != nil {
+		slog.Warn("Error loading translations", "property_id", property.ID, "error", err)
+	}
+	// Endpoint anónimo: misma proyección que el catálogo público (sin responsable,
+	// datos registrales ni coordenadas exactas)
+	public := domain.NewPublicProperty(&localized[0], h.precision)
+
+	h.analytics.TrackView(viewerEvent(r, property.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", public.Language)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"property":   public,
+		"expires_at": link.ExpiresAt,
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+	"real-state-backend/internal/services"
+)
+
+type ValuationHandler struct {
+	service ports.ValuationService
+}
+
+func NewValuationHandler(s ports.ValuationService) *ValuationHandler {
+	return &ValuationHandler{service: s}
+}
+
+// Estimate: Estimación de precio a partir de propiedades comparables
+func (h *ValuationHandler) Estimate(w http.ResponseWriter, r *http.Request) {
+	var input dto.ValuationRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "valuation", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "valuation")
+		return
+	}
+
+	areaSqM, _ := domain.AreaToSqM(input.AreaSqM, input.AreaUnit)
+	criteria := domain.ComparableCriteria{
+		Type:     input.Type,
+		City:     input.City,
+		AreaSqM:  areaSqM,
+		Bedrooms: input.Bedrooms,
+		RadiusKm: input.RadiusKm,
+	}
+	if input.Lat != nil {
+		criteria.Lat, criteria.Lng = *input.Lat, *input.Lng
+	}
+
+	estimate, err := h.service.Estimate(r.Context(), criteria, input.Currency)
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientComparables) {
+			writeError(w, http.StatusUnprocessableEntity, "No hay suficientes propiedades comparables", "insufficient_comparables", "valuation", nil)
+			return
+		}
+		slog.Error("Error estimating valuation", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al calcular la valuación", "valuation_error", "valuation", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(estimate)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/dto"
+)
+
+type WebhookHandler struct {
+	service ports.WebhookService
+}
+
+func NewWebhookHandler(s ports.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: s}
+}
+
+// List: Suscripciones de la agencia
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.List(r.Context())
+	if err != nil {
+		writeWebhookError(w, err, "Error al listar webhooks")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subs)
+}
+
+// Create: Registra una suscripción; la respuesta incluye el secreto (única vez)
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateWebhookDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "webhook", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "webhook")
+		return
+	}
+	sub := &domain.WebhookSubscription{URL: input.URL, Description: input.Description, EventTypes: input.EventTypes}
+	if err := h.service.Create(r.Context(), sub); err != nil {
+		writeWebhookError(w, err, "Error al registrar webhook")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	sub, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, err, "Error al obtener webhook")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// Update: Modifica URL, eventos o estado, o rota el secreto
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	var input dto.UpdateWebhookDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON", "invalid_json", "webhook", nil)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		writeValidationError(w, http.StatusUnprocessableEntity, err, "webhook")
+		return
+	}
+	sub, err := h.service.Update(r.Context(), id, domain.WebhookSubscriptionChanges{
+		URL:          input.URL,
+		Description:  input.Description,
+		EventTypes:   input.EventTypes,
+		Active:       input.Active,
+		RotateSecret: input.RotateSecret,
+	})
+	if err != nil {
+		writeWebhookError(w, err, "Error al actualizar webhook")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		writeWebhookError(w, err, "Error al eliminar webhook")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ping: Encola un evento webhook.ping para probar el receptor
+func (h *WebhookHandler) Ping(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := h.service.Ping(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, err, "Error al encolar ping")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// ListDeliveries: Bitácora de entregas (?status=pending|succeeded|failed&limit=50)
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryFailed:
+	default:
+		writeError(w, http.StatusBadRequest, "Estado inválido", "invalid_status", "webhook", nil)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	deliveries, err := h.service.ListDeliveries(r.Context(), id, status, limit)
+	if err != nil {
+		writeWebhookError(w, err, "Error al listar entregas")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// GetDelivery: Entrega con su historial de intentos
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := h.service.GetDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		writeWebhookError(w, err, "Error al obtener entrega")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// Redeliver: Reenvía el evento como una entrega nueva
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := h.service.Redeliver(r.Context(), id, deliveryID)
+	if err != nil {
+		writeWebhookError(w, err, "Error al reenviar entrega")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+func parseWebhookPath(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "webhook", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func parseDeliveryPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	deliveryID, errDelivery := strconv.ParseInt(r.PathValue("delivery"), 10, 64)
+	if err != nil || errDelivery != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "webhook", nil)
+		return 0, 0, false
+	}
+	return id, deliveryID, true
+}
+
+func writeWebhookError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		writeError(w, http.StatusNotFound, "Webhook no encontrado", "webhook_not_found", "webhook", nil)
+	case errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		writeError(w, http.StatusNotFound, "Entrega no encontrada", "webhook_delivery_not_found", "webhook", nil)
+	case errors.Is(err, domain.ErrWebhookURLNotAllowed):
+		writeError(w, http.StatusUnprocessableEntity, "La URL debe resolver a una dirección pública", "webhook_url_not_allowed", "webhook", nil)
+	case errors.Is(err, domain.ErrTenantRequired):
+		writeError(w, http.StatusForbidden, "El usuario no pertenece a una agencia", "tenant_required", "webhook", nil)
+	default:
+		slog.Error(message, "error", err)
+		writeError(w, http.StatusInternalServerError, message, "webhooks_error", "webhook", nil)
+	}
+}
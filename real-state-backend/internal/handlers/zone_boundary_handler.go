@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"real-state-backend/internal/core/ports"
+	"real-state-backend/internal/services"
+)
+
+// maxGeoJSONSize limita el tamaño de los archivos de polígonos cargados
+const maxGeoJSONSize = 10 << 20
+
+type ZoneBoundaryHandler struct {
+	service ports.ZoneBoundaryService
+}
+
+func NewZoneBoundaryHandler(s ports.ZoneBoundaryService) *ZoneBoundaryHandler {
+	return &ZoneBoundaryHandler{service: s}
+}
+
+// Import: Carga un GeoJSON (Feature o FeatureCollection) con polígonos de zonas/colonias
+func (h *ZoneBoundaryHandler) Import(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGeoJSONSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "Archivo demasiado grande", "payload_too_large", "zones", nil)
+		return
+	}
+
+	boundaries, err := h.service.Import(r.Context(), body)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidGeoJSON) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), "invalid_geojson", "zones", nil)
+			return
+		}
+		slog.Error("Error importing zone boundaries", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al cargar polígonos", "zones_error", "zones", nil)
+		return
+	}
+
+	// La geometría ya la tiene el cliente; se responde solo con los metadatos
+	for i := range boundaries {
+		boundaries[i].Geometry = nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(boundaries)
+}
+
+// List: Polígonos cargados; la geometría solo se incluye con ?geometry=true
+func (h *ZoneBoundaryHandler) List(w http.ResponseWriter, r *http.Request) {
+	boundaries, err := h.service.List(r.Context())
+	if err != nil {
+		slog.Error("Error listing zone boundaries", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error al listar polígonos", "zones_error", "zones", nil)
+		return
+	}
+	if r.URL.Query().Get("geometry") != "true" {
+		for i := range boundaries {
+			boundaries[i].Geometry = nil
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(boundaries)
+}
+
+// Delete: Elimina un polígono; las propiedades pierden la colonia pero conservan la zona del catálogo
+func (h *ZoneBoundaryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, "ID inválido", "invalid_id", "zones", nil)
+		return
+	}
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		writeError(w, http.StatusNotFound, "Polígono no encontrado", "zone_boundary_not_found", "zones", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
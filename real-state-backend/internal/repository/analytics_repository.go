@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"time"
+)
+
+type analyticsRepo struct {
+	db *sql.DB
+}
+
+// NewAnalyticsRepository crea una instancia del repositorio de analítica.
+func NewAnalyticsRepository(db *sql.DB) ports.AnalyticsRepository {
+	return &analyticsRepo{db: db}
+}
+
+func (r *analyticsRepo) RecordEvent(ctx context.Context, event *domain.PropertyEvent) error {
+	// El índice único parcial deduplica vistas y favoritos por usuario/dispositivo y día
+	query := `INSERT INTO property_events
+              (property_id, event_type, user_id, device_id, viewer_key, event_date, metadata, created_at)
+              SELECT $1, $2, $3, $4, $5, $6, $7, $8
+              WHERE ` + inTenant("$1::int", 9) + `
+              ON CONFLICT DO NOTHING`
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	metadataJSON, _ := json.Marshal(event.Metadata)
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		event.PropertyID, event.EventType, event.UserID, event.DeviceID, event.ViewerKey,
+		event.CreatedAt.Format("2006-01-02"), metadataJSON, event.CreatedAt, tenantID(ctx))
+	return err
+}
+
+func (r *analyticsRepo) GetDailyStats(ctx context.Context, propertyID int64, from, to time.Time) ([]domain.PropertyDailyStats, error) {
+	// generate_series rellena con ceros los días sin eventos
+	query := `SELECT d::date,
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'view'),
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'favorite'),
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'inquiry')
+              FROM generate_series($2::date, $3::date, INTERVAL '1 day') AS d
+              LEFT JOIN property_events e ON e.property_id = $1 AND e.event_date = d::date AND ` + inTenant("e.property_id", 4) + `
+              GROUP BY d
+              ORDER BY d`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, propertyID, from.Format("2006-01-02"), to.Format("2006-01-02"), tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.PropertyDailyStats, 0)
+	for rows.Next() {
+		var s domain.PropertyDailyStats
+		var day time.Time
+		if err := rows.Scan(&day, &s.Views, &s.Favorites, &s.Inquiries); err != nil {
+			return nil, err
+		}
+		s.Date = day.Format("2006-01-02")
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *analyticsRepo) GetTopViewed(ctx context.Context, from, to time.Time, limit int) ([]domain.PropertyViewRank, error) {
+	query := `SELECT p.id, p.title, COALESCE(p.city, ''), p.type,
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'view') AS views,
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'favorite'),
+                     COUNT(e.id) FILTER (WHERE e.event_type = 'inquiry')
+              FROM property_events e
+              JOIN properties p ON p.id = e.property_id
+              WHERE e.event_date BETWEEN $1 AND $2 AND ` + tenantFilter("p.organization_id", 4) + `
+              GROUP BY p.id, p.title, p.city, p.type
+              ORDER BY views DESC, p.id
+              LIMIT $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"), limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ranking := make([]domain.PropertyViewRank, 0)
+	for rows.Next() {
+		var rank domain.PropertyViewRank
+		if err := rows.Scan(&rank.PropertyID, &rank.Title, &rank.City, &rank.Type,
+			&rank.Views, &rank.Favorites, &rank.Inquiries); err != nil {
+			return nil, err
+		}
+		ranking = append(ranking, rank)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ranking, nil
+}
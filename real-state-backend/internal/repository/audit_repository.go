@@ -19,15 +19,30 @@ func NewAuditRepository(db *sql.DB) *AuditRepository {
 
 func (r *AuditRepository) LogEvent(ctx context.Context, log *domain.AuditLog) error {
 	query := `
-		INSERT INTO audit_logs (id, event_type, user_id, resource, action, old_values, new_values, ip_address, user_agent, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
-
-	log.ID = uuid.New().String()
+		INSERT INTO audit_logs (id, event_type, user_id, resource, action, old_values, new_values, ip_address, user_agent, timestamp, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		        COALESCE(NULLIF($11, '')::uuid, (SELECT organization_id FROM users WHERE id = $3)))
+		ON CONFLICT (id) DO NOTHING`
+
+	// El ID puede venir del evento del outbox: reintentar la entrega no duplica el registro
+	if log.ID == "" {
+		log.ID = uuid.New().String()
+	}
+	// Sin organización explícita se usa la del contexto o, en su defecto, la del usuario (ej. login)
+	if log.OrganizationID == nil {
+		if id := ownerTenant(ctx); id != "" {
+			log.OrganizationID = &id
+		}
+	}
+	var organizationID string
+	if log.OrganizationID != nil {
+		organizationID = *log.OrganizationID
+	}
 
 	oldJSON, _ := json.Marshal(log.OldValues)
 	newJSON, _ := json.Marshal(log.NewValues)
 
-	_, err := r.db.ExecContext(ctx, query, log.ID, log.EventType, log.UserID, log.Resource, log.Action,
-		oldJSON, newJSON, log.IPAddress, log.UserAgent, log.Timestamp)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, log.ID, log.EventType, log.UserID, log.Resource, log.Action,
+		oldJSON, newJSON, log.IPAddress, log.UserAgent, log.Timestamp, organizationID)
 	return err
 }
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type commissionRepo struct {
+	db *sql.DB
+}
+
+// NewCommissionRepository crea una instancia del repositorio de comisiones.
+func NewCommissionRepository(db *sql.DB) ports.CommissionRepository {
+	return &commissionRepo{db: db}
+}
+
+const commissionRuleColumns = `operation_type, rate_percent, listing_split_percent, agency_share_percent, iva_percent, updated_by, updated_at`
+
+func scanCommissionRule(row interface{ Scan(...any) error }, r *domain.CommissionRule) error {
+	return row.Scan(&r.OperationType, &r.RatePercent, &r.ListingSplitPercent, &r.AgencySharePercent,
+		&r.IVAPercent, &r.UpdatedBy, &r.UpdatedAt)
+}
+
+const commissionEntryColumns = `id, settlement_id, property_id, participant, agent_id, roles, base_amount, iva_amount, total_amount, currency, entry_date, created_at`
+
+func scanCommissionEntry(row interface{ Scan(...any) error }, e *domain.CommissionEntry) error {
+	return row.Scan(&e.ID, &e.SettlementID, &e.PropertyID, &e.Participant, &e.AgentID, pq.Array(&e.Roles),
+		&e.BaseAmount, &e.IVAAmount, &e.TotalAmount, &e.Currency, &e.EntryDate, &e.CreatedAt)
+}
+
+// ListRules retorna las reglas de la agencia del contexto (sin organización no hay reglas)
+func (r *commissionRepo) ListRules(ctx context.Context) ([]domain.CommissionRule, error) {
+	query := `SELECT ` + commissionRuleColumns + ` FROM commission_rules WHERE organization_id::text = $1 ORDER BY operation_type`
+	rows, err := r.db.QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]domain.CommissionRule, 0)
+	for rows.Next() {
+		var rule domain.CommissionRule
+		if err := scanCommissionRule(rows, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *commissionRepo) GetRule(ctx context.Context, operationType string) (*domain.CommissionRule, error) {
+	query := `SELECT ` + commissionRuleColumns + ` FROM commission_rules WHERE operation_type = $1 AND organization_id::text = $2`
+
+	var rule domain.CommissionRule
+	if err := scanCommissionRule(conn(ctx, r.db).QueryRowContext(ctx, query, operationType, tenantID(ctx)), &rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCommissionRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *commissionRepo) SaveRule(ctx context.Context, rule *domain.CommissionRule) error {
+	organizationID := ownerTenant(ctx)
+	if organizationID == "" {
+		return domain.ErrTenantRequired
+	}
+	query := `INSERT INTO commission_rules (operation_type, rate_percent, listing_split_percent, agency_share_percent, iva_percent, updated_by, updated_at, organization_id)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              ON CONFLICT (organization_id, operation_type) DO UPDATE
+              SET rate_percent = EXCLUDED.rate_percent, listing_split_percent = EXCLUDED.listing_split_percent,
+                  agency_share_percent = EXCLUDED.agency_share_percent, iva_percent = EXCLUDED.iva_percent,
+                  updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+	rule.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, query, rule.OperationType, rule.RatePercent, rule.ListingSplitPercent,
+		rule.AgencySharePercent, rule.IVAPercent, rule.UpdatedBy, rule.UpdatedAt, organizationID)
+	return err
+}
+
+func (r *commissionRepo) CreateSettlement(ctx context.Context, s *domain.CommissionSettlement) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO commission_settlements
+              (property_id, operation_type, closed_at, price, currency, offer_id, listing_agent_id, selling_agent_id,
+               rate_percent, listing_split_percent, agency_share_percent, iva_percent, commission_amount, iva_amount, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+              RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, s.PropertyID, s.OperationType, s.ClosedAt, s.Price, s.Currency, s.OfferID,
+		s.ListingAgentID, s.SellingAgentID, s.Rule.RatePercent, s.Rule.ListingSplitPercent, s.Rule.AgencySharePercent,
+		s.Rule.IVAPercent, s.CommissionAmount, s.IVAAmount, s.CreatedBy).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return domain.ErrCommissionAlreadySettled
+		}
+		return err
+	}
+
+	entryQuery := `INSERT INTO commission_ledger
+                   (settlement_id, property_id, participant, agent_id, roles, base_amount, iva_amount, total_amount, currency, entry_date)
+                   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+                   RETURNING id, created_at`
+	for i := range s.Entries {
+		e := &s.Entries[i]
+		e.SettlementID = s.ID
+		if err := tx.QueryRowContext(ctx, entryQuery, e.SettlementID, e.PropertyID, e.Participant, e.AgentID,
+			pq.Array(e.Roles), e.BaseAmount, e.IVAAmount, e.TotalAmount, e.Currency, e.EntryDate).
+			Scan(&e.ID, &e.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *commissionRepo) ListSettlements(ctx context.Context, propertyID int64) ([]domain.CommissionSettlement, error) {
+	query := `SELECT id, property_id, operation_type, closed_at, price, currency, offer_id, listing_agent_id, selling_agent_id,
+                     rate_percent, listing_split_percent, agency_share_percent, iva_percent, commission_amount, iva_amount,
+                     created_by, created_at
+              FROM commission_settlements
+              WHERE property_id = $1 AND ` + inTenant("property_id", 2) + `
+              ORDER BY closed_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, propertyID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := make([]domain.CommissionSettlement, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var s domain.CommissionSettlement
+		if err := rows.Scan(&s.ID, &s.PropertyID, &s.OperationType, &s.ClosedAt, &s.Price, &s.Currency, &s.OfferID,
+			&s.ListingAgentID, &s.SellingAgentID, &s.Rule.RatePercent, &s.Rule.ListingSplitPercent,
+			&s.Rule.AgencySharePercent, &s.Rule.IVAPercent, &s.CommissionAmount, &s.IVAAmount,
+			&s.CreatedBy, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		s.Rule.OperationType = s.OperationType
+		s.Entries = make([]domain.CommissionEntry, 0)
+		index[s.ID] = len(settlements)
+		settlements = append(settlements, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(settlements) == 0 {
+		return settlements, nil
+	}
+
+	entries, err := r.db.QueryContext(ctx, `SELECT `+commissionEntryColumns+` FROM commission_ledger WHERE property_id = $1 ORDER BY id`, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer entries.Close()
+	for entries.Next() {
+		var e domain.CommissionEntry
+		if err := scanCommissionEntry(entries, &e); err != nil {
+			return nil, err
+		}
+		if i, ok := index[e.SettlementID]; ok {
+			settlements[i].Entries = append(settlements[i].Entries, e)
+		}
+	}
+	if err := entries.Err(); err != nil {
+		return nil, err
+	}
+	return settlements, nil
+}
+
+func (r *commissionRepo) ListAgentEntries(ctx context.Context, agentID string, from, to time.Time) ([]domain.CommissionEntry, error) {
+	query := `SELECT ` + commissionEntryColumns + `
+              FROM commission_ledger
+              WHERE agent_id = $1 AND entry_date >= $2 AND entry_date < $3 AND ` + inTenant("property_id", 4) + `
+              ORDER BY entry_date, id`
+
+	rows, err := r.db.QueryContext(ctx, query, agentID, from, to, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.CommissionEntry, 0)
+	for rows.Next() {
+		var e domain.CommissionEntry
+		if err := scanCommissionEntry(rows, &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-state-backend/internal/core/ports"
+)
+
+type exchangeRateRepo struct {
+	db *sql.DB
+}
+
+// NewExchangeRateRepository crea una instancia del repositorio de tipos de cambio.
+func NewExchangeRateRepository(db *sql.DB) ports.ExchangeRateRepository {
+	return &exchangeRateRepo{db: db}
+}
+
+func (r *exchangeRateRepo) GetRates(ctx context.Context) (map[string]float64, error) {
+	query := `SELECT currency, rate_to_usd FROM exchange_rates`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make(map[string]float64)
+	for rows.Next() {
+		var currency string
+		var rate float64
+		if err := rows.Scan(&currency, &rate); err != nil {
+			return nil, err
+		}
+		rates[currency] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
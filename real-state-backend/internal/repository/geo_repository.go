@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type geoRepo struct {
+	db *sql.DB
+}
+
+// NewGeoRepository crea una instancia del repositorio del catálogo geográfico.
+func NewGeoRepository(db *sql.DB) ports.GeoRepository {
+	return &geoRepo{db: db}
+}
+
+func (r *geoRepo) ListDepartments(ctx context.Context) ([]domain.Department, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, code, name FROM geo_departments ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := make([]domain.Department, 0)
+	for rows.Next() {
+		var d domain.Department
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name); err != nil {
+			return nil, err
+		}
+		departments = append(departments, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+// ListMunicipalities retorna los municipios del departamento (0 = todos)
+func (r *geoRepo) ListMunicipalities(ctx context.Context, departmentID int64) ([]domain.Municipality, error) {
+	query := `SELECT id, department_id, code, name, aliases, lat, lng
+              FROM geo_municipalities
+              WHERE ($1 = 0 OR department_id = $1)
+              ORDER BY code`
+
+	rows, err := r.db.QueryContext(ctx, query, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	municipalities := make([]domain.Municipality, 0)
+	for rows.Next() {
+		var m domain.Municipality
+		if err := rows.Scan(&m.ID, &m.DepartmentID, &m.Code, &m.Name, pq.Array(&m.Aliases), &m.Lat, &m.Lng); err != nil {
+			return nil, err
+		}
+		municipalities = append(municipalities, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return municipalities, nil
+}
+
+// ListZones retorna las zonas del municipio (0 = todas)
+func (r *geoRepo) ListZones(ctx context.Context, municipalityID int64) ([]domain.Zone, error) {
+	query := `SELECT id, municipality_id, number, name
+              FROM geo_zones
+              WHERE ($1 = 0 OR municipality_id = $1)
+              ORDER BY municipality_id, number`
+
+	rows, err := r.db.QueryContext(ctx, query, municipalityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	zones := make([]domain.Zone, 0)
+	for rows.Next() {
+		var z domain.Zone
+		if err := rows.Scan(&z.ID, &z.MunicipalityID, &z.Number, &z.Name); err != nil {
+			return nil, err
+		}
+		zones = append(zones, z)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"strings"
+	"time"
+)
+
+type marketStatsRepo struct {
+	db *sql.DB
+}
+
+// NewMarketStatsRepository crea una instancia del repositorio de estadísticas.
+func NewMarketStatsRepository(db *sql.DB) ports.MarketStatsRepository {
+	return &marketStatsRepo{db: db}
+}
+
+func (r *marketStatsRepo) GetMonthly(ctx context.Context, filter domain.MarketStatsFilter) ([]domain.MarketStat, error) {
+	// Se consulta la vista materializada, nunca la tabla properties directamente.
+	// La vista agrega por agencia; cada organización solo ve sus filas.
+	query := `SELECT month, organization_id, city, type, inventory_count, new_listings, closed_count,
+                     median_price_usd, median_price_per_sqm_usd, avg_days_on_market, price_reductions, unconverted_count
+              FROM market_stats_monthly
+              WHERE month BETWEEN $1 AND $2
+                AND ($3 = '' OR city = $3)
+                AND ($4 = '' OR type = $4)
+                AND ` + tenantFilter("organization_id", 5) + `
+              ORDER BY month, organization_id, city, type`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"),
+		strings.ToLower(strings.TrimSpace(filter.City)), filter.Type, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.MarketStat, 0)
+	for rows.Next() {
+		var s domain.MarketStat
+		var month time.Time
+		var medianPrice, medianPerSqM, avgDays sql.NullFloat64
+		if err := rows.Scan(&month, &s.OrganizationID, &s.City, &s.Type, &s.InventoryCount, &s.NewListings, &s.ClosedCount,
+			&medianPrice, &medianPerSqM, &avgDays, &s.PriceReductions, &s.UnconvertedCount); err != nil {
+			return nil, err
+		}
+		s.Month = month.Format("2006-01")
+		s.Currency = "USD"
+		if medianPrice.Valid {
+			s.MedianPrice = &medianPrice.Float64
+		}
+		if medianPerSqM.Valid {
+			s.MedianPricePerSqM = &medianPerSqM.Float64
+		}
+		if avgDays.Valid {
+			s.AvgDaysOnMarket = &avgDays.Float64
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *marketStatsRepo) Refresh(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY market_stats_monthly`)
+	return err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"time"
+)
+
+type mortgageProductRepo struct {
+	db *sql.DB
+}
+
+// NewMortgageProductRepository crea una instancia del repositorio de productos hipotecarios.
+func NewMortgageProductRepository(db *sql.DB) ports.MortgageProductRepository {
+	return &mortgageProductRepo{db: db}
+}
+
+const mortgageProductColumns = `id, bank_name, product_name, currency, annual_rate, max_term_years,
+                     min_down_payment_pct, max_payment_to_income, active, created_at, updated_at`
+
+func scanMortgageProduct(row interface{ Scan(...any) error }, p *domain.MortgageProduct) error {
+	return row.Scan(&p.ID, &p.BankName, &p.ProductName, &p.Currency, &p.AnnualRate, &p.MaxTermYears,
+		&p.MinDownPaymentPct, &p.MaxPaymentToIncome, &p.Active, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *mortgageProductRepo) List(ctx context.Context, activeOnly bool) ([]domain.MortgageProduct, error) {
+	query := `SELECT ` + mortgageProductColumns + `
+              FROM mortgage_products
+              WHERE ($1 = FALSE OR active = TRUE)
+              ORDER BY bank_name, product_name`
+
+	rows, err := r.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]domain.MortgageProduct, 0)
+	for rows.Next() {
+		var p domain.MortgageProduct
+		if err := scanMortgageProduct(rows, &p); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *mortgageProductRepo) GetByID(ctx context.Context, id int64) (*domain.MortgageProduct, error) {
+	query := `SELECT ` + mortgageProductColumns + ` FROM mortgage_products WHERE id = $1`
+
+	var p domain.MortgageProduct
+	if err := scanMortgageProduct(r.db.QueryRowContext(ctx, query, id), &p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMortgageProductNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *mortgageProductRepo) Create(ctx context.Context, p *domain.MortgageProduct) error {
+	query := `INSERT INTO mortgage_products
+              (bank_name, product_name, currency, annual_rate, max_term_years,
+               min_down_payment_pct, max_payment_to_income, active)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(ctx, query, p.BankName, p.ProductName, p.Currency, p.AnnualRate,
+		p.MaxTermYears, p.MinDownPaymentPct, p.MaxPaymentToIncome, p.Active).
+		Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *mortgageProductRepo) Update(ctx context.Context, p *domain.MortgageProduct) error {
+	query := `UPDATE mortgage_products
+              SET bank_name = $1, product_name = $2, currency = $3, annual_rate = $4, max_term_years = $5,
+                  min_down_payment_pct = $6, max_payment_to_income = $7, active = $8, updated_at = $9
+              WHERE id = $10
+              RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, p.BankName, p.ProductName, p.Currency, p.AnnualRate,
+		p.MaxTermYears, p.MinDownPaymentPct, p.MaxPaymentToIncome, p.Active, time.Now(), p.ID).
+		Scan(&p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrMortgageProductNotFound
+	}
+	return err
+}
+
+func (r *mortgageProductRepo) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM mortgage_products WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrMortgageProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// nominatimMinInterval respeta la política de uso de Nominatim (1 petición por segundo)
+const nominatimMinInterval = time.Second
+
+type nominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+
+	mu       sync.Mutex
+	lastCall time.Time
+}
+
+// NewNominatimGeocoder crea un geocodificador para cualquier API compatible con
+// Nominatim (servidor público, instancia propia o un stub local).
+func NewNominatimGeocoder(baseURL, userAgent string) ports.Geocoder {
+	return &nominatimGeocoder{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// nominatimPlace es un resultado de /search con format=jsonv2
+type nominatimPlace struct {
+	Lat       string `json:"lat"`
+	Lon       string `json:"lon"`
+	PlaceRank int    `json:"place_rank"`
+}
+
+func (g *nominatimGeocoder) Geocode(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+	text := query.Text()
+	if text == "" {
+		return nil, domain.ErrAddressNotFound
+	}
+	params := url.Values{
+		"q":            {text},
+		"format":       {"jsonv2"},
+		"limit":        {"1"},
+		"countrycodes": {"gt"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if err := g.throttle(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim: unexpected status %d", resp.StatusCode)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("nominatim: invalid response: %w", err)
+	}
+	if len(places) == 0 {
+		return nil, domain.ErrAddressNotFound
+	}
+
+	lat, errLat := strconv.ParseFloat(places[0].Lat, 64)
+	lng, errLng := strconv.ParseFloat(places[0].Lon, 64)
+	if errLat != nil || errLng != nil {
+		return nil, fmt.Errorf("nominatim: invalid coordinates %q, %q", places[0].Lat, places[0].Lon)
+	}
+	return &domain.GeocodeResult{
+		Lat:        lat,
+		Lng:        lng,
+		Confidence: placeRankConfidence(places[0].PlaceRank),
+		Source:     domain.GeocodeSourceNominatim,
+	}, nil
+}
+
+// placeRankConfidence traduce el nivel del resultado (30 = edificio, 26 = calle,
+// 20 = colonia, 16 = municipio) a la confianza registrada en la propiedad
+func placeRankConfidence(rank int) float64 {
+	switch {
+	case rank >= 30:
+		return 0.9
+	case rank >= 26:
+		return 0.75
+	case rank >= 17:
+		return 0.5
+	case rank >= 12:
+		return 0.25
+	default:
+		return 0.1
+	}
+}
+
+// throttle espacia las peticiones según nominatimMinInterval
+func (g *nominatimGeocoder) throttle(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if wait := nominatimMinInterval - time.Since(g.lastCall); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	g.lastCall = time.Now()
+	return nil
+}
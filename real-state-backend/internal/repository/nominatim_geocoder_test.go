@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// newNominatimStub responde /search con el cuerpo dado y guarda la última petición
+func newNominatimStub(t *testing.T, status int, body string, last **http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("path = %q, want /search", r.URL.Path)
+		}
+		if last != nil {
+			*last = r
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var testGeocodeQuery = domain.GeocodeQuery{Address: "5a Avenida 10-20", Zone: "Zona 10", City: "Guatemala"}
+
+func TestNominatimGeocoderParsesResult(t *testing.T) {
+	var req *http.Request
+	server := newNominatimStub(t, http.StatusOK,
+		`[{"lat":"14.6012345","lon":"-90.5123456","place_rank":30,"display_name":"5a Avenida"}]`, &req)
+
+	result, err := NewNominatimGeocoder(server.URL+"/", "real-state-test/1.0").Geocode(context.Background(), testGeocodeQuery)
+	if err != nil {
+		t.Fatalf("Geocode: %v", err)
+	}
+	if result.Lat != 14.6012345 || result.Lng != -90.5123456 {
+		t.Errorf("coordinates = %v, %v", result.Lat, result.Lng)
+	}
+	if result.Confidence != 0.9 || result.Source != domain.GeocodeSourceNominatim {
+		t.Errorf("confidence = %v, source = %q", result.Confidence, result.Source)
+	}
+
+	q := req.URL.Query()
+	if q.Get("q") != testGeocodeQuery.Text() || q.Get("format") != "jsonv2" || q.Get("limit") != "1" || q.Get("countrycodes") != "gt" {
+		t.Errorf("query = %v", q)
+	}
+	if req.UserAgent() != "real-state-test/1.0" {
+		t.Errorf("user agent = %q", req.UserAgent())
+	}
+}
+
+func TestNominatimGeocoderConfidenceByPlaceRank(t *testing.T) {
+	tests := []struct {
+		rank int
+		want float64
+	}{
+		{30, 0.9}, {28, 0.75}, {26, 0.75}, {20, 0.5}, {17, 0.5}, {16, 0.25}, {12, 0.25}, {8, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.rank), func(t *testing.T) {
+			server := newNominatimStub(t, http.StatusOK,
+				fmt.Sprintf(`[{"lat":"14.6","lon":"-90.5","place_rank":%d}]`, tt.rank), nil)
+			result, err := NewNominatimGeocoder(server.URL, "test").Geocode(context.Background(), testGeocodeQuery)
+			if err != nil {
+				t.Fatalf("Geocode: %v", err)
+			}
+			if result.Confidence != tt.want {
+				t.Errorf("confidence = %v, want %v", result.Confidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestNominatimGeocoderNotFound(t *testing.T) {
+	server := newNominatimStub(t, http.StatusOK, `[]`, nil)
+	_, err := NewNominatimGeocoder(server.URL, "test").Geocode(context.Background(), testGeocodeQuery)
+	if !errors.Is(err, domain.ErrAddressNotFound) {
+		t.Errorf("error = %v, want ErrAddressNotFound", err)
+	}
+
+	// Sin dirección no se consulta el servicio
+	var req *http.Request
+	server = newNominatimStub(t, http.StatusOK, `[]`, &req)
+	_, err = NewNominatimGeocoder(server.URL, "test").Geocode(context.Background(), domain.GeocodeQuery{Address: "  "})
+	if !errors.Is(err, domain.ErrAddressNotFound) || req != nil {
+		t.Errorf("error = %v, request sent = %v", err, req != nil)
+	}
+}
+
+func TestNominatimGeocoderErrors(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		body   string
+	}{
+		"unavailable":         {http.StatusServiceUnavailable, `simulated failure`},
+		"invalid json":        {http.StatusOK, `{"lat":`},
+		"invalid coordinates": {http.StatusOK, `[{"lat":"north","lon":"-90.5","place_rank":30}]`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newNominatimStub(t, tt.status, tt.body, nil)
+			_, err := NewNominatimGeocoder(server.URL, "test").Geocode(context.Background(), testGeocodeQuery)
+			if err == nil || errors.Is(err, domain.ErrAddressNotFound) {
+				t.Errorf("error = %v, want a provider error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type notificationRepo struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository crea una instancia del repositorio de notificaciones.
+func NewNotificationRepository(db *sql.DB) ports.NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+const notificationColumns = `n.id, n.user_id, n.kind, n.language, n.recipient, n.subject, n.text_body, n.html_body,
+                             n.status, n.attempts, n.next_attempt_at, n.last_error, n.sent_at, n.created_at, n.updated_at`
+
+func scanNotification(row interface{ Scan(...any) error }, n *domain.Notification) error {
+	return row.Scan(&n.ID, &n.UserID, &n.Kind, &n.Language, &n.Recipient, &n.Subject, &n.TextBody, &n.HTMLBody,
+		&n.Status, &n.Attempts, &n.NextAttemptAt, &n.LastError, &n.SentAt, &n.CreatedAt, &n.UpdatedAt)
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	query := `SELECT language, email_enabled, muted_kinds, updated_at FROM notification_preferences WHERE user_id = $1`
+	p := domain.NotificationPreferences{UserID: userID}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).
+		Scan(&p.Language, &p.EmailEnabled, pq.Array(&p.MutedKinds), &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.MutedKinds == nil {
+		p.MutedKinds = []string{}
+	}
+	return &p, nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, p *domain.NotificationPreferences) error {
+	query := `INSERT INTO notification_preferences (user_id, language, email_enabled, muted_kinds, updated_at)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (user_id) DO UPDATE
+              SET language = EXCLUDED.language, email_enabled = EXCLUDED.email_enabled,
+                  muted_kinds = EXCLUDED.muted_kinds, updated_at = EXCLUDED.updated_at`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, p.UserID, p.Language, p.EmailEnabled, pq.Array(p.MutedKinds), p.UpdatedAt)
+	return err
+}
+
+// Enqueue usa la transacción del contexto si la hay; con un DedupeKey repetido no
+// inserta y deja n.ID en 0
+func (r *notificationRepo) Enqueue(ctx context.Context, n *domain.Notification) error {
+	query := `INSERT INTO notifications (user_id, kind, language, recipient, subject, text_body, html_body,
+                                         status, next_attempt_at, dedupe_key, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $11)
+              ON CONFLICT (dedupe_key) DO NOTHING
+              RETURNING id`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, n.UserID, n.Kind, n.Language, n.Recipient, n.Subject,
+		n.TextBody, n.HTMLBody, n.Status, n.NextAttemptAt, n.DedupeKey, n.CreatedAt).Scan(&n.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// ClaimDue usa SKIP LOCKED y adelanta next_attempt_at por el lease: si el proceso
+// cae durante el envío, la notificación se reintenta al vencer
+func (r *notificationRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error) {
+	query := `WITH due AS (
+                  SELECT id FROM notifications
+                  WHERE status = 'pending' AND next_attempt_at <= $1
+                  ORDER BY next_attempt_at
+                  LIMIT $3
+                  FOR UPDATE SKIP LOCKED
+              )
+              UPDATE notifications n
+              SET next_attempt_at = $2
+              FROM due
+              WHERE n.id = due.id
+              RETURNING ` + notificationColumns
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0)
+	for rows.Next() {
+		var n domain.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepo) RecordAttempt(ctx context.Context, n *domain.Notification) error {
+	query := `UPDATE notifications
+              SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5, updated_at = $6
+              WHERE id = $7`
+	_, err := r.db.ExecContext(ctx, query, n.Status, n.Attempts, n.NextAttemptAt, n.LastError, n.SentAt, n.UpdatedAt, n.ID)
+	return err
+}
+
+func (r *notificationRepo) ListByUser(ctx context.Context, userID string, limit int) ([]domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + `
+              FROM notifications n
+              WHERE n.user_id = $1
+              ORDER BY n.id DESC
+              LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0)
+	for rows.Next() {
+		var n domain.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
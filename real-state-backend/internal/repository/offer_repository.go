@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type offerRepo struct {
+	db *sql.DB
+}
+
+// NewOfferRepository crea una instancia del repositorio de ofertas.
+func NewOfferRepository(db *sql.DB) ports.OfferRepository {
+	return &offerRepo{db: db}
+}
+
+const offerColumns = `id, property_id, buyer_id, amount, currency, conditions, expires_at, status, COALESCE(awaiting_party, ''), created_at, updated_at`
+
+func scanOffer(row interface{ Scan(...any) error }, o *domain.Offer) error {
+	return row.Scan(&o.ID, &o.PropertyID, &o.BuyerID, &o.Amount, &o.Currency, &o.Conditions,
+		&o.ExpiresAt, &o.Status, &o.AwaitingParty, &o.CreatedAt, &o.UpdatedAt)
+}
+
+// execer permite insertar eventos dentro o fuera de una transacción
+type execer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertOfferEvent(ctx context.Context, db execer, e *domain.OfferEvent) error {
+	query := `INSERT INTO property_offer_events (offer_id, author_id, party, kind, amount, currency, conditions, expires_at, message)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              RETURNING id, created_at`
+	var amount sql.NullFloat64
+	var currency, conditions sql.NullString
+	var expiresAt sql.NullTime
+	if e.Terms != nil {
+		amount = sql.NullFloat64{Float64: e.Terms.Amount, Valid: true}
+		currency = sql.NullString{String: e.Terms.Currency, Valid: true}
+		conditions = sql.NullString{String: e.Terms.Conditions, Valid: true}
+		expiresAt = sql.NullTime{Time: e.Terms.ExpiresAt, Valid: true}
+	}
+	return db.QueryRowContext(ctx, query, e.OfferID, e.AuthorID, e.Party, e.Kind, amount, currency, conditions, expiresAt, e.Message).
+		Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *offerRepo) Create(ctx context.Context, offer *domain.Offer, event *domain.OfferEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO property_offers (property_id, buyer_id, amount, currency, conditions, expires_at, status, awaiting_party)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              RETURNING id, created_at, updated_at`
+	if err := tx.QueryRowContext(ctx, query, offer.PropertyID, offer.BuyerID, offer.Amount, offer.Currency,
+		offer.Conditions, offer.ExpiresAt, offer.Status, offer.AwaitingParty).
+		Scan(&offer.ID, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
+		return err
+	}
+	event.OfferID = offer.ID
+	if err := insertOfferEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *offerRepo) Get(ctx context.Context, propertyID, id int64) (*domain.Offer, error) {
+	query := `SELECT ` + offerColumns + ` FROM property_offers WHERE id = $1 AND property_id = $2 AND ` + inTenant("property_id", 3)
+
+	var o domain.Offer
+	if err := scanOffer(conn(ctx, r.db).QueryRowContext(ctx, query, id, propertyID, tenantID(ctx)), &o); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrOfferNotFound
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *offerRepo) List(ctx context.Context, propertyID int64, buyerID string) ([]domain.Offer, error) {
+	query := `SELECT ` + offerColumns + `
+              FROM property_offers
+              WHERE property_id = $1 AND ($2 = '' OR buyer_id::text = $2) AND ` + inTenant("property_id", 3) + `
+              ORDER BY created_at DESC, id DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, propertyID, buyerID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offers := make([]domain.Offer, 0)
+	for rows.Next() {
+		var o domain.Offer
+		if err := scanOffer(rows, &o); err != nil {
+			return nil, err
+		}
+		offers = append(offers, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+func (r *offerRepo) ListEvents(ctx context.Context, offerID int64) ([]domain.OfferEvent, error) {
+	query := `SELECT id, offer_id, author_id, party, kind, amount, currency, conditions, expires_at, message, created_at
+              FROM property_offer_events e
+              WHERE offer_id = $1
+                AND EXISTS (SELECT 1 FROM property_offers o WHERE o.id = e.offer_id AND ` + inTenant("o.property_id", 2) + `)
+              ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, offerID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.OfferEvent, 0)
+	for rows.Next() {
+		var e domain.OfferEvent
+		var amount sql.NullFloat64
+		var currency, conditions sql.NullString
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.OfferID, &e.AuthorID, &e.Party, &e.Kind, &amount, &currency,
+			&conditions, &expiresAt, &e.Message, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if amount.Valid {
+			e.Terms = &domain.OfferTerms{Amount: amount.Float64, Currency: currency.String, Conditions: conditions.String, ExpiresAt: expiresAt.Time}
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *offerRepo) AddEvent(ctx context.Context, event *domain.OfferEvent) error {
+	return insertOfferEvent(ctx, r.db, event)
+}
+
+func (r *offerRepo) Transition(ctx context.Context, offer *domain.Offer, fromStatus string, event *domain.OfferEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateOffer(ctx, tx, offer, fromStatus); err != nil {
+		return err
+	}
+	if err := insertOfferEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Accept se une a la transacción del contexto si la hay (ver TxManager)
+func (r *offerRepo) Accept(ctx context.Context, offer *domain.Offer, fromStatus string, event *domain.OfferEvent) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateOffer(ctx, tx.Tx, offer, fromStatus); err != nil {
+		return err
+	}
+	if err := insertOfferEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	// La propiedad pasa a reservada solo si sigue publicada (otra oferta pudo ganar la carrera)
+	res, err := tx.ExecContext(ctx, `UPDATE properties SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`,
+		domain.PropertyStatusReserved, offer.UpdatedAt, offer.PropertyID, domain.PropertyStatusPublished)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrPropertyNotAvailable
+	}
+
+	// Las demás ofertas abiertas quedan rechazadas
+	rows, err := tx.QueryContext(ctx, `UPDATE property_offers SET status = $1, awaiting_party = NULL, updated_at = $2
+              WHERE property_id = $3 AND id <> $4 AND status IN ('submitted', 'countered')
+              RETURNING id`, domain.OfferStatusRejected, offer.UpdatedAt, offer.PropertyID, offer.ID)
+	if err != nil {
+		return err
+	}
+	var rejected []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		rejected = append(rejected, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range rejected {
+		if err := insertOfferEvent(ctx, tx, &domain.OfferEvent{
+			OfferID: id,
+			Party:   domain.OfferPartySystem,
+			Kind:    domain.OfferStatusRejected,
+			Message: "Another offer was accepted",
+		}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// updateOffer guarda estado y términos; falla si otra acción cambió el estado antes
+func updateOffer(ctx context.Context, tx *sql.Tx, offer *domain.Offer, fromStatus string) error {
+	query := `UPDATE property_offers
+              SET amount = $1, currency = $2, conditions = $3, expires_at = $4, status = $5,
+                  awaiting_party = NULLIF($6, ''), updated_at = $7
+              WHERE id = $8 AND status = $9 AND ` + inTenant("property_id", 10)
+	offer.UpdatedAt = time.Now()
+	res, err := tx.ExecContext(ctx, query, offer.Amount, offer.Currency, offer.Conditions, offer.ExpiresAt,
+		offer.Status, offer.AwaitingParty, offer.UpdatedAt, offer.ID, fromStatus, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrInvalidOfferTransition
+	}
+	return nil
+}
+
+func (r *offerRepo) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	query := `WITH expired AS (
+                  UPDATE property_offers SET status = $1, awaiting_party = NULL, updated_at = $2
+                  WHERE status IN ('submitted', 'countered') AND expires_at <= $2
+                  RETURNING id
+              )
+              INSERT INTO property_offer_events (offer_id, party, kind)
+              SELECT id, $3, $1 FROM expired`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, domain.OfferStatusExpired, now, domain.OfferPartySystem)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type organizationRepo struct {
+	db *sql.DB
+}
+
+// NewOrganizationRepository crea una instancia del repositorio de agencias y sus usuarios.
+func NewOrganizationRepository(db *sql.DB) ports.OrganizationRepository {
+	return &organizationRepo{db: db}
+}
+
+const organizationColumns = `id, name, slug, active, created_at, updated_at`
+
+func scanOrganization(row interface{ Scan(...any) error }, o *domain.Organization) error {
+	return row.Scan(&o.ID, &o.Name, &o.Slug, &o.Active, &o.CreatedAt, &o.UpdatedAt)
+}
+
+// roleColumns incluye si el rol otorga algún permiso de plataforma
+const roleColumns = `r.id, r.name, COALESCE(r.description, ''), r.organization_id,
+                     EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id
+                             WHERE rp.role_id = r.id AND p.platform),
+                     r.created_at`
+
+func scanRole(row interface{ Scan(...any) error }, role *domain.Role) error {
+	return row.Scan(&role.ID, &role.Name, &role.Description, &role.OrganizationID, &role.Platform, &role.CreatedAt)
+}
+
+// Create registra la agencia con una copia de las reglas de comisión de la agencia por defecto
+func (r *organizationRepo) Create(ctx context.Context, org *domain.Organization) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO organizations (name, slug, active)
+              VALUES ($1, $2, $3)
+              RETURNING id, created_at, updated_at`
+	if err := tx.QueryRowContext(ctx, query, org.Name, org.Slug, org.Active).
+		Scan(&org.ID, &org.CreatedAt, &org.UpdatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return domain.ErrOrganizationSlugTaken
+		}
+		return err
+	}
+
+	rulesQuery := `INSERT INTO commission_rules (organization_id, operation_type, rate_percent, listing_split_percent, agency_share_percent, iva_percent)
+                   SELECT $1, operation_type, rate_percent, listing_split_percent, agency_share_percent, iva_percent
+                   FROM commission_rules WHERE organization_id = $2`
+	if _, err := tx.ExecContext(ctx, rulesQuery, org.ID, domain.DefaultOrganizationID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *organizationRepo) Get(ctx context.Context, id string) (*domain.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE id = $1`
+
+	var o domain.Organization
+	if err := scanOrganization(r.db.QueryRowContext(ctx, query, id), &o); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *organizationRepo) List(ctx context.Context) ([]domain.Organization, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+organizationColumns+` FROM organizations ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := make([]domain.Organization, 0)
+	for rows.Next() {
+		var o domain.Organization
+		if err := scanOrganization(rows, &o); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepo) Update(ctx context.Context, org *domain.Organization) error {
+	query := `UPDATE organizations SET name = $1, active = $2, updated_at = $3 WHERE id = $4`
+	org.UpdatedAt = time.Now()
+	res, err := r.db.ExecContext(ctx, query, org.Name, org.Active, org.UpdatedAt, org.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+// ListUsers retorna los usuarios de la agencia con sus roles
+func (r *organizationRepo) ListUsers(ctx context.Context, organizationID string) ([]domain.User, error) {
+	query := `SELECT id, username, email, organization_id, created_at, updated_at
+              FROM users
+              WHERE organization_id = $1
+              ORDER BY username`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.OrganizationID, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadRoles(ctx, users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *organizationRepo) GetUser(ctx context.Context, organizationID, userID string) (*domain.User, error) {
+	query := `SELECT id, username, email, organization_id, created_at, updated_at
+              FROM users
+              WHERE id = $1 AND organization_id = $2`
+
+	var u domain.User
+	err := r.db.QueryRowContext(ctx, query, userID, organizationID).
+		Scan(&u.ID, &u.Username, &u.Email, &u.OrganizationID, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	users := []domain.User{u}
+	if err := r.loadRoles(ctx, users); err != nil {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// loadRoles completa los roles de cada usuario
+func (r *organizationRepo) loadRoles(ctx context.Context, users []domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]string, len(users))
+	index := make(map[string]int, len(users))
+	for i := range users {
+		ids[i] = users[i].ID
+		index[users[i].ID] = i
+		users[i].Roles = make([]domain.Role, 0)
+	}
+
+	query := `SELECT ur.user_id, ` + roleColumns + `
+              FROM user_roles ur
+              JOIN roles r ON r.id = ur.role_id
+              WHERE ur.user_id::text = ANY($1)
+              ORDER BY r.name`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID string
+		var role domain.Role
+		if err := rows.Scan(&userID, &role.ID, &role.Name, &role.Description, &role.OrganizationID,
+			&role.Platform, &role.CreatedAt); err != nil {
+			return err
+		}
+		if i, ok := index[userID]; ok {
+			users[i].Roles = append(users[i].Roles, role)
+		}
+	}
+	return rows.Err()
+}
+
+// CreateUser registra el usuario en su agencia con los roles indicados
+func (r *organizationRepo) CreateUser(ctx context.Context, user *domain.User, roleIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users (id, username, email, password_hash, organization_id, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $6)`
+	user.ID = uuid.New().String()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	if _, err := tx.ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash,
+		user.OrganizationID, user.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return domain.ErrUserTaken
+		}
+		return err
+	}
+	if err := insertUserRoles(ctx, tx, user.ID, roleIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetUserRoles reemplaza los roles de un usuario de la agencia
+func (r *organizationRepo) SetUserRoles(ctx context.Context, organizationID, userID string, roleIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Bloquea al usuario para serializar cambios concurrentes de roles
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 AND organization_id = $2 FOR UPDATE`,
+		userID, organizationID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if err := insertUserRoles(ctx, tx, userID, roleIDs); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE users SET updated_at = $1 WHERE id = $2`, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertUserRoles(ctx context.Context, tx *sql.Tx, userID string, roleIDs []string) error {
+	for _, roleID := range roleIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			userID, roleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRoles retorna los roles globales y los propios de la agencia
+func (r *organizationRepo) ListRoles(ctx context.Context, organizationID string) ([]domain.Role, error) {
+	query := `SELECT ` + roleColumns + `
+              FROM roles r
+              WHERE r.organization_id IS NULL OR r.organization_id = $1
+              ORDER BY r.organization_id NULLS FIRST, r.name`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]domain.Role, 0)
+	for rows.Next() {
+		var role domain.Role
+		if err := scanRole(rows, &role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// CreateRole registra un rol propio de la agencia con permisos que no sean de plataforma
+func (r *organizationRepo) CreateRole(ctx context.Context, role *domain.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO roles (name, description, organization_id)
+              VALUES ($1, NULLIF($2, ''), $3)
+              RETURNING id, created_at`
+	if err := tx.QueryRowContext(ctx, query, role.Name, role.Description, role.OrganizationID).
+		Scan(&role.ID, &role.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return domain.ErrRoleNameTaken
+		}
+		return err
+	}
+
+	// Solo se vinculan permisos existentes y de agencia; cualquier otro rechaza el rol
+	permissionsQuery := `INSERT INTO role_permissions (role_id, permission_id)
+                         SELECT $1, p.id FROM permissions p WHERE p.name = ANY($2) AND NOT p.platform
+                         RETURNING permission_id`
+	rows, err := tx.QueryContext(ctx, permissionsQuery, role.ID, pq.Array(role.Permissions))
+	if err != nil {
+		return err
+	}
+	linked := 0
+	for rows.Next() {
+		linked++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if linked != len(role.Permissions) {
+		return domain.ErrPermissionNotAssignable
+	}
+	return tx.Commit()
+}
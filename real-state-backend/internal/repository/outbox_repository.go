@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type outboxRepo struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository crea una instancia del repositorio del outbox.
+func NewOutboxRepository(db *sql.DB) ports.OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+const outboxColumns = `e.id, e.event_id, e.event_type, e.organization_id, e.aggregate_type, e.aggregate_id, e.payload,
+                       e.attempts, e.next_attempt_at, e.last_error, e.published_at, e.created_at`
+
+func scanOutboxEvent(row interface{ Scan(...any) error }, e *domain.OutboxEvent) error {
+	return row.Scan(&e.ID, &e.EventID, &e.Type, &e.OrganizationID, &e.AggregateType, &e.AggregateID, &e.Payload,
+		&e.Attempts, &e.NextAttemptAt, &e.LastError, &e.PublishedAt, &e.CreatedAt)
+}
+
+// Add usa la transacción del contexto; sin organización explícita se usa la de la petición
+func (r *outboxRepo) Add(ctx context.Context, events ...*domain.OutboxEvent) error {
+	query := `INSERT INTO outbox_events (event_type, organization_id, aggregate_type, aggregate_id, payload, next_attempt_at, created_at)
+              VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $6)
+              RETURNING id, event_id`
+
+	now := time.Now().UTC()
+	for _, e := range events {
+		if e.OrganizationID == nil {
+			if id := ownerTenant(ctx); id != "" {
+				e.OrganizationID = &id
+			}
+		}
+		var organizationID string
+		if e.OrganizationID != nil {
+			organizationID = *e.OrganizationID
+		}
+		e.NextAttemptAt, e.CreatedAt = now, now
+		if err := conn(ctx, r.db).QueryRowContext(ctx, query, e.Type, organizationID, e.AggregateType, e.AggregateID,
+			[]byte(e.Payload), now).Scan(&e.ID, &e.EventID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimDue usa SKIP LOCKED y adelanta next_attempt_at por el lease: si el proceso
+// cae antes de marcar el evento, se vuelve a entregar al vencer
+func (r *outboxRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	query := `WITH due AS (
+                  SELECT id FROM outbox_events
+                  WHERE published_at IS NULL AND next_attempt_at <= $1
+                  ORDER BY next_attempt_at, id
+                  LIMIT $3
+                  FOR UPDATE SKIP LOCKED
+              )
+              UPDATE outbox_events e
+              SET next_attempt_at = $2
+              FROM due
+              WHERE e.id = due.id
+              RETURNING ` + outboxColumns
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.OutboxEvent, 0)
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := scanOutboxEvent(rows, &e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING no respeta el orden de la subconsulta
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (r *outboxRepo) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox_events SET published_at = $1, last_error = '' WHERE id = $2`, at, id)
+	return err
+}
+
+func (r *outboxRepo) MarkRetry(ctx context.Context, event *domain.OutboxEvent) error {
+	query := `UPDATE outbox_events SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, event.Attempts, event.NextAttemptAt, event.LastError, event.ID)
+	return err
+}
+
+func (r *outboxRepo) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type passwordResetRepo struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository crea una instancia del repositorio de enlaces de recuperación.
+func NewPasswordResetRepository(db *sql.DB) ports.PasswordResetRepository {
+	return &passwordResetRepo{db: db}
+}
+
+// Create invalida los enlaces pendientes del usuario y registra el nuevo; usa la
+// transacción del contexto si la hay
+func (r *passwordResetRepo) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE password_resets SET used_at = $2 WHERE user_id = $1 AND used_at IS NULL`,
+		reset.UserID, reset.CreatedAt); err != nil {
+		return err
+	}
+	query := `INSERT INTO password_resets (user_id, expires_at, created_at)
+              VALUES ($1, $2, $3)
+              RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, reset.UserID, reset.ExpiresAt, reset.CreatedAt).
+		Scan(&reset.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// IssueToken reemplaza el token del enlace: un reintento del correo invalida el
+// token enviado en el intento anterior
+func (r *passwordResetRepo) IssueToken(ctx context.Context, id int64, userID, tokenHash string, now time.Time) (time.Time, error) {
+	query := `UPDATE password_resets SET token_hash = $3
+              WHERE id = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > $4
+              RETURNING expires_at`
+	var expiresAt time.Time
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, userID, tokenHash, now).Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, domain.ErrPasswordResetInvalid
+	}
+	return expiresAt, err
+}
+
+// Consume marca usado el enlace si sigue vigente; dos peticiones con el mismo token
+// no pueden consumirlo ambas
+func (r *passwordResetRepo) Consume(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	query := `UPDATE password_resets SET used_at = $2
+              WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+              RETURNING user_id`
+	var userID string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash, now).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrPasswordResetInvalid
+	}
+	return userID, err
+}
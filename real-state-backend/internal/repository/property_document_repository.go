@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type propertyDocumentRepo struct {
+	db *sql.DB
+}
+
+// NewPropertyDocumentRepository crea una instancia del repositorio de documentos de propiedades.
+func NewPropertyDocumentRepository(db *sql.DB) ports.PropertyDocumentRepository {
+	return &propertyDocumentRepo{db: db}
+}
+
+const documentVersionColumns = `id, document_id, version, file_name, content_type, size_bytes, checksum_sha256, storage_key, uploaded_by, created_at`
+
+func scanDocumentVersion(row interface{ Scan(...any) error }, v *domain.DocumentVersion) error {
+	return row.Scan(&v.ID, &v.DocumentID, &v.Version, &v.FileName, &v.ContentType, &v.SizeBytes,
+		&v.Checksum, &v.StorageKey, &v.UploadedBy, &v.CreatedAt)
+}
+
+// documentInTenant acota las versiones a documentos de propiedades de la organización
+func documentInTenant(n int) string {
+	return `EXISTS (SELECT 1 FROM property_documents td WHERE td.id = document_id AND ` + inTenant("td.property_id", n) + `)`
+}
+
+func (r *propertyDocumentRepo) CreateDocument(ctx context.Context, doc *domain.PropertyDocument) error {
+	query := `INSERT INTO property_documents (property_id, document_type, title, created_by)
+              VALUES ($1, $2, $3, $4)
+              RETURNING id, created_at, updated_at`
+	return conn(ctx, r.db).QueryRowContext(ctx, query, doc.PropertyID, doc.DocumentType, doc.Title, doc.CreatedBy).
+		Scan(&doc.ID, &doc.CreatedAt, &doc.UpdatedAt)
+}
+
+// AddVersion numera la versión con el documento bloqueado, para que dos cargas
+// simultáneas no obtengan el mismo número, y actualiza updated_at en la misma transacción
+func (r *propertyDocumentRepo) AddVersion(ctx context.Context, v *domain.DocumentVersion) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM property_documents WHERE id = $1 FOR UPDATE`, v.DocumentID).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrDocumentNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO property_document_versions
+              (document_id, version, file_name, content_type, size_bytes, checksum_sha256, storage_key, uploaded_by)
+              SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6, $7
+              FROM property_document_versions WHERE document_id = $1
+              RETURNING id, version, created_at`
+	err = tx.QueryRowContext(ctx, query, v.DocumentID, v.FileName, v.ContentType, v.SizeBytes,
+		v.Checksum, v.StorageKey, v.UploadedBy).Scan(&v.ID, &v.Version, &v.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE property_documents SET updated_at = $1 WHERE id = $2`, v.CreatedAt, v.DocumentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *propertyDocumentRepo) ListDocuments(ctx context.Context, propertyID int64) ([]domain.PropertyDocument, error) {
+	query := `SELECT d.id, d.property_id, d.document_type, d.title, d.created_by, d.created_at, d.updated_at,
+                     v.id, v.document_id, v.version, v.file_name, v.content_type, v.size_bytes,
+                     v.checksum_sha256, v.storage_key, v.uploaded_by, v.created_at
+              FROM property_documents d
+              JOIN LATERAL (
+                  SELECT * FROM property_document_versions
+                  WHERE document_id = d.id
+                  ORDER BY version DESC
+                  LIMIT 1
+              ) v ON true
+              WHERE d.property_id = $1 AND ` + inTenant("d.property_id", 2) + `
+              ORDER BY d.document_type, d.updated_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, propertyID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]domain.PropertyDocument, 0)
+	for rows.Next() {
+		var d domain.PropertyDocument
+		var v domain.DocumentVersion
+		if err := rows.Scan(&d.ID, &d.PropertyID, &d.DocumentType, &d.Title, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt,
+			&v.ID, &v.DocumentID, &v.Version, &v.FileName, &v.ContentType, &v.SizeBytes,
+			&v.Checksum, &v.StorageKey, &v.UploadedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.LatestVersion = &v
+		docs = append(docs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *propertyDocumentRepo) GetDocument(ctx context.Context, propertyID, id int64) (*domain.PropertyDocument, error) {
+	query := `SELECT id, property_id, document_type, title, created_by, created_at, updated_at
+              FROM property_documents
+              WHERE id = $1 AND property_id = $2 AND ` + inTenant("property_id", 3)
+
+	var d domain.PropertyDocument
+	err := r.db.QueryRowContext(ctx, query, id, propertyID, tenantID(ctx)).
+		Scan(&d.ID, &d.PropertyID, &d.DocumentType, &d.Title, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *propertyDocumentRepo) ListVersions(ctx context.Context, documentID int64) ([]domain.DocumentVersion, error) {
+	query := `SELECT ` + documentVersionColumns + `
+              FROM property_document_versions
+              WHERE document_id = $1 AND ` + documentInTenant(2) + `
+              ORDER BY version DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, documentID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]domain.DocumentVersion, 0)
+	for rows.Next() {
+		var v domain.DocumentVersion
+		if err := scanDocumentVersion(rows, &v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *propertyDocumentRepo) GetVersion(ctx context.Context, documentID int64, version int) (*domain.DocumentVersion, error) {
+	query := `SELECT ` + documentVersionColumns + `
+              FROM property_document_versions
+              WHERE document_id = $1 AND ($2 = 0 OR version = $2) AND ` + documentInTenant(3) + `
+              ORDER BY version DESC
+              LIMIT 1`
+
+	var v domain.DocumentVersion
+	if err := scanDocumentVersion(r.db.QueryRowContext(ctx, query, documentID, version, tenantID(ctx)), &v); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
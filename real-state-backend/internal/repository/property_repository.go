@@ -6,6 +6,10 @@ import (
 	"errors"
 	"real-state-backend/internal/core/domain"
 	"real-state-backend/internal/core/ports"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type propertyRepo struct {
@@ -17,30 +21,214 @@ func NewPropertyRepository(db *sql.DB) ports.PropertyRepository {
 	return &propertyRepo{db: db}
 }
 
+// propertyColumn relaciona un campo JSON de domain.Property con su expresión SQL
+type propertyColumn struct {
+	field string
+	expr  string
+	dest  func(p *domain.Property) any
+}
+
+// propertyColumnList son las columnas que se leen para construir un domain.Property
+var propertyColumnList = []propertyColumn{
+	{"id", "id", func(p *domain.Property) any { return &p.ID }},
+	{"title", "title", func(p *domain.Property) any { return &p.Title }},
+	{"description", "COALESCE(description, '')", func(p *domain.Property) any { return &p.Description }},
+	{"source_language", "source_language", func(p *domain.Property) any { return &p.SourceLanguage }},
+	{"price", "price", func(p *domain.Property) any { return &p.Price }},
+	{"currency", "COALESCE(currency, 'USD')", func(p *domain.Property) any { return &p.Currency }},
+	{"address", "address", func(p *domain.Property) any { return &p.Address }},
+	{"city", "COALESCE(city, '')", func(p *domain.Property) any { return &p.City }},
+	{"department_id", "department_id", func(p *domain.Property) any { return &p.DepartmentID }},
+	{"municipality_id", "municipality_id", func(p *domain.Property) any { return &p.MunicipalityID }},
+	{"zone_id", "zone_id", func(p *domain.Property) any { return &p.ZoneID }},
+	{"department", "COALESCE((SELECT name FROM geo_departments WHERE id = department_id), '')", func(p *domain.Property) any { return &p.Department }},
+	{"zone", "COALESCE((SELECT name FROM geo_zones WHERE id = zone_id), '')", func(p *domain.Property) any { return &p.Zone }},
+	{"zone_boundary_id", "zone_boundary_id", func(p *domain.Property) any { return &p.ZoneBoundaryID }},
+	{"neighbourhood", "COALESCE((SELECT name FROM zone_boundaries WHERE id = zone_boundary_id), '')", func(p *domain.Property) any { return &p.Neighbourhood }},
+	{"type", "type", func(p *domain.Property) any { return &p.Type }},
+	{"bedrooms", "COALESCE(bedrooms, 0)", func(p *domain.Property) any { return &p.Bedrooms }},
+	{"bathrooms", "COALESCE(bathrooms, 0)", func(p *domain.Property) any { return &p.Bathrooms }},
+	{"amenities", "amenities", func(p *domain.Property) any { return pq.Array(&p.Amenities) }},
+	{"area_sqm", "COALESCE(area_sqm, 0)", func(p *domain.Property) any { return &p.AreaSqM }},
+	{"lat", "COALESCE(lat, 0)", func(p *domain.Property) any { return &p.Lat }},
+	{"lng", "COALESCE(lng, 0)", func(p *domain.Property) any { return &p.Lng }},
+	{"geocode_confidence", "geocode_confidence", func(p *domain.Property) any { return &p.GeocodeConfidence }},
+	{"geocode_source", "COALESCE(geocode_source, '')", func(p *domain.Property) any { return &p.GeocodeSource }},
+	{"geocoded_at", "geocoded_at", func(p *domain.Property) any { return &p.GeocodedAt }},
+	{"main_image", "COALESCE(main_image, '')", func(p *domain.Property) any { return &p.MainImage }},
+	{"agent_id", "agent_id", func(p *domain.Property) any { return &p.AgentID }},
+	{"registry_finca", "COALESCE(registry_finca, '')", func(p *domain.Property) any { return &p.RegistryFinca }},
+	{"registry_folio", "COALESCE(registry_folio, '')", func(p *domain.Property) any { return &p.RegistryFolio }},
+	{"registry_libro", "COALESCE(registry_libro, '')", func(p *domain.Property) any { return &p.RegistryLibro }},
+	{"registry_iusi", "COALESCE(registry_iusi, '')", func(p *domain.Property) any { return &p.RegistryIUSI }},
+	{"status", "status", func(p *domain.Property) any { return &p.Status }},
+	{"closed_at", "closed_at", func(p *domain.Property) any { return &p.ClosedAt }},
+	{"created_at", "created_at", func(p *domain.Property) any { return &p.CreatedAt }},
+	{"updated_at", "updated_at", func(p *domain.Property) any { return &p.UpdatedAt }},
+}
+
+// propertyColumns es la lista SELECT completa
+var propertyColumns = selectList(propertyColumnList)
+
+// propertyFieldDependencies son columnas necesarias para campos calculados o siempre requeridos
+var propertyFieldDependencies = map[string][]string{
+	"":      {"id", "source_language"}, // Siempre se leen (localización)
+	"areas": {"price", "area_sqm"},
+}
+
+// sparseColumns retorna las columnas de los campos pedidos (vacío = todas)
+func sparseColumns(fields []string) []propertyColumn {
+	if len(fields) == 0 {
+		return propertyColumnList
+	}
+	wanted := map[string]bool{}
+	for _, f := range append(fields, propertyFieldDependencies[""]...) {
+		wanted[f] = true
+		for _, dep := range propertyFieldDependencies[f] {
+			wanted[dep] = true
+		}
+	}
+	columns := make([]propertyColumn, 0, len(wanted))
+	for _, c := range propertyColumnList {
+		if wanted[c.field] {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func selectList(columns []propertyColumn) string {
+	exprs := make([]string, len(columns))
+	for i, c := range columns {
+		exprs[i] = c.expr
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// scanProperty lee una fila con el orden de propertyColumns
+func scanProperty(row interface{ Scan(...any) error }, p *domain.Property) error {
+	return scanColumns(row, p, propertyColumnList)
+}
+
+func scanColumns(row interface{ Scan(...any) error }, p *domain.Property, columns []propertyColumn) error {
+	dest := make([]any, len(columns))
+	for i, c := range columns {
+		dest[i] = c.dest(p)
+	}
+	return row.Scan(dest...)
+}
+
+// propertySortColumns son las columnas por las que se puede ordenar el listado
+var propertySortColumns = map[string]string{
+	"price":      "price",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"area_sqm":   "COALESCE(area_sqm, 0)",
+	"bedrooms":   "COALESCE(bedrooms, 0)",
+	"bathrooms":  "COALESCE(bathrooms, 0)",
+	"title":      "lower(title)",
+}
+
+// orderBy arma el ORDER BY con el id como desempate estable (por defecto, más recientes primero)
+func orderBy(sorts []domain.PropertySort) string {
+	clauses := make([]string, 0, len(sorts)+1)
+	for _, s := range sorts {
+		column, ok := propertySortColumns[s.Field]
+		if !ok {
+			continue
+		}
+		if s.Desc {
+			column += " DESC"
+		}
+		clauses = append(clauses, column)
+	}
+	if len(clauses) == 0 {
+		clauses = append(clauses, "created_at DESC")
+	}
+	return strings.Join(append(clauses, "id DESC"), ", ")
+}
+
 func (r *propertyRepo) GetByID(ctx context.Context, id int64) (*domain.Property, error) {
 	// Query parametrizada: INMUNE a SQL Injection
-	query := `SELECT id, title, price, address, type, created_at FROM properties WHERE id = $1`
+	query := `SELECT ` + propertyColumns + ` FROM properties WHERE id = $1 AND ` + tenantFilter("organization_id", 2)
 
 	var p domain.Property
 	// Usamos QueryRowContext para respetar el timeout del contexto
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Title, &p.Price, &p.Address, &p.Type, &p.CreatedAt)
+	err := scanProperty(conn(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)), &p)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("property not found")
+			return nil, domain.ErrPropertyNotFound
 		}
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *propertyRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Property, error) {
-	query := `SELECT id, title, description, price, currency, address, city, type, 
-                     bedrooms, bathrooms, area_sqm, main_image, created_at, updated_at 
+// GetByIDForUpdate debe llamarse dentro de TxManager.WithinTx; fuera de ella el
+// bloqueo se libera al terminar la consulta
+func (r *propertyRepo) GetByIDForUpdate(ctx context.Context, id int64) (*domain.Property, error) {
+	query := `SELECT ` + propertyColumns + ` FROM properties
+              WHERE id = $1 AND ` + tenantFilter("organization_id", 2) + `
+              FOR UPDATE`
+
+	var p domain.Property
+	err := scanProperty(conn(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)), &p)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPropertyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *propertyRepo) GetByIDs(ctx context.Context, ids []int64) ([]domain.Property, error) {
+	query := `SELECT ` + propertyColumns + ` FROM properties WHERE id = ANY($1) AND ` + tenantFilter("organization_id", 2)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(ids), tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]domain.Property, 0, len(ids))
+	for rows.Next() {
+		var p domain.Property
+		if err := scanProperty(rows, &p); err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+	return properties, rows.Err()
+}
+
+func (r *propertyRepo) GetAll(ctx context.Context, filter domain.PropertyFilter, limit, offset int) ([]domain.Property, error) {
+	// Los filtros vacíos (cero) se ignoran en la consulta
+	columns := sparseColumns(filter.Fields)
+	query := `SELECT ` + selectList(columns) + ` 
               FROM properties 
-              ORDER BY created_at DESC 
-              LIMIT $1 OFFSET $2`
+              WHERE ($1 = '' OR type = $1)
+                AND ($2::float8 = 0 OR area_sqm >= $2)
+                AND ($3::float8 = 0 OR area_sqm <= $3)
+                AND ($4 = 0 OR department_id = $4)
+                AND ($5 = 0 OR municipality_id = $5)
+                AND ($6 = 0 OR zone_id = $6)
+                AND ($7 = ''
+                     OR zone_boundary_id IN (SELECT id FROM zone_boundaries WHERE lower(name) = lower($7))
+                     OR zone_id IN (SELECT id FROM geo_zones WHERE lower(name) = lower($7)))
+                AND (COALESCE(cardinality($10::text[]), 0) = 0 OR status = ANY($10))
+                AND ($11 = '' OR registry_finca = $11)
+                AND ($12 = '' OR registry_folio = $12)
+                AND ($13 = '' OR registry_libro = $13)
+                AND ($14 = '' OR registry_iusi = $14)
+                AND ` + tenantFilter("organization_id", 15) + `
+              ORDER BY ` + orderBy(filter.Sort) + `
+              LIMIT $8 OFFSET $9`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, filter.Type, filter.MinAreaSqM, filter.MaxAreaSqM,
+		filter.DepartmentID, filter.MunicipalityID, filter.ZoneID, filter.ZoneName, limit, offset,
+		pq.Array(filter.Statuses), filter.RegistryFinca, filter.RegistryFolio, filter.RegistryLibro, filter.RegistryIUSI,
+		tenantID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -49,10 +237,7 @@ func (r *propertyRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.
 	var properties []domain.Property
 	for rows.Next() {
 		var p domain.Property
-		err := rows.Scan(&p.ID, &p.Title, &p.Description, &p.Price, &p.Currency,
-			&p.Address, &p.City, &p.Type, &p.Bedrooms, &p.Bathrooms,
-			&p.AreaSqM, &p.MainImage, &p.CreatedAt, &p.UpdatedAt)
-		if err != nil {
+		if err := scanColumns(rows, &p, columns); err != nil {
 			return nil, err
 		}
 		properties = append(properties, p)
@@ -62,15 +247,431 @@ func (r *propertyRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.
 }
 
 func (r *propertyRepo) Create(ctx context.Context, property *domain.Property) error {
+	organizationID := ownerTenant(ctx)
+	if organizationID == "" {
+		return domain.ErrTenantRequired
+	}
 	query := `INSERT INTO properties 
               (title, description, price, currency, address, city, type, 
-               bedrooms, bathrooms, area_sqm, main_image) 
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
+               bedrooms, bathrooms, area_sqm, main_image, address_normalized, status,
+               department_id, municipality_id, zone_id, lat, lng, zone_boundary_id,
+               geocode_confidence, geocode_source, geocoded_at, source_language, amenities, agent_id,
+               registry_finca, registry_folio, registry_libro, registry_iusi, organization_id) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+                      NULLIF($17::float8, 0), NULLIF($18::float8, 0), $19, $20, NULLIF($21, ''), $22, $23, $24, $25,
+                      NULLIF($26, ''), NULLIF($27, ''), NULLIF($28, ''), NULLIF($29, ''), $30) 
               RETURNING id, created_at, updated_at`
 
-	return r.db.QueryRowContext(ctx, query,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		property.Title, property.Description, property.Price, property.Currency,
 		property.Address, property.City, property.Type, property.Bedrooms,
-		property.Bathrooms, property.AreaSqM, property.MainImage).
+		property.Bathrooms, property.AreaSqM, property.MainImage,
+		domain.NormalizeAddress(property.Address), property.Status,
+		property.DepartmentID, property.MunicipalityID, property.ZoneID,
+		property.Lat, property.Lng, property.ZoneBoundaryID,
+		property.GeocodeConfidence, property.GeocodeSource, property.GeocodedAt, property.SourceLanguage,
+		pq.Array(amenitiesOrEmpty(property.Amenities)), property.AgentID,
+		property.RegistryFinca, property.RegistryFolio, property.RegistryLibro, property.RegistryIUSI, organizationID).
 		Scan(&property.ID, &property.CreatedAt, &property.UpdatedAt)
+	return registryConflict(err)
+}
+
+func (r *propertyRepo) Update(ctx context.Context, property *domain.Property) error {
+	query := `UPDATE properties 
+              SET title = $1, description = $2, price = $3, currency = $4, address = $5, city = $6, type = $7,
+                  bedrooms = $8, bathrooms = $9, area_sqm = $10, main_image = $11, address_normalized = $12,
+                  department_id = $13, municipality_id = $14, zone_id = $15, updated_at = $16,
+                  amenities = $18, registry_finca = NULLIF($19, ''), registry_folio = NULLIF($20, ''),
+                  registry_libro = NULLIF($21, ''), registry_iusi = NULLIF($22, '')
+              WHERE id = $17 AND ` + tenantFilter("organization_id", 23) + `
+              RETURNING updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		property.Title, property.Description, property.Price, property.Currency,
+		property.Address, property.City, property.Type, property.Bedrooms,
+		property.Bathrooms, property.AreaSqM, property.MainImage,
+		domain.NormalizeAddress(property.Address),
+		property.DepartmentID, property.MunicipalityID, property.ZoneID, time.Now(), property.ID,
+		pq.Array(amenitiesOrEmpty(property.Amenities)),
+		property.RegistryFinca, property.RegistryFolio, property.RegistryLibro, property.RegistryIUSI, tenantID(ctx)).
+		Scan(&property.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPropertyNotFound
+	}
+	return registryConflict(err)
+}
+
+// ReplaceGeocode escribe sin condiciones coordenadas, geocodificación y polígono. Sin
+// coordenadas la propiedad vuelve a quedar pendiente para el barrido del geocodificador.
+func (r *propertyRepo) ReplaceGeocode(ctx context.Context, property *domain.Property) error {
+	query := `UPDATE properties
+              SET lat = NULLIF($1::float8, 0), lng = NULLIF($2::float8, 0), zone_boundary_id = $3,
+                  geocode_confidence = $4, geocode_source = NULLIF($5, ''), geocoded_at = $6,
+                  geocode_attempted_at = CASE WHEN NULLIF($1::float8, 0) IS NULL THEN NULL ELSE geocode_attempted_at END,
+                  updated_at = $7
+              WHERE id = $8 AND ` + tenantFilter("organization_id", 9)
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, property.Lat, property.Lng, property.ZoneBoundaryID,
+		property.GeocodeConfidence, property.GeocodeSource, property.GeocodedAt, time.Now(), property.ID, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrPropertyNotFound
+	}
+	return nil
+}
+
+// registryConflict traduce la violación de los índices únicos registrales
+func registryConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		switch pqErr.Constraint {
+		case "idx_properties_registry_active":
+			return &domain.RegistryConflictError{Field: "registry"}
+		case "idx_properties_iusi_active":
+			return &domain.RegistryConflictError{Field: "registry_iusi"}
+		}
+	}
+	return err
+}
+
+// FindRegistryConflict busca otra propiedad activa de la agencia con la misma finca/folio/libro o matrícula IUSI
+func (r *propertyRepo) FindRegistryConflict(ctx context.Context, property *domain.Property) (*domain.RegistryConflictError, error) {
+	query := `SELECT id, (registry_finca = $2 AND registry_folio = $3 AND registry_libro = $4)
+              FROM properties
+              WHERE id <> $1 AND status IN ('draft', 'published', 'reserved')
+                AND (($2 <> '' AND registry_finca = $2 AND registry_folio = $3 AND registry_libro = $4)
+                     OR ($5 <> '' AND registry_iusi = $5))
+                AND ` + tenantFilter("organization_id", 6) + `
+              ORDER BY id
+              LIMIT 1`
+
+	var id int64
+	var sameRegistry sql.NullBool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, property.ID, property.RegistryFinca, property.RegistryFolio,
+		property.RegistryLibro, property.RegistryIUSI, tenantID(ctx)).Scan(&id, &sameRegistry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sameRegistry.Bool {
+		return &domain.RegistryConflictError{Field: "registry", PropertyID: id}, nil
+	}
+	return &domain.RegistryConflictError{Field: "registry_iusi", PropertyID: id}, nil
+}
+
+// amenitiesOrEmpty evita escribir NULL en la columna NOT NULL
+func amenitiesOrEmpty(amenities []string) []string {
+	if amenities == nil {
+		return []string{}
+	}
+	return amenities
+}
+
+// UpdateStatus solo cambia el estado si sigue siendo from, para que dos transiciones
+// simultáneas no se apliquen ambas
+func (r *propertyRepo) UpdateStatus(ctx context.Context, id int64, from, to string, closedAt *time.Time) error {
+	query := `UPDATE properties SET status = $1, closed_at = $2, updated_at = $3
+              WHERE id = $4 AND status = $6 AND ` + tenantFilter("organization_id", 5)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, to, closedAt, time.Now(), id, tenantID(ctx), from)
+	if err != nil {
+		return registryConflict(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	var exists bool
+	err = conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM properties WHERE id = $1 AND `+tenantFilter("organization_id", 2)+`)`,
+		id, tenantID(ctx)).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPropertyNotFound
+	}
+	return domain.ErrPropertyStatusChanged
+}
+
+// ListWithCoordinates retorna propiedades con coordenadas por páginas de ID (para backfills)
+func (r *propertyRepo) ListWithCoordinates(ctx context.Context, afterID int64, limit int) ([]domain.Property, error) {
+	query := `SELECT ` + propertyColumns + `
+              FROM properties
+              WHERE id > $1 AND lat IS NOT NULL AND lng IS NOT NULL AND ` + tenantFilter("organization_id", 3) + `
+              ORDER BY id
+              LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, afterID, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]domain.Property, 0)
+	for rows.Next() {
+		var p domain.Property
+		if err := scanProperty(rows, &p); err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// ListAddresses retorna ID y dirección por páginas de ID
+func (r *propertyRepo) ListAddresses(ctx context.Context, afterID int64, limit int) ([]domain.Property, error) {
+	query := `SELECT id, address FROM properties
+              WHERE id > $1 AND ` + tenantFilter("organization_id", 3) + `
+              ORDER BY id
+              LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, afterID, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]domain.Property, 0)
+	for rows.Next() {
+		var p domain.Property
+		if err := rows.Scan(&p.ID, &p.Address); err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// UpdateAddressNormalized no modifica updated_at: es un dato derivado, no una edición
+func (r *propertyRepo) UpdateAddressNormalized(ctx context.Context, id int64, normalized string) (bool, error) {
+	query := `UPDATE properties SET address_normalized = $1
+              WHERE id = $2 AND address_normalized IS DISTINCT FROM $1 AND ` + tenantFilter("organization_id", 3)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, normalized, id, tenantID(ctx))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *propertyRepo) UpdateZone(ctx context.Context, id int64, zoneBoundaryID, zoneID *int64) error {
+	query := `UPDATE properties SET zone_boundary_id = $1, zone_id = $2, updated_at = $3
+              WHERE id = $4 AND ` + tenantFilter("organization_id", 5)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, zoneBoundaryID, zoneID, time.Now(), id, tenantID(ctx))
+	return err
+}
+
+// UpdateGeocode guarda las coordenadas geocodificadas. Un resultado automático no
+// sobrescribe coordenadas que se hayan asignado mientras tanto (ej. manualmente).
+func (r *propertyRepo) UpdateGeocode(ctx context.Context, property *domain.Property) error {
+	query := `UPDATE properties
+              SET lat = $1, lng = $2, geocode_confidence = $3, geocode_source = $4, geocoded_at = $5,
+                  zone_boundary_id = $6, zone_id = $7, updated_at = $8
+              WHERE id = $9 AND ($4 = 'manual' OR lat IS NULL) AND ` + tenantFilter("organization_id", 10)
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, property.Lat, property.Lng, property.GeocodeConfidence,
+		property.GeocodeSource, property.GeocodedAt, property.ZoneBoundaryID, property.ZoneID,
+		time.Now(), property.ID, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrCoordinatesAlreadySet
+	}
+	return nil
+}
+
+func (r *propertyRepo) ClaimPendingGeocodes(ctx context.Context, now, retryBefore time.Time, limit int) ([]int64, error) {
+	query := `UPDATE properties SET geocode_attempted_at = $1
+              WHERE id IN (
+                  SELECT id FROM properties
+                  WHERE lat IS NULL AND address <> ''
+                    AND (geocode_attempted_at IS NULL OR geocode_attempted_at < $2)
+                    AND ` + tenantFilter("organization_id", 4) + `
+                  ORDER BY geocode_attempted_at NULLS FIRST, id
+                  LIMIT $3
+                  FOR UPDATE SKIP LOCKED
+              )
+              RETURNING id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, retryBefore, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// duplicateRadiusDeg es el radio (en grados, ~300 m) para buscar coordenadas cercanas
+const duplicateRadiusDeg = 0.003
+
+// FindSimilar ordena por fuerza de la coincidencia (dirección, distancia, precio) para que
+// la banda de precio, que abarca muchas propiedades, no desplace del límite a las cercanas
+func (r *propertyRepo) FindSimilar(ctx context.Context, property *domain.Property, limit int) ([]domain.Property, error) {
+	query := `SELECT id, title, price, COALESCE(currency, ''), address, COALESCE(city, ''), type,
+                     COALESCE(lat, 0), COALESCE(lng, 0), created_at
+              FROM properties
+              WHERE type = $1 AND (
+                    address_normalized = $2
+                 OR ($3::float8 <> 0 AND lat BETWEEN $3 - $6 AND $3 + $6 AND lng BETWEEN $4 - $6 AND $4 + $6)
+                 OR (currency = $5 AND price BETWEEN $7 * 0.9 AND $7 * 1.1)
+              ) AND ` + tenantFilter("organization_id", 9) + `
+              ORDER BY (address_normalized = $2) DESC NULLS LAST,
+                       CASE WHEN $3::float8 <> 0 AND lat BETWEEN $3 - $6 AND $3 + $6 AND lng BETWEEN $4 - $6 AND $4 + $6
+                            THEN (lat - $3) ^ 2 + (lng - $4) ^ 2
+                       END NULLS LAST,
+                       CASE WHEN currency = $5 THEN ABS(price - $7) END NULLS LAST,
+                       created_at DESC
+              LIMIT $8`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, property.Type, domain.NormalizeAddress(property.Address),
+		property.Lat, property.Lng, property.Currency, duplicateRadiusDeg, property.Price, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]domain.Property, 0)
+	for rows.Next() {
+		var p domain.Property
+		if err := rows.Scan(&p.ID, &p.Title, &p.Price, &p.Currency, &p.Address, &p.City, &p.Type,
+			&p.Lat, &p.Lng, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// duplicateCandidateJoin arma un bloque de candidatos: un auto-join por una clave de
+// agrupación con índice, acotado por tipo, agencia y el cursor ($2, $3)
+func duplicateCandidateJoin(on string) string {
+	return `SELECT a.id AS first_id, b.id AS second_id
+            FROM properties a
+            JOIN properties b ON ` + on + `
+             AND a.id < b.id AND a.type = b.type AND a.organization_id = b.organization_id
+            WHERE (a.id, b.id) > ($2, $3) AND ` + tenantFilter("a.organization_id", 5)
+}
+
+func (r *propertyRepo) FindDuplicatePairs(ctx context.Context, afterFirst, afterSecond int64, limit int) ([]domain.DuplicatePair, error) {
+	// Unión de bloques (dirección, celda de coordenadas, título) en lugar de comparar
+	// todos los pares; el puntaje final se calcula en el servicio recorriendo las páginas
+	query := `WITH candidates AS (
+                  ` + duplicateCandidateJoin(`b.address_normalized = a.address_normalized`) + `
+                  UNION
+                  ` + duplicateCandidateJoin(`b.lat BETWEEN a.lat - $1 AND a.lat + $1 AND b.lng BETWEEN a.lng - $1 AND a.lng + $1`) + `
+                  UNION
+                  ` + duplicateCandidateJoin(`lower(b.title) = lower(a.title) AND b.currency = a.currency
+             AND ABS(a.price - b.price) <= 0.1 * GREATEST(a.price, b.price)`) + `
+              )
+              SELECT a.id, a.title, a.price, COALESCE(a.currency, ''), a.address, COALESCE(a.city, ''), a.type,
+                     COALESCE(a.lat, 0), COALESCE(a.lng, 0), a.created_at,
+                     b.id, b.title, b.price, COALESCE(b.currency, ''), b.address, COALESCE(b.city, ''), b.type,
+                     COALESCE(b.lat, 0), COALESCE(b.lng, 0), b.created_at
+              FROM candidates c
+              JOIN properties a ON a.id = c.first_id
+              JOIN properties b ON b.id = c.second_id
+              ORDER BY c.first_id, c.second_id
+              LIMIT $4`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, duplicateRadiusDeg, afterFirst, afterSecond, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := make([]domain.DuplicatePair, 0)
+	for rows.Next() {
+		var pair domain.DuplicatePair
+		a, b := &pair.First, &pair.Second
+		if err := rows.Scan(&a.ID, &a.Title, &a.Price, &a.Currency, &a.Address, &a.City, &a.Type,
+			&a.Lat, &a.Lng, &a.CreatedAt,
+			&b.ID, &b.Title, &b.Price, &b.Currency, &b.Address, &b.City, &b.Type,
+			&b.Lat, &b.Lng, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+func (r *propertyRepo) FindComparables(ctx context.Context, criteria domain.ComparableCriteria, limit int) ([]domain.Property, error) {
+	// Con coordenadas se filtra por caja envolvente del radio y se ordena por distancia;
+	// sin ellas, por ciudad. Borradores y retiradas no son precios de mercado.
+	query := `SELECT id, title, price, COALESCE(currency, 'USD'), COALESCE(city, ''), type,
+                     COALESCE(bedrooms, 0), area_sqm, COALESCE(lat, 0), COALESCE(lng, 0), created_at
+              FROM properties
+              WHERE type = $1
+                AND status IN ('published', 'reserved', 'sold', 'rented')
+                AND price > 0 AND area_sqm > 0
+                AND area_sqm BETWEEN $2 * 0.6 AND $2 * 1.4
+                AND ($3 = 0 OR bedrooms BETWEEN $3 - 1 AND $3 + 1)
+                AND (
+                     ($4::float8 <> 0 AND lat BETWEEN $4 - $6 AND $4 + $6 AND lng BETWEEN $5 - $6 AND $5 + $6)
+                  OR ($4::float8 = 0 AND lower(city) = lower($7))
+                )
+                AND ` + tenantFilter("organization_id", 9) + `
+              ORDER BY CASE WHEN $4::float8 <> 0 THEN (lat - $4) ^ 2 + (lng - $5) ^ 2 END,
+                       created_at DESC
+              LIMIT $8`
+
+	// 1 grado de latitud ≈ 111 km
+	radiusDeg := criteria.RadiusKm / 111.0
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, criteria.Type, criteria.AreaSqM, criteria.Bedrooms,
+		criteria.Lat, criteria.Lng, radiusDeg, criteria.City, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]domain.Property, 0)
+	for rows.Next() {
+		var p domain.Property
+		if err := rows.Scan(&p.ID, &p.Title, &p.Price, &p.Currency, &p.City, &p.Type,
+			&p.Bedrooms, &p.AreaSqM, &p.Lat, &p.Lng, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return properties, nil
 }
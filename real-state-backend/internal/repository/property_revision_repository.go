@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type propertyRevisionRepo struct {
+	db *sql.DB
+}
+
+// NewPropertyRevisionRepository crea una instancia del repositorio del historial de propiedades.
+func NewPropertyRevisionRepository(db *sql.DB) ports.PropertyRevisionRepository {
+	return &propertyRevisionRepo{db: db}
+}
+
+// Create asigna el siguiente número de revisión de la propiedad. Bloquea la fila de
+// la propiedad para que dos cambios simultáneos no obtengan el mismo número.
+func (r *propertyRevisionRepo) Create(ctx context.Context, rev *domain.PropertyRevision) error {
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM properties WHERE id = $1 FOR UPDATE`, rev.PropertyID).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPropertyNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO property_revisions
+              (property_id, revision, change_type, changed_by, reverted_from, snapshot)
+              SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+              FROM property_revisions WHERE property_id = $1
+              RETURNING id, revision, created_at`
+	err = tx.QueryRowContext(ctx, query, rev.PropertyID, rev.ChangeType, rev.ChangedBy, rev.RevertedFrom, snapshot).
+		Scan(&rev.ID, &rev.Revision, &rev.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// List retorna el historial sin las copias, de la más reciente a la más antigua
+func (r *propertyRevisionRepo) List(ctx context.Context, propertyID int64) ([]domain.PropertyRevision, error) {
+	query := `SELECT id, property_id, revision, change_type, changed_by, reverted_from, created_at
+              FROM property_revisions
+              WHERE property_id = $1 AND ` + inTenant("property_id", 2) + `
+              ORDER BY revision DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, propertyID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]domain.PropertyRevision, 0)
+	for rows.Next() {
+		var rev domain.PropertyRevision
+		if err := rows.Scan(&rev.ID, &rev.PropertyID, &rev.Revision, &rev.ChangeType,
+			&rev.ChangedBy, &rev.RevertedFrom, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Get retorna una revisión con su copia completa (revision 0 = la más reciente)
+func (r *propertyRevisionRepo) Get(ctx context.Context, propertyID int64, revision int) (*domain.PropertyRevision, error) {
+	query := `SELECT id, property_id, revision, change_type, changed_by, reverted_from, snapshot, created_at
+              FROM property_revisions
+              WHERE property_id = $1 AND ($2 = 0 OR revision = $2) AND ` + inTenant("property_id", 3) + `
+              ORDER BY revision DESC
+              LIMIT 1`
+
+	var rev domain.PropertyRevision
+	var snapshot []byte
+	err := r.db.QueryRowContext(ctx, query, propertyID, revision, tenantID(ctx)).Scan(&rev.ID, &rev.PropertyID, &rev.Revision,
+		&rev.ChangeType, &rev.ChangedBy, &rev.RevertedFrom, &snapshot, &rev.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	rev.Snapshot = &domain.Property{}
+	if err := json.Unmarshal(snapshot, rev.Snapshot); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type propertyTranslationRepo struct {
+	db *sql.DB
+}
+
+// NewPropertyTranslationRepository crea una instancia del repositorio de traducciones.
+func NewPropertyTranslationRepository(db *sql.DB) ports.PropertyTranslationRepository {
+	return &propertyTranslationRepo{db: db}
+}
+
+func (r *propertyTranslationRepo) Upsert(ctx context.Context, t *domain.PropertyTranslation) error {
+	query := `INSERT INTO property_translations (property_id, language, title, description)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (property_id, language)
+              DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, updated_at = CURRENT_TIMESTAMP
+              RETURNING created_at, updated_at`
+
+	return r.db.QueryRowContext(ctx, query, t.PropertyID, t.Language, t.Title, t.Description).
+		Scan(&t.CreatedAt, &t.UpdatedAt)
+}
+
+// List retorna las traducciones de las propiedades indicadas, opcionalmente filtradas por idioma
+func (r *propertyTranslationRepo) List(ctx context.Context, propertyIDs []int64, languages []string) ([]domain.PropertyTranslation, error) {
+	query := `SELECT property_id, language, title, description, created_at, updated_at
+              FROM property_translations
+              WHERE property_id = ANY($1)
+                AND (cardinality($2::text[]) = 0 OR language = ANY($2))
+                AND ` + inTenant("property_id", 3) + `
+              ORDER BY property_id, language`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(propertyIDs), pq.Array(languages), tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]domain.PropertyTranslation, 0)
+	for rows.Next() {
+		var t domain.PropertyTranslation
+		if err := rows.Scan(&t.PropertyID, &t.Language, &t.Title, &t.Description, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *propertyTranslationRepo) Delete(ctx context.Context, propertyID int64, language string) error {
+	query := `DELETE FROM property_translations WHERE property_id = $1 AND language = $2 AND ` + inTenant("property_id", 3)
+	res, err := r.db.ExecContext(ctx, query, propertyID, language, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrTranslationNotFound
+	}
+	return nil
+}
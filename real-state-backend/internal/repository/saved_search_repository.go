@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type savedSearchRepo struct {
+	db *sql.DB
+}
+
+// NewSavedSearchRepository crea una instancia del repositorio de búsquedas guardadas.
+func NewSavedSearchRepository(db *sql.DB) ports.SavedSearchRepository {
+	return &savedSearchRepo{db: db}
+}
+
+const savedSearchColumns = `id, user_id, organization_id, name, criteria, created_at, updated_at`
+
+func scanSavedSearch(row interface{ Scan(...any) error }, s *domain.SavedSearch) error {
+	var criteria []byte
+	if err := row.Scan(&s.ID, &s.UserID, &s.OrganizationID, &s.Name, &criteria, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return err
+	}
+	return json.Unmarshal(criteria, &s.Criteria)
+}
+
+// Create registra la búsqueda en la agencia del contexto
+func (r *savedSearchRepo) Create(ctx context.Context, search *domain.SavedSearch) error {
+	organizationID := ownerTenant(ctx)
+	if organizationID == "" {
+		return domain.ErrTenantRequired
+	}
+	criteria, err := json.Marshal(search.Criteria)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO saved_searches (user_id, organization_id, name, criteria, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $5)
+              RETURNING id`
+	search.OrganizationID = organizationID
+	return conn(ctx, r.db).QueryRowContext(ctx, query, search.UserID, organizationID, search.Name, criteria, search.CreatedAt).
+		Scan(&search.ID)
+}
+
+func (r *savedSearchRepo) ListByUser(ctx context.Context, userID string) ([]domain.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches
+              WHERE user_id = $1 AND ` + tenantFilter("organization_id", 2) + `
+              ORDER BY id`
+	return r.list(ctx, query, userID, tenantID(ctx))
+}
+
+func (r *savedSearchRepo) Delete(ctx context.Context, userID string, id int64) error {
+	query := `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2 AND ` + tenantFilter("organization_id", 3)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, userID, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// ListCandidates retorna las búsquedas de la agencia sin tipo o del tipo de la propiedad;
+// el resto de criterios los evalúa domain.SavedSearchCriteria.Matches
+func (r *savedSearchRepo) ListCandidates(ctx context.Context, organizationID, propertyType string) ([]domain.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches
+              WHERE organization_id::text = $1 AND (criteria->>'type' IS NULL OR criteria->>'type' = $2)
+              ORDER BY id`
+	return r.list(ctx, query, organizationID, propertyType)
+}
+
+func (r *savedSearchRepo) list(ctx context.Context, query string, args ...any) ([]domain.SavedSearch, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := make([]domain.SavedSearch, 0)
+	for rows.Next() {
+		var s domain.SavedSearch
+		if err := scanSavedSearch(rows, &s); err != nil {
+			return nil, err
+		}
+		searches = append(searches, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
@@ -27,7 +27,7 @@ func (r *SecurityConfigRepository) GetString(ctx context.Context, key string) (s
 func (r *SecurityConfigRepository) GetInt(ctx context.Context, key string) (int, error) {
 	query := `SELECT value FROM security_config WHERE key = $1`
 	var value string
-	err := r.db.QueryRowContext(ctx, query, key).Scan(&value)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, key).Scan(&value)
 	if err != nil {
 		return 0, err
 	}
@@ -49,9 +49,9 @@ func (r *SecurityConfigRepository) GetDuration(ctx context.Context, key string)
 	return time.Duration(minutes) * time.Minute, nil
 }
 
-// UpdateConfig actualiza una configuración
+// UpdateConfig actualiza una configuración; se une a la transacción del contexto si la hay
 func (r *SecurityConfigRepository) UpdateConfig(ctx context.Context, key string, value string) error {
 	query := `UPDATE security_config SET value = $1, updated_at = $2 WHERE key = $3`
-	_, err := r.db.ExecContext(ctx, query, value, time.Now(), key)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, value, time.Now(), key)
 	return err
 }
@@ -32,20 +32,24 @@ func (r *SessionRepository) Create(ctx context.Context, session *domain.UserSess
 	locationJSON, _ := json.Marshal(session.LocationData)
 	deviceJSON, _ := json.Marshal(session.DeviceMetadata)
 
-	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.TokenJTI, session.RefreshTokenHash,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, session.ID, session.UserID, session.TokenJTI, session.RefreshTokenHash,
 		session.DeviceID, locationJSON, session.UserAgent, deviceJSON, session.CreatedAt, session.ExpiresAt, session.Revoked)
 	return err
 }
 
 func (r *SessionRepository) GetByTokenJTI(ctx context.Context, jti string) (*domain.UserSession, error) {
 	query := `
-		SELECT id, user_id, token_jti, refresh_token_hash, device_id, location_data, user_agent, device_metadata, created_at, expires_at, revoked
-		FROM user_sessions WHERE token_jti = $1 AND revoked = FALSE`
+		SELECT s.id, s.user_id, u.organization_id, o.active, s.token_jti, s.refresh_token_hash, s.device_id, s.location_data,
+		       s.user_agent, s.device_metadata, s.created_at, s.expires_at, s.revoked
+		FROM user_sessions s
+		JOIN users u ON u.id = s.user_id
+		JOIN organizations o ON o.id = u.organization_id
+		WHERE s.token_jti = $1 AND s.revoked = FALSE`
 
 	session := &domain.UserSession{}
 	var locationJSON, deviceJSON []byte
-	err := r.db.QueryRowContext(ctx, query, jti).Scan(
-		&session.ID, &session.UserID, &session.TokenJTI, &session.RefreshTokenHash,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, jti).Scan(
+		&session.ID, &session.UserID, &session.OrganizationID, &session.OrganizationActive, &session.TokenJTI, &session.RefreshTokenHash,
 		&session.DeviceID, &locationJSON, &session.UserAgent, &deviceJSON,
 		&session.CreatedAt, &session.ExpiresAt, &session.Revoked,
 	)
@@ -60,18 +64,18 @@ func (r *SessionRepository) GetByTokenJTI(ctx context.Context, jti string) (*dom
 
 func (r *SessionRepository) RevokeByUserID(ctx context.Context, userID string) error {
 	query := `UPDATE user_sessions SET revoked = TRUE WHERE user_id = $1`
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
 	return err
 }
 
 func (r *SessionRepository) RevokeByJTI(ctx context.Context, jti string) error {
 	query := `UPDATE user_sessions SET revoked = TRUE WHERE token_jti = $1`
-	_, err := r.db.ExecContext(ctx, query, jti)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, jti)
 	return err
 }
 
 func (r *SessionRepository) UpdateRefreshToken(ctx context.Context, jti string, newHash string, newExpiry time.Time) error {
 	query := `UPDATE user_sessions SET refresh_token_hash = $1, expires_at = $2 WHERE token_jti = $3`
-	_, err := r.db.ExecContext(ctx, query, newHash, newExpiry, jti)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, newHash, newExpiry, jti)
 	return err
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type shareLinkRepo struct {
+	db *sql.DB
+}
+
+// NewShareLinkRepository crea una instancia del repositorio de enlaces públicos.
+func NewShareLinkRepository(db *sql.DB) ports.ShareLinkRepository {
+	return &shareLinkRepo{db: db}
+}
+
+const shareLinkColumns = `id, property_id, created_by, expires_at, revoked_at, open_count, last_opened_at, created_at`
+
+func scanShareLink(row interface{ Scan(...any) error }, l *domain.ShareLink) error {
+	return row.Scan(&l.ID, &l.PropertyID, &l.CreatedBy, &l.ExpiresAt, &l.RevokedAt,
+		&l.OpenCount, &l.LastOpenedAt, &l.CreatedAt)
+}
+
+func (r *shareLinkRepo) Create(ctx context.Context, link *domain.ShareLink) error {
+	query := `INSERT INTO property_share_links (property_id, created_by, expires_at)
+              VALUES ($1, $2, $3)
+              RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, link.PropertyID, link.CreatedBy, link.ExpiresAt).
+		Scan(&link.ID, &link.CreatedAt)
+}
+
+func (r *shareLinkRepo) Get(ctx context.Context, id int64) (*domain.ShareLink, error) {
+	query := `SELECT ` + shareLinkColumns + ` FROM property_share_links WHERE id = $1 AND ` + inTenant("property_id", 2)
+
+	var l domain.ShareLink
+	if err := scanShareLink(r.db.QueryRowContext(ctx, query, id, tenantID(ctx)), &l); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *shareLinkRepo) List(ctx context.Context, propertyID int64) ([]domain.ShareLink, error) {
+	query := `SELECT ` + shareLinkColumns + `
+              FROM property_share_links
+              WHERE property_id = $1 AND ` + inTenant("property_id", 2) + `
+              ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, propertyID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]domain.ShareLink, 0)
+	for rows.Next() {
+		var l domain.ShareLink
+		if err := scanShareLink(rows, &l); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *shareLinkRepo) Revoke(ctx context.Context, propertyID, id int64) error {
+	query := `UPDATE property_share_links SET revoked_at = $1
+              WHERE id = $2 AND property_id = $3 AND revoked_at IS NULL AND ` + inTenant("property_id", 4)
+	res, err := r.db.ExecContext(ctx, query, time.Now(), id, propertyID, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrShareLinkNotFound
+	}
+	return nil
+}
+
+func (r *shareLinkRepo) RecordOpen(ctx context.Context, id int64) error {
+	now := time.Now()
+	query := `UPDATE property_share_links
+              SET open_count = open_count + 1, last_opened_at = $1
+              WHERE id = $2 AND revoked_at IS NULL AND expires_at > $1`
+	res, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrShareLinkInvalid
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// Modos de cifrado de la conexión SMTP
+const (
+	SMTPTLSStartTLS = "starttls" // STARTTLS si el servidor lo ofrece (por defecto)
+	SMTPTLSImplicit = "tls"      // TLS desde la conexión (puerto 465)
+	SMTPTLSNone     = "none"     // Sin cifrado (servidores de prueba locales)
+)
+
+type smtpEmailSender struct {
+	addr     string
+	host     string
+	from     *mail.Address
+	auth     smtp.Auth
+	tlsMode  string
+	timeout  time.Duration
+	hostname string // Dominio de los Message-ID
+}
+
+// NewSMTPEmailSender crea un emisor para addr (host:puerto). Sin username no se
+// autentica, lo que permite probar contra un servidor local que acepta todo.
+func NewSMTPEmailSender(addr, username, password, from, tlsMode string, timeout time.Duration) (ports.EmailSender, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: invalid address %q: %w", addr, err)
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: invalid from address %q: %w", from, err)
+	}
+	switch tlsMode {
+	case "":
+		tlsMode = SMTPTLSStartTLS
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+	default:
+		return nil, fmt.Errorf("smtp: invalid tls mode %q", tlsMode)
+	}
+	s := &smtpEmailSender{addr: addr, host: host, from: sender, tlsMode: tlsMode, timeout: timeout}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	if _, domainPart, ok := strings.Cut(sender.Address, "@"); ok {
+		s.hostname = domainPart
+	}
+	return s, nil
+}
+
+func (s *smtpEmailSender) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid recipient %q: %w", msg.To, err)
+	}
+	body, err := s.build(to, msg)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	if s.tlsMode == SMTPTLSImplicit {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.host})
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if s.tlsMode == SMTPTLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+				return err
+			}
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// build arma un mensaje multipart/alternative con las versiones de texto y HTML
+func (s *smtpEmailSender) build(to *mail.Address, msg *domain.EmailMessage) ([]byte, error) {
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	messageID, err := s.messageID()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	// El asunto se codifica (RFC 2047): acentos y saltos de línea no alteran los encabezados
+	for _, h := range [][2]string{
+		{"From", s.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("UTF-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/alternative; boundary="` + mw.Boundary() + `"`},
+	} {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+func (s *smtpEmailSender) messageID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "<" + hex.EncodeToString(b) + "@" + s.hostname + ">", nil
+}
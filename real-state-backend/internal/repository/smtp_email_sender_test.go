@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+)
+
+func newTestSMTPSender(t *testing.T, addr string) *smtpEmailSender {
+	t.Helper()
+	sender, err := NewSMTPEmailSender(addr, "", "", "Real State <no-reply@realstate.test>", SMTPTLSNone, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewSMTPEmailSender: %v", err)
+	}
+	return sender.(*smtpEmailSender)
+}
+
+// readParts lee las partes multipart/alternative (el lector decodifica quoted-printable)
+func readParts(t *testing.T, msg *mail.Message) map[string]string {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		parts[part.Header.Get("Content-Type")] = string(body)
+	}
+}
+
+func TestSMTPBuildMessage(t *testing.T) {
+	s := newTestSMTPSender(t, "localhost:25")
+	msg := &domain.EmailMessage{
+		To:      "Ana Pérez <ana@example.com>",
+		Subject: "Nueva consulta: Casa en Antigua, zona 10 — ¿disponible?",
+		Text:    "Hola Ana,\nLínea con acentos: áéíóú ñ " + strings.Repeat("x", 100),
+		HTML:    "<p>Hola <strong>Ana</strong></p>",
+	}
+	to, _ := mail.ParseAddress(msg.To)
+	raw, err := s.build(to, msg)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	header, _, _ := bytes.Cut(raw, []byte("\r\n\r\n"))
+	for _, b := range header {
+		if b > 127 {
+			t.Fatalf("headers contain non-ASCII bytes:\n%s", header)
+		}
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if from, err := parsed.Header.AddressList("From"); err != nil || from[0].Address != "no-reply@realstate.test" {
+		t.Errorf("from = %v (%v)", from, err)
+	}
+	if rcpt, err := parsed.Header.AddressList("To"); err != nil || rcpt[0].Address != "ana@example.com" || rcpt[0].Name != "Ana Pérez" {
+		t.Errorf("to = %v (%v)", rcpt, err)
+	}
+	if id := parsed.Header.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@realstate.test>") {
+		t.Errorf("message id = %q", id)
+	}
+	if _, err := parsed.Header.Date(); err != nil {
+		t.Errorf("date: %v", err)
+	}
+	if parsed.Header.Get("MIME-Version") != "1.0" {
+		t.Errorf("mime version = %q", parsed.Header.Get("MIME-Version"))
+	}
+
+	// quoted-printable normaliza los saltos de línea a CRLF
+	parts := readParts(t, parsed)
+	if parts["text/plain; charset=UTF-8"] != strings.ReplaceAll(msg.Text, "\n", "\r\n") {
+		t.Errorf("text part = %q", parts["text/plain; charset=UTF-8"])
+	}
+	if parts["text/html; charset=UTF-8"] != msg.HTML {
+		t.Errorf("html part = %q", parts["text/html; charset=UTF-8"])
+	}
+}
+
+func TestSMTPBuildSubjectCannotInjectHeaders(t *testing.T) {
+	s := newTestSMTPSender(t, "localhost:25")
+	to, _ := mail.ParseAddress("ana@example.com")
+	raw, err := s.build(to, &domain.EmailMessage{To: to.Address, Subject: "Hola\r\nBcc: otro@example.com", Text: "x"})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if parsed.Header.Get("Bcc") != "" {
+		t.Errorf("subject injected a Bcc header: %q", parsed.Header.Get("Bcc"))
+	}
+	if parts := readParts(t, parsed); len(parts) != 1 || parts["text/plain; charset=UTF-8"] != "x" {
+		t.Errorf("parts = %v, want only the text part", parts)
+	}
+}
+
+// smtpSession es lo que recibió el servidor de prueba en una conexión
+type smtpSession struct {
+	from, rcpt string
+	data       []byte
+}
+
+// startSMTPServer atiende una conexión SMTP en loopback. Con reject responde 451 al DATA.
+func startSMTPServer(t *testing.T, reject bool) (string, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var session smtpSession
+		tp.PrintfLine("220 localhost test server")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case cmd == "EHLO" || cmd == "HELO":
+				tp.PrintfLine("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				session.rcpt = strings.Trim(line[len("RCPT TO:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case cmd == "DATA" && reject:
+				tp.PrintfLine("451 try again later")
+			case cmd == "DATA":
+				tp.PrintfLine("354 end with .")
+				if session.data, err = tp.ReadDotBytes(); err != nil {
+					return
+				}
+				tp.PrintfLine("250 queued")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				sessions <- session
+				return
+			default:
+				tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), sessions
+}
+
+func TestSMTPSendLoopback(t *testing.T) {
+	addr, sessions := startSMTPServer(t, false)
+	s := newTestSMTPSender(t, addr)
+	msg := &domain.EmailMessage{To: "ana@example.com", Subject: "Correo de prueba", Text: "Hola ana,", HTML: "<p>Hola ana,</p>"}
+	if err := s.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var session smtpSession
+	select {
+	case session = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not receive the message")
+	}
+	if session.from != "no-reply@realstate.test" || session.rcpt != "ana@example.com" {
+		t.Errorf("envelope = %q -> %q", session.from, session.rcpt)
+	}
+	parsed, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(session.data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if parsed.Header.Get("Subject") != "Correo de prueba" {
+		t.Errorf("subject = %q", parsed.Header.Get("Subject"))
+	}
+	if parts := readParts(t, parsed); parts["text/plain; charset=UTF-8"] != msg.Text || parts["text/html; charset=UTF-8"] != msg.HTML {
+		t.Errorf("parts = %v", parts)
+	}
+}
+
+func TestSMTPSendRejected(t *testing.T) {
+	addr, _ := startSMTPServer(t, true)
+	s := newTestSMTPSender(t, addr)
+	err := s.Send(context.Background(), &domain.EmailMessage{To: "ana@example.com", Subject: "x", Text: "x"})
+	var protoErr *textproto.Error
+	if err == nil || !errors.As(err, &protoErr) || protoErr.Code != 451 {
+		t.Errorf("error = %v, want a 451 reply", err)
+	}
+}
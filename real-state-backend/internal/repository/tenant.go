@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// tenantID retorna la organización del contexto: la de la petición autenticada o
+// domain.PlatformScope en los workers y las rutas públicas. Vacío no coincide con nada.
+func tenantID(ctx context.Context) string {
+	id, _ := ctx.Value("organization_id").(string)
+	return id
+}
+
+// ownerTenant retorna la organización a registrar en una escritura; vacío con
+// alcance de plataforma o sin organización
+func ownerTenant(ctx context.Context) string {
+	if id := tenantID(ctx); id != domain.PlatformScope {
+		return id
+	}
+	return ""
+}
+
+// tenantFilter acota una columna organization_id al parámetro $n. Solo el alcance de
+// plataforma abarca todas las agencias; un parámetro vacío no retorna filas.
+func tenantFilter(column string, n int) string {
+	return fmt.Sprintf("($%d = '%s' OR %s::text = $%d)", n, domain.PlatformScope, column, n)
+}
+
+// inTenant acota una tabla hija a las propiedades de la organización del parámetro $n.
+// Las inserciones en tablas hijas dependen de que el servicio haya leído antes la
+// propiedad con GetByID, que ya está acotado.
+func inTenant(propertyColumn string, n int) string {
+	return fmt.Sprintf("($%d = '%s' OR EXISTS (SELECT 1 FROM properties tp WHERE tp.id = %s AND tp.organization_id::text = $%d))",
+		n, domain.PlatformScope, propertyColumn, n)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"real-state-backend/internal/core/ports"
+)
+
+type txKey struct{}
+
+// dbtx son las operaciones comunes a *sql.DB y *sql.Tx
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn retorna la transacción abierta por TxManager.WithinTx o, fuera de ella, la BD
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// scopedTx es una transacción propia del repositorio o la de WithinTx; en el
+// segundo caso Commit y Rollback quedan a cargo de WithinTx
+type scopedTx struct {
+	*sql.Tx
+	owned bool
+}
+
+// beginTx inicia una transacción o se une a la del contexto
+func beginTx(ctx context.Context, db *sql.DB) (*scopedTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &scopedTx{Tx: tx}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx, owned: true}, nil
+}
+
+func (t *scopedTx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *scopedTx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+type txManager struct {
+	db *sql.DB
+}
+
+// NewTxManager crea el administrador de transacciones compartidas entre repositorios.
+func NewTxManager(db *sql.DB) ports.TxManager {
+	return &txManager{db: db}
+}
+
+// WithinTx ejecuta fn en una transacción; una llamada anidada reutiliza la existente
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
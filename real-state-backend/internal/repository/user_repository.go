@@ -19,26 +19,27 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, username, email, password_hash, mfa_secret, failed_attempts, locked_until, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		INSERT INTO users (id, username, email, password_hash, mfa_secret, failed_attempts, locked_until, created_at, updated_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	user.ID = uuid.New().String()
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.MFASecret, user.FailedAttempts, user.LockedUntil, user.CreatedAt, user.UpdatedAt)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.MFASecret, user.FailedAttempts, user.LockedUntil, user.CreatedAt, user.UpdatedAt, user.OrganizationID)
 	return err
 }
 
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, mfa_secret, failed_attempts, locked_until, created_at, updated_at
-		FROM users WHERE username = $1`
+		SELECT u.id, u.username, u.email, u.organization_id, o.active, u.password_hash, u.mfa_secret, u.failed_attempts, u.locked_until, u.created_at, u.updated_at
+		FROM users u JOIN organizations o ON o.id = u.organization_id
+		WHERE u.username = $1`
 
 	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.MFASecret,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, username).Scan(
+		&user.ID, &user.Username, &user.Email, &user.OrganizationID, &user.OrganizationActive, &user.PasswordHash, &user.MFASecret,
 		&user.FailedAttempts, &user.LockedUntil, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
@@ -49,12 +50,31 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, mfa_secret, failed_attempts, locked_until, created_at, updated_at
-		FROM users WHERE id = $1`
+		SELECT u.id, u.username, u.email, u.organization_id, o.active, u.password_hash, u.mfa_secret, u.failed_attempts, u.locked_until, u.created_at, u.updated_at
+		FROM users u JOIN organizations o ON o.id = u.organization_id
+		WHERE u.id = $1`
 
 	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.MFASecret,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&user.ID, &user.Username, &user.Email, &user.OrganizationID, &user.OrganizationActive, &user.PasswordHash, &user.MFASecret,
+		&user.FailedAttempts, &user.LockedUntil, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetByEmail busca el usuario por su correo registrado (recuperación de contraseña)
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	query := `
+		SELECT u.id, u.username, u.email, u.organization_id, o.active, u.password_hash, u.mfa_secret, u.failed_attempts, u.locked_until, u.created_at, u.updated_at
+		FROM users u JOIN organizations o ON o.id = u.organization_id
+		WHERE u.email = $1`
+
+	user := &domain.User{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email).Scan(
+		&user.ID, &user.Username, &user.Email, &user.OrganizationID, &user.OrganizationActive, &user.PasswordHash, &user.MFASecret,
 		&user.FailedAttempts, &user.LockedUntil, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
@@ -65,27 +85,38 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 
 func (r *UserRepository) UpdateFailedAttempts(ctx context.Context, id string, attempts int, lockedUntil *time.Time) error {
 	query := `UPDATE users SET failed_attempts = $1, locked_until = $2, updated_at = $3 WHERE id = $4`
-	_, err := r.db.ExecContext(ctx, query, attempts, lockedUntil, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, attempts, lockedUntil, time.Now(), id)
+	return err
+}
+
+// UpdatePassword guarda el hash nuevo y desbloquea la cuenta
+func (r *UserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, failed_attempts = 0, locked_until = NULL, updated_at = $2 WHERE id = $3`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, passwordHash, time.Now(), id)
 	return err
 }
 
 func (r *UserRepository) UpdateMFASecret(ctx context.Context, id string, secret string) error {
 	query := `UPDATE users SET mfa_secret = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, secret, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, secret, time.Now(), id)
 	return err
 }
 
-// GetPermissions retorna los permisos efectivos del usuario (vía roles)
+// GetPermissions retorna los permisos efectivos del usuario (vía roles). Los roles de
+// otra agencia no cuentan y los permisos de plataforma solo llegan por roles globales.
 func (r *UserRepository) GetPermissions(ctx context.Context, userID string) ([]domain.Permission, error) {
 	query := `
-	SELECT DISTINCT p.id, p.name, p.resource, p.action, p.created_at
+	SELECT DISTINCT p.id, p.name, p.resource, p.action, p.platform, p.created_at
 	FROM permissions p
 	JOIN role_permissions rp ON p.id = rp.permission_id
 	JOIN roles ro ON rp.role_id = ro.id
 	JOIN user_roles ur ON ro.id = ur.role_id
+	JOIN users u ON u.id = ur.user_id
 	WHERE ur.user_id = $1
+	  AND (ro.organization_id IS NULL OR ro.organization_id = u.organization_id)
+	  AND (NOT p.platform OR ro.organization_id IS NULL)
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +126,7 @@ func (r *UserRepository) GetPermissions(ctx context.Context, userID string) ([]d
 	for rows.Next() {
 		var p domain.Permission
 		var createdAt time.Time
-		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &createdAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Platform, &createdAt); err != nil {
 			return nil, err
 		}
 		p.CreatedAt = createdAt
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type webhookRepo struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository crea una instancia del repositorio de webhooks.
+func NewWebhookRepository(db *sql.DB) ports.WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+// El secreto solo se lee al despachar (ver ClaimDue)
+const webhookSubscriptionColumns = `id, organization_id, url, description, event_types, active, created_by, created_at, updated_at`
+
+func scanWebhookSubscription(row interface{ Scan(...any) error }, s *domain.WebhookSubscription) error {
+	return row.Scan(&s.ID, &s.OrganizationID, &s.URL, &s.Description, pq.Array(&s.EventTypes), &s.Active,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+}
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+                                d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.redelivery_of,
+                                d.created_at, d.updated_at`
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }, d *domain.WebhookDelivery, extra ...any) error {
+	dest := []any{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.RedeliveryOf,
+		&d.CreatedAt, &d.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	organizationID := ownerTenant(ctx)
+	if organizationID == "" {
+		return domain.ErrTenantRequired
+	}
+	query := `INSERT INTO webhook_subscriptions (organization_id, url, description, event_types, secret, active, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              RETURNING id, created_at, updated_at`
+	sub.OrganizationID = organizationID
+	return r.db.QueryRowContext(ctx, query, organizationID, sub.URL, sub.Description, pq.Array(sub.EventTypes),
+		sub.Secret, sub.Active, sub.CreatedBy).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + `
+              FROM webhook_subscriptions
+              WHERE id = $1 AND ` + tenantFilter("organization_id", 2)
+
+	var s domain.WebhookSubscription
+	if err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, id, tenantID(ctx)), &s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + `
+              FROM webhook_subscriptions
+              WHERE ` + tenantFilter("organization_id", 1) + `
+              ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		var s domain.WebhookSubscription
+		if err := scanWebhookSubscription(rows, &s); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	query := `UPDATE webhook_subscriptions
+              SET url = $1, description = $2, event_types = $3, active = $4,
+                  secret = COALESCE(NULLIF($5, ''), secret), updated_at = $6
+              WHERE id = $7 AND ` + tenantFilter("organization_id", 8)
+	sub.UpdatedAt = time.Now()
+	res, err := r.db.ExecContext(ctx, query, sub.URL, sub.Description, pq.Array(sub.EventTypes), sub.Active,
+		sub.Secret, sub.UpdatedAt, sub.ID, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// DeleteSubscription elimina la suscripción junto con su bitácora de entregas
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1 AND ` + tenantFilter("organization_id", 2)
+	res, err := r.db.ExecContext(ctx, query, id, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Enqueue no se acota al usuario: el servicio ya leyó la propiedad y el evento
+// pertenece a la agencia dueña de la propiedad
+func (r *webhookRepo) Enqueue(ctx context.Context, event *domain.WebhookEvent, payload []byte) (int, error) {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at)
+              SELECT s.id, $1, $2, $3, $4
+              FROM webhook_subscriptions s
+              JOIN properties p ON p.organization_id = s.organization_id
+              WHERE p.id = $5 AND s.active AND $2 = ANY(s.event_types)
+              ON CONFLICT (subscription_id, event_id) WHERE redelivery_of IS NULL DO NOTHING`
+	res, err := r.db.ExecContext(ctx, query, event.ID, event.Type, payload, time.Now().UTC(), event.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *webhookRepo) EnqueueTo(ctx context.Context, subscriptionID int64, event *domain.WebhookEvent, payload []byte) (*domain.WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries AS d (subscription_id, event_id, event_type, payload, next_attempt_at)
+              SELECT s.id, $2, $3, $4, $5
+              FROM webhook_subscriptions s
+              WHERE s.id = $1 AND ` + tenantFilter("s.organization_id", 6) + `
+              RETURNING ` + webhookDeliveryColumns
+
+	var d domain.WebhookDelivery
+	err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, subscriptionID, event.ID, event.Type, payload,
+		time.Now().UTC(), tenantID(ctx)), &d)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ClaimDue usa SKIP LOCKED y adelanta next_attempt_at por el lease: si el proceso
+// cae durante el envío, la entrega se vuelve a intentar al vencer (al menos una vez)
+func (r *webhookRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	query := `WITH due AS (
+                  SELECT d.id
+                  FROM webhook_deliveries d
+                  JOIN webhook_subscriptions s ON s.id = d.subscription_id
+                  WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND s.active
+                  ORDER BY d.next_attempt_at
+                  LIMIT $3
+                  FOR UPDATE OF d SKIP LOCKED
+              )
+              UPDATE webhook_deliveries d
+              SET next_attempt_at = $2
+              FROM due, webhook_subscriptions s
+              WHERE d.id = due.id AND s.id = d.subscription_id
+              RETURNING ` + webhookDeliveryColumns + `, s.url, s.secret`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d, &d.Target.URL, &d.Target.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	attemptQuery := `INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, response_body, duration_ms, created_at)
+                     VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.ExecContext(ctx, attemptQuery, delivery.ID, attempt.Attempt, attempt.StatusCode, attempt.Error,
+		attempt.ResponseBody, attempt.DurationMs, attempt.CreatedAt); err != nil {
+		return err
+	}
+
+	deliveryQuery := `UPDATE webhook_deliveries
+                      SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4,
+                          last_error = $5, delivered_at = $6, updated_at = $7
+                      WHERE id = $8`
+	if _, err := tx.ExecContext(ctx, deliveryQuery, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListDeliveries retorna las entregas más recientes primero; status vacío = todas
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+              FROM webhook_deliveries d
+              JOIN webhook_subscriptions s ON s.id = d.subscription_id
+              WHERE d.subscription_id = $1 AND ($2 = '' OR d.status = $2) AND ` + tenantFilter("s.organization_id", 4) + `
+              ORDER BY d.id DESC
+              LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, status, limit, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+              FROM webhook_deliveries d
+              JOIN webhook_subscriptions s ON s.id = d.subscription_id
+              WHERE d.id = $1 AND d.subscription_id = $2 AND ` + tenantFilter("s.organization_id", 3)
+
+	var d domain.WebhookDelivery
+	if err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id, subscriptionID, tenantID(ctx)), &d); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT attempt, status_code, error, response_body, duration_ms, created_at
+                                         FROM webhook_delivery_attempts
+                                         WHERE delivery_id = $1
+                                         ORDER BY attempt, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	d.History = make([]domain.WebhookAttempt, 0)
+	for rows.Next() {
+		var a domain.WebhookAttempt
+		if err := rows.Scan(&a.Attempt, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMs, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.History = append(d.History, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Redeliver conserva event_id para que el receptor pueda descartar duplicados
+func (r *webhookRepo) Redeliver(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries AS d (subscription_id, event_id, event_type, payload, next_attempt_at, redelivery_of)
+              SELECT o.subscription_id, o.event_id, o.event_type, o.payload, $3, o.id
+              FROM webhook_deliveries o
+              JOIN webhook_subscriptions s ON s.id = o.subscription_id
+              WHERE o.id = $1 AND o.subscription_id = $2 AND ` + tenantFilter("s.organization_id", 4) + `
+              RETURNING ` + webhookDeliveryColumns
+
+	var d domain.WebhookDelivery
+	err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id, subscriptionID, time.Now().UTC(), tenantID(ctx)), &d)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+	"time"
+)
+
+type zoneBoundaryRepo struct {
+	db *sql.DB
+}
+
+// NewZoneBoundaryRepository crea una instancia del repositorio de polígonos.
+func NewZoneBoundaryRepository(db *sql.DB) ports.ZoneBoundaryRepository {
+	return &zoneBoundaryRepo{db: db}
+}
+
+func (r *zoneBoundaryRepo) Upsert(ctx context.Context, b *domain.ZoneBoundary) error {
+	query := `INSERT INTO zone_boundaries
+              (name, kind, municipality_id, geo_zone_id, geometry, min_lat, min_lng, max_lat, max_lng)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              ON CONFLICT (name, kind) DO UPDATE
+              SET municipality_id = EXCLUDED.municipality_id, geo_zone_id = EXCLUDED.geo_zone_id,
+                  geometry = EXCLUDED.geometry, min_lat = EXCLUDED.min_lat, min_lng = EXCLUDED.min_lng,
+                  max_lat = EXCLUDED.max_lat, max_lng = EXCLUDED.max_lng, updated_at = $10
+              RETURNING id, created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, b.Name, b.Kind, b.MunicipalityID, b.GeoZoneID, []byte(b.Geometry),
+		b.MinLat, b.MinLng, b.MaxLat, b.MaxLng, time.Now()).
+		Scan(&b.ID, &b.CreatedAt, &b.UpdatedAt)
+}
+
+func (r *zoneBoundaryRepo) List(ctx context.Context) ([]domain.ZoneBoundary, error) {
+	query := `SELECT id, name, kind, municipality_id, geo_zone_id, geometry,
+                     min_lat, min_lng, max_lat, max_lng, created_at, updated_at
+              FROM zone_boundaries
+              ORDER BY kind, name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	boundaries := make([]domain.ZoneBoundary, 0)
+	for rows.Next() {
+		var b domain.ZoneBoundary
+		var geometry []byte
+		if err := rows.Scan(&b.ID, &b.Name, &b.Kind, &b.MunicipalityID, &b.GeoZoneID, &geometry,
+			&b.MinLat, &b.MinLng, &b.MaxLat, &b.MaxLng, &b.CreatedAt, &b.UpdatedAt); err != nil {
+			return nil, err
+		}
+		b.Geometry = geometry
+		boundaries = append(boundaries, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return boundaries, nil
+}
+
+func (r *zoneBoundaryRepo) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM zone_boundaries WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("zone boundary not found")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// maxStatsRange limita el rango de la serie diaria para evitar consultas enormes
+const maxStatsRange = 366 * 24 * time.Hour
+
+// Errores de rango de fechas de los reportes (errores del cliente)
+var (
+	ErrInvalidDateRange  = errors.New("invalid date range")
+	ErrDateRangeTooLarge = errors.New("date range too large")
+)
+
+type analyticsService struct {
+	repo   ports.AnalyticsRepository
+	tx     ports.TxManager
+	outbox ports.OutboxRepository
+	views  chan domain.PropertyEvent
+}
+
+// NewAnalyticsService crea el servicio y arranca el worker que persiste las vistas
+// en segundo plano. bufferSize define cuántas vistas pueden quedar en cola.
+// Las consultas se registran en outbox como lead.created en la misma transacción.
+func NewAnalyticsService(ctx context.Context, repo ports.AnalyticsRepository, tx ports.TxManager, outbox ports.OutboxRepository, bufferSize int) ports.AnalyticsService {
+	s := &analyticsService{
+		repo:   repo,
+		tx:     tx,
+		outbox: outbox,
+		views:  make(chan domain.PropertyEvent, bufferSize),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// TrackView encola la vista; si la cola está llena se descarta para no frenar la petición
+func (s *analyticsService) TrackView(event domain.PropertyEvent) {
+	event.EventType = domain.PropertyEventView
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	select {
+	case s.views <- event:
+	default:
+		slog.Warn("View queue full, dropping event", "property_id", event.PropertyID)
+	}
+}
+
+// RecordEvent persiste favoritos y consultas de forma síncrona
+func (s *analyticsService) RecordEvent(ctx context.Context, event *domain.PropertyEvent) error {
+	switch event.EventType {
+	case domain.PropertyEventView, domain.PropertyEventFavorite, domain.PropertyEventInquiry:
+	default:
+		return errors.New("invalid event type")
+	}
+	if event.ViewerKey == "" {
+		return errors.New("viewer key is required")
+	}
+	if event.EventType != domain.PropertyEventInquiry {
+		return s.repo.RecordEvent(ctx, event)
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.RecordEvent(ctx, event); err != nil {
+			return err
+		}
+		lead, err := domain.NewOutboxEvent(domain.EventLeadCreated, domain.AggregateProperty, strconv.FormatInt(event.PropertyID, 10), event)
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, lead)
+	})
+}
+
+func (s *analyticsService) GetDailyStats(ctx context.Context, propertyID int64, from, to time.Time) ([]domain.PropertyDailyStats, error) {
+	if to.Before(from) {
+		return nil, ErrInvalidDateRange
+	}
+	if to.Sub(from) > maxStatsRange {
+		return nil, ErrDateRangeTooLarge
+	}
+	return s.repo.GetDailyStats(ctx, propertyID, from, to)
+}
+
+func (s *analyticsService) GetTopViewed(ctx context.Context, from, to time.Time, limit int) ([]domain.PropertyViewRank, error) {
+	if to.Before(from) {
+		return nil, ErrInvalidDateRange
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return s.repo.GetTopViewed(ctx, from, to, limit)
+}
+
+// run consume la cola de vistas hasta que se cancele el contexto
+func (s *analyticsService) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.views:
+			writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			if err := s.repo.RecordEvent(writeCtx, &event); err != nil {
+				slog.Warn("Failed to record property view", "property_id", event.PropertyID, "error", err)
+			}
+			cancel()
+		}
+	}
+}
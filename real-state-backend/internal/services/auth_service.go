@@ -23,7 +23,8 @@ import (
 type AuthService struct {
 	userRepo    ports.UserRepository
 	sessionRepo ports.SessionRepository
-	auditRepo   ports.AuditRepository
+	outbox      ports.OutboxRepository
+	tx          ports.TxManager
 	jwtSecret   []byte
 	jwtPepper   []byte
 	accessTTL   time.Duration
@@ -32,12 +33,14 @@ type AuthService struct {
 	lockoutDur  time.Duration
 }
 
-// NewAuthService crea una nueva instancia de AuthService
-func NewAuthService(userRepo ports.UserRepository, sessionRepo ports.SessionRepository, auditRepo ports.AuditRepository, secret, pepper string, accessTTL, refreshTTL time.Duration, maxAttempts int, lockoutDur time.Duration) *AuthService {
+// NewAuthService crea una nueva instancia de AuthService. Los eventos de auditoría se
+// registran en outbox junto con el cambio que los origina (ver AuditLogHandler).
+func NewAuthService(userRepo ports.UserRepository, sessionRepo ports.SessionRepository, outbox ports.OutboxRepository, tx ports.TxManager, secret, pepper string, accessTTL, refreshTTL time.Duration, maxAttempts int, lockoutDur time.Duration) *AuthService {
 	return &AuthService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
-		auditRepo:   auditRepo,
+		outbox:      outbox,
+		tx:          tx,
 		jwtSecret:   []byte(secret),
 		jwtPepper:   []byte(pepper),
 		accessTTL:   accessTTL,
@@ -58,6 +61,12 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequestDTO, device
 	// Debug log temporal (desactivar en producción)
 	slog.Info("Login attempt", "username", req.Username, "user_id", user.ID)
 
+	// Usuarios de agencias desactivadas no inician sesión
+	if !user.OrganizationActive {
+		s.logAudit(ctx, "LOGIN_FAILURE", &user.ID, "auth", "login", nil, map[string]interface{}{"reason": "organization_inactive"}, "", userAgent)
+		return dto.LoginResponseDTO{}, errors.New("invalid credentials")
+	}
+
 	// Verificar bloqueo
 	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
 		s.logAudit(ctx, "LOGIN_FAILURE", &user.ID, "auth", "login", nil, map[string]interface{}{"reason": "account_locked"}, "", userAgent)
@@ -72,15 +81,15 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequestDTO, device
 		return dto.LoginResponseDTO{}, errors.New("invalid credentials")
 	}
 
-	// Reset failed attempts on success
-	s.userRepo.UpdateFailedAttempts(ctx, user.ID, 0, nil)
-
 	// Generar tokens
 	accessToken, refreshToken, jti, err := s.generateTokens(user.ID, deviceFingerprint)
 	if err != nil {
 		return dto.LoginResponseDTO{}, err
 	}
 
+	// Verificar si MFA está habilitado
+	mfaRequired := user.MFASecret != nil && *user.MFASecret != ""
+
 	// Crear sesión
 	session := &domain.UserSession{
 		UserID:           user.ID,
@@ -92,15 +101,20 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequestDTO, device
 		DeviceMetadata:   deviceMetadata,
 		ExpiresAt:        time.Now().Add(s.refreshTTL),
 	}
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
+	// Reset de intentos, sesión y auditoría se confirman juntos
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateFailedAttempts(ctx, user.ID, 0, nil); err != nil {
+			return err
+		}
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			return err
+		}
+		return s.addAudit(ctx, "LOGIN_SUCCESS", &user.ID, "auth", "login", nil, map[string]interface{}{"mfa_required": mfaRequired}, "", userAgent)
+	})
+	if err != nil {
 		return dto.LoginResponseDTO{}, err
 	}
 
-	// Verificar si MFA está habilitado
-	mfaRequired := user.MFASecret != nil && *user.MFASecret != ""
-
-	s.logAudit(ctx, "LOGIN_SUCCESS", &user.ID, "auth", "login", nil, map[string]interface{}{"mfa_required": mfaRequired}, "", userAgent)
-
 	return dto.LoginResponseDTO{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -151,11 +165,21 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, dev
 	if err != nil || session.Revoked {
 		return dto.TokenResponseDTO{}, errors.New("session revoked")
 	}
+	if !session.OrganizationActive {
+		return dto.TokenResponseDTO{}, domain.ErrOrganizationInactive
+	}
 
 	// Verificar device consistency
 	if subtle.ConstantTimeCompare([]byte(session.DeviceID), []byte(deviceFingerprint)) != 1 {
-		s.sessionRepo.RevokeByJTI(ctx, jti)
-		s.logAudit(ctx, "SESSION_HIJACK_ATTEMPT", &session.UserID, "auth", "refresh", nil, map[string]interface{}{"device_mismatch": true}, "", "")
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.sessionRepo.RevokeByJTI(ctx, jti); err != nil {
+				return err
+			}
+			return s.addAudit(ctx, "SESSION_HIJACK_ATTEMPT", &session.UserID, "auth", "refresh", nil, map[string]interface{}{"device_mismatch": true}, "", "")
+		})
+		if err != nil {
+			slog.Error("Failed to revoke hijacked session", "error", err)
+		}
 		return dto.TokenResponseDTO{}, errors.New("device mismatch")
 	}
 
@@ -176,12 +200,12 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, dev
 
 // Logout revoca la sesión
 func (s *AuthService) Logout(ctx context.Context, tokenJTI string) error {
-	err := s.sessionRepo.RevokeByJTI(ctx, tokenJTI)
-	if err != nil {
-		return err
-	}
-	s.logAudit(ctx, "LOGOUT", nil, "auth", "logout", nil, nil, "", "")
-	return nil
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.sessionRepo.RevokeByJTI(ctx, tokenJTI); err != nil {
+			return err
+		}
+		return s.addAudit(ctx, "LOGOUT", nil, "auth", "logout", nil, nil, "", "")
+	})
 }
 
 // ValidateSession valida la sesión para middlewares
@@ -193,6 +217,9 @@ func (s *AuthService) ValidateSession(ctx context.Context, tokenJTI string) (*do
 	if session.Revoked || time.Now().After(session.ExpiresAt) {
 		return nil, errors.New("session invalid")
 	}
+	if !session.OrganizationActive {
+		return nil, domain.ErrOrganizationInactive
+	}
 	return session, nil
 }
 
@@ -259,6 +286,15 @@ func (s *AuthService) checkPassword(password, hash string) bool {
 	return err == nil
 }
 
+// HashPassword genera el hash bcrypt + pepper para guardar una contraseña nueva
+func (s *AuthService) HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(s.pepperPassword(password)), 12)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
 // pepperPassword aplica pepper a la contraseña
 func (s *AuthService) pepperPassword(password string) string {
 	return fmt.Sprintf("%s%s", password, s.jwtPepper)
@@ -278,12 +314,26 @@ func (s *AuthService) handleFailedAttempt(ctx context.Context, user *domain.User
 		lockTime := time.Now().Add(s.lockoutDur)
 		lockedUntil = &lockTime
 	}
-	s.userRepo.UpdateFailedAttempts(ctx, user.ID, attempts, lockedUntil)
-	s.logAudit(ctx, "LOGIN_FAILURE", &user.ID, "auth", "login", nil, map[string]interface{}{"attempts": attempts}, "", userAgent)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateFailedAttempts(ctx, user.ID, attempts, lockedUntil); err != nil {
+			return err
+		}
+		return s.addAudit(ctx, "LOGIN_FAILURE", &user.ID, "auth", "login", nil, map[string]interface{}{"attempts": attempts}, "", userAgent)
+	})
+	if err != nil {
+		slog.Error("Failed to record failed login attempt", "user_id", user.ID, "error", err)
+	}
 }
 
-// logAudit registra evento de auditoría
+// logAudit registra un evento de auditoría sin cambio asociado; un fallo solo se reporta
 func (s *AuthService) logAudit(ctx context.Context, eventType string, userID *string, resource, action string, oldValues, newValues map[string]interface{}, ip, userAgent string) {
+	if err := s.addAudit(ctx, eventType, userID, resource, action, oldValues, newValues, ip, userAgent); err != nil {
+		slog.Error("Failed to record audit event", "event_type", eventType, "error", err)
+	}
+}
+
+// addAudit registra el evento de auditoría en outbox; dentro de WithinTx se confirma con el cambio
+func (s *AuthService) addAudit(ctx context.Context, eventType string, userID *string, resource, action string, oldValues, newValues map[string]interface{}, ip, userAgent string) error {
 	log := &domain.AuditLog{
 		EventType: eventType,
 		UserID:    userID,
@@ -295,5 +345,13 @@ func (s *AuthService) logAudit(ctx context.Context, eventType string, userID *st
 		UserAgent: userAgent,
 		Timestamp: time.Now(),
 	}
-	s.auditRepo.LogEvent(ctx, log)
+	var aggregateID string
+	if userID != nil {
+		aggregateID = *userID
+	}
+	event, err := domain.NewOutboxEvent(domain.EventAuditLogged, domain.AggregateUser, aggregateID, log)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, event)
 }
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// permissionManageCommissions permite ver los estados de cuenta de otros agentes
+const permissionManageCommissions = "manage_commissions"
+
+type commissionService struct {
+	repo       ports.CommissionRepository
+	properties ports.PropertyRepository
+	offers     ports.OfferRepository
+	auth       ports.AuthService
+}
+
+func NewCommissionService(repo ports.CommissionRepository, properties ports.PropertyRepository, offers ports.OfferRepository, auth ports.AuthService) ports.CommissionService {
+	return &commissionService{
+		repo:       repo,
+		properties: properties,
+		offers:     offers,
+		auth:       auth,
+	}
+}
+
+func (s *commissionService) ListRules(ctx context.Context) ([]domain.CommissionRule, error) {
+	return s.repo.ListRules(ctx)
+}
+
+func (s *commissionService) UpdateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		rule.UpdatedBy = &userID
+	}
+	return s.repo.SaveRule(ctx, rule)
+}
+
+// Settle liquida el cierre vigente de la propiedad. El precio es el de la oferta
+// aceptada en este ciclo o, si no la hay, el precio publicado.
+func (s *commissionService) Settle(ctx context.Context, propertyID int64, sellingAgentID *string) (*domain.CommissionSettlement, error) {
+	property, err := s.properties.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	operation := domain.OperationForStatus(property.Status)
+	if operation == "" || property.ClosedAt == nil {
+		return nil, domain.ErrPropertyNotClosed
+	}
+	rule, err := s.repo.GetRule(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &domain.CommissionSettlement{
+		PropertyID:     propertyID,
+		OperationType:  operation,
+		ClosedAt:       *property.ClosedAt,
+		Price:          property.Price,
+		Currency:       property.Currency,
+		ListingAgentID: property.AgentID,
+		SellingAgentID: sellingAgentID,
+		Rule:           *rule,
+	}
+	if settlement.SellingAgentID == nil {
+		settlement.SellingAgentID = property.AgentID
+	}
+	if err := s.applyAcceptedOffer(ctx, settlement); err != nil {
+		return nil, err
+	}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		settlement.CreatedBy = &userID
+	}
+
+	splitCommission(settlement)
+	if err := s.repo.CreateSettlement(ctx, settlement); err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// applyAcceptedOffer usa el monto de la última oferta aceptada posterior al cierre anterior
+func (s *commissionService) applyAcceptedOffer(ctx context.Context, settlement *domain.CommissionSettlement) error {
+	previous, err := s.repo.ListSettlements(ctx, settlement.PropertyID)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if len(previous) > 0 {
+		since = previous[0].ClosedAt
+	}
+	offers, err := s.offers.List(ctx, settlement.PropertyID, "")
+	if err != nil {
+		return err
+	}
+	for _, o := range offers {
+		if o.Status == domain.OfferStatusAccepted && o.UpdatedAt.After(since) {
+			settlement.Price, settlement.Currency, settlement.OfferID = o.Amount, o.Currency, &o.ID
+			return nil
+		}
+	}
+	return nil
+}
+
+// splitCommission calcula la comisión y una entrada por participante. La parte de
+// un rol sin agente queda para la agencia; un mismo agente en ambos roles recibe una sola entrada.
+func splitCommission(s *domain.CommissionSettlement) {
+	rule := s.Rule
+	commission := round2(s.Price * rule.RatePercent / 100)
+	agency := round2(commission * rule.AgencySharePercent / 100)
+	agentsPool := round2(commission - agency)
+	listing := round2(agentsPool * rule.ListingSplitPercent / 100)
+	selling := round2(agentsPool - listing)
+
+	var agents []domain.CommissionEntry
+	assign := func(agentID *string, role string, amount float64) {
+		if agentID == nil {
+			agency = round2(agency + amount)
+			return
+		}
+		for i := range agents {
+			if *agents[i].AgentID == *agentID {
+				agents[i].Roles = append(agents[i].Roles, role)
+				agents[i].BaseAmount = round2(agents[i].BaseAmount + amount)
+				return
+			}
+		}
+		agents = append(agents, domain.CommissionEntry{Participant: domain.CommissionParticipantAgent, AgentID: agentID, Roles: []string{role}, BaseAmount: amount})
+	}
+	assign(s.ListingAgentID, domain.CommissionRoleListing, listing)
+	assign(s.SellingAgentID, domain.CommissionRoleSelling, selling)
+	entries := append(agents, domain.CommissionEntry{Participant: domain.CommissionParticipantAgency, Roles: []string{}, BaseAmount: agency})
+
+	s.CommissionAmount, s.IVAAmount = commission, 0
+	for i := range entries {
+		e := &entries[i]
+		e.PropertyID, e.Currency, e.EntryDate = s.PropertyID, s.Currency, s.ClosedAt
+		e.IVAAmount = round2(e.BaseAmount * rule.IVAPercent / 100)
+		e.TotalAmount = round2(e.BaseAmount + e.IVAAmount)
+		s.IVAAmount = round2(s.IVAAmount + e.IVAAmount)
+	}
+	s.Entries = entries
+}
+
+func (s *commissionService) ListSettlements(ctx context.Context, propertyID int64) ([]domain.CommissionSettlement, error) {
+	return s.repo.ListSettlements(ctx, propertyID)
+}
+
+// Statement agrupa las entradas del agente y suma por moneda
+func (s *commissionService) Statement(ctx context.Context, agentID string, from, to time.Time) (*domain.AgentStatement, error) {
+	if err := s.authorizeStatement(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+	entries, err := s.repo.ListAgentEntries(ctx, agentID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &domain.AgentStatement{AgentID: agentID, From: from, To: to, Entries: entries, Totals: make([]domain.CommissionTotal, 0)}
+	index := make(map[string]int)
+	for _, e := range entries {
+		i, ok := index[e.Currency]
+		if !ok {
+			i = len(statement.Totals)
+			index[e.Currency] = i
+			statement.Totals = append(statement.Totals, domain.CommissionTotal{Currency: e.Currency})
+		}
+		t := &statement.Totals[i]
+		t.BaseAmount = round2(t.BaseAmount + e.BaseAmount)
+		t.IVAAmount = round2(t.IVAAmount + e.IVAAmount)
+		t.TotalAmount = round2(t.TotalAmount + e.TotalAmount)
+		t.Entries++
+	}
+	return statement, nil
+}
+
+// authorizeStatement permite al propio agente o a quien tenga manage_commissions
+func (s *commissionService) authorizeStatement(ctx context.Context, agentID string) error {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return domain.ErrCommissionAccessDenied
+	}
+	if userID == agentID {
+		return nil
+	}
+	permissions, err := s.auth.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		if p.Name == permissionManageCommissions {
+			return nil
+		}
+	}
+	return domain.ErrCommissionAccessDenied
+}
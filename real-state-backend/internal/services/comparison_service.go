@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type comparisonService struct {
+	propertyRepo ports.PropertyRepository
+	rateRepo     ports.ExchangeRateRepository
+}
+
+func NewComparisonService(propertyRepo ports.PropertyRepository, rateRepo ports.ExchangeRateRepository) ports.ComparisonService {
+	return &comparisonService{propertyRepo: propertyRepo, rateRepo: rateRepo}
+}
+
+// Compare solo incluye propiedades visibles para compradores; si alguna no existe o
+// no está publicada/reservada se rechaza la comparación completa
+func (s *comparisonService) Compare(ctx context.Context, ids []int64, currency string) (*domain.PropertyComparison, error) {
+	found, err := s.propertyRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]domain.Property, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	properties := make([]domain.Property, len(ids))
+	var unavailable []int64
+	for i, id := range ids {
+		p, ok := byID[id]
+		if !ok || !domain.IsListedStatus(p.Status) {
+			unavailable = append(unavailable, id)
+			continue
+		}
+		p.ComputeAreas()
+		properties[i] = p
+	}
+	if len(unavailable) > 0 {
+		return nil, fmt.Errorf("%w: %v", domain.ErrPropertyNotComparable, unavailable)
+	}
+
+	rates, err := s.rateRepo.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prices := make([]any, len(properties))
+	pricePerSqM := make([]any, len(properties))
+	for i, p := range properties {
+		price, err := convertCurrency(rates, p.Price, p.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = round2(price)
+		if p.AreaSqM > 0 {
+			pricePerSqM[i] = round2(price / p.AreaSqM)
+		}
+	}
+
+	return &domain.PropertyComparison{
+		Currency:   currency,
+		Properties: properties,
+		Attributes: []domain.ComparedAttribute{
+			compareAttribute("type", properties, func(p domain.Property) any { return p.Type }),
+			compareAttribute("status", properties, func(p domain.Property) any { return p.Status }),
+			alignedAttribute("price", prices),
+			alignedAttribute("price_per_sqm", pricePerSqM),
+			compareAttribute("area_sqm", properties, func(p domain.Property) any { return p.AreaSqM }),
+			compareAttribute("bedrooms", properties, func(p domain.Property) any { return p.Bedrooms }),
+			compareAttribute("bathrooms", properties, func(p domain.Property) any { return p.Bathrooms }),
+			compareAttribute("department", properties, func(p domain.Property) any { return p.Department }),
+			compareAttribute("city", properties, func(p domain.Property) any { return p.City }),
+			compareAttribute("zone", properties, func(p domain.Property) any { return p.Zone }),
+			compareAttribute("neighbourhood", properties, func(p domain.Property) any { return p.Neighbourhood }),
+		},
+		Amenities: domain.NewAmenityDiff(properties),
+		Distances: propertyDistances(properties),
+	}, nil
+}
+
+func compareAttribute(name string, properties []domain.Property, value func(domain.Property) any) domain.ComparedAttribute {
+	values := make([]any, len(properties))
+	for i, p := range properties {
+		values[i] = value(p)
+	}
+	return alignedAttribute(name, values)
+}
+
+func alignedAttribute(name string, values []any) domain.ComparedAttribute {
+	same := true
+	for _, v := range values[1:] {
+		same = same && reflect.DeepEqual(v, values[0])
+	}
+	return domain.ComparedAttribute{Attribute: name, Values: values, Same: same}
+}
+
+// propertyDistances calcula la distancia de cada par con coordenadas (en metros)
+func propertyDistances(properties []domain.Property) []domain.PropertyDistance {
+	distances := make([]domain.PropertyDistance, 0)
+	for i := range properties {
+		for j := i + 1; j < len(properties); j++ {
+			a, b := &properties[i], &properties[j]
+			if !hasCoordinates(a) || !hasCoordinates(b) {
+				continue
+			}
+			distances = append(distances, domain.PropertyDistance{
+				FromID: a.ID,
+				ToID:   b.ID,
+				Meters: math.Round(haversineMeters(a.Lat, a.Lng, b.Lat, b.Lng)),
+			})
+		}
+	}
+	return distances
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"fmt"
+)
+
+// convertCurrency convierte un monto entre monedas usando tasas expresadas en USD por unidad
+func convertCurrency(rates map[string]float64, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, fmt.Errorf("missing exchange rate for %s", from)
+	}
+	toRate, ok := rates[to]
+	if !ok || toRate <= 0 {
+		return 0, fmt.Errorf("missing exchange rate for %s", to)
+	}
+	return amount * fromRate / toRate, nil
+}
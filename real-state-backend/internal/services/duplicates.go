@@ -0,0 +1,108 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// duplicateThreshold es el puntaje mínimo para considerar dos propiedades duplicadas
+const duplicateThreshold = 0.6
+
+// duplicateScore compara dos propiedades y retorna un puntaje 0..1, los motivos
+// y la distancia en metros cuando ambas tienen coordenadas.
+func duplicateScore(a, b *domain.Property) (float64, []string, *float64) {
+	reasons := make([]string, 0, 4)
+
+	addrA, addrB := domain.NormalizeAddress(a.Address), domain.NormalizeAddress(b.Address)
+	addrScore := tokenSimilarity(addrA, addrB)
+	if addrA != "" && addrA == addrB {
+		addrScore = 1
+		reasons = append(reasons, "same_address")
+	} else if addrScore >= 0.7 {
+		reasons = append(reasons, "similar_address")
+	}
+
+	titleScore := tokenSimilarity(domain.NormalizeAddress(a.Title), domain.NormalizeAddress(b.Title))
+	if titleScore >= 0.7 {
+		reasons = append(reasons, "similar_title")
+	}
+
+	priceScore := 0.0
+	if a.Currency == b.Currency && a.Price > 0 && b.Price > 0 {
+		diff := math.Abs(a.Price-b.Price) / math.Max(a.Price, b.Price)
+		switch {
+		case diff <= 0.02:
+			priceScore = 1
+			reasons = append(reasons, "same_price")
+		case diff <= 0.1:
+			priceScore = 0.5
+			reasons = append(reasons, "similar_price")
+		}
+	}
+
+	// Sin coordenadas en ambas, el peso se reparte entre dirección, título y precio
+	if !hasCoordinates(a) || !hasCoordinates(b) {
+		return 0.5*addrScore + 0.3*titleScore + 0.2*priceScore, reasons, nil
+	}
+
+	distance := haversineMeters(a.Lat, a.Lng, b.Lat, b.Lng)
+	geoScore := 0.0
+	switch {
+	case distance <= 50:
+		geoScore = 1
+		reasons = append(reasons, "same_location")
+	case distance <= 150:
+		geoScore = 0.7
+		reasons = append(reasons, "nearby_location")
+	case distance <= 300:
+		geoScore = 0.3
+	}
+
+	score := 0.35*addrScore + 0.3*geoScore + 0.2*titleScore + 0.15*priceScore
+	return score, reasons, &distance
+}
+
+// tokenSimilarity calcula el índice de Jaccard entre las palabras de dos textos
+func tokenSimilarity(a, b string) float64 {
+	tokensA, tokensB := strings.Fields(a), strings.Fields(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(tokensA))
+	for _, t := range tokensA {
+		set[t] = true
+	}
+	union := len(set)
+	intersection := 0
+	seen := make(map[string]bool, len(tokensB))
+	for _, t := range tokensB {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	return float64(intersection) / float64(union)
+}
+
+func hasCoordinates(p *domain.Property) bool {
+	return p.Lat != 0 || p.Lng != 0
+}
+
+// haversineMeters calcula la distancia entre dos coordenadas en metros
+func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
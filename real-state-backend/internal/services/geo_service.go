@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// geoCatalogTTL define cada cuánto se recarga el catálogo en memoria
+const geoCatalogTTL = 10 * time.Minute
+
+// ErrUnknownLocation indica que la ubicación no existe en el catálogo o es ambigua
+var ErrUnknownLocation = errors.New("unknown location")
+
+// geoCatalog es una copia en memoria del catálogo (es pequeño y casi estático)
+type geoCatalog struct {
+	departments    []domain.Department
+	municipalities []domain.Municipality
+	zones          []domain.Zone
+	loadedAt       time.Time
+}
+
+type geoService struct {
+	repo    ports.GeoRepository
+	mu      sync.RWMutex
+	catalog *geoCatalog
+}
+
+func NewGeoService(repo ports.GeoRepository) ports.GeoService {
+	return &geoService{repo: repo}
+}
+
+func (s *geoService) ListDepartments(ctx context.Context) ([]domain.Department, error) {
+	return s.repo.ListDepartments(ctx)
+}
+
+func (s *geoService) ListMunicipalities(ctx context.Context, departmentID int64) ([]domain.Municipality, error) {
+	return s.repo.ListMunicipalities(ctx, departmentID)
+}
+
+func (s *geoService) ListZones(ctx context.Context, municipalityID int64) ([]domain.Zone, error) {
+	return s.repo.ListZones(ctx, municipalityID)
+}
+
+// Resolve normaliza los textos (mayúsculas, tildes, alias como "Guate") y
+// retorna la entrada canónica del catálogo. Un municipio que no está en el
+// catálogo se acepta sin resolver; departamento, zona o nombre ambiguo sí fallan.
+func (s *geoService) Resolve(ctx context.Context, department, city string, zone int) (*domain.GeoLocation, error) {
+	catalog, err := s.loadCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dept *domain.Department
+	if department != "" {
+		key := domain.NormalizeName(department)
+		for i := range catalog.departments {
+			d := &catalog.departments[i]
+			if domain.NormalizeName(d.Name) == key || d.Code == department {
+				dept = d
+				break
+			}
+		}
+		if dept == nil {
+			return nil, fmt.Errorf("%w: department %q not found", ErrUnknownLocation, department)
+		}
+	}
+
+	key := domain.NormalizeName(city)
+	matches := make([]*domain.Municipality, 0, 1)
+	for i := range catalog.municipalities {
+		m := &catalog.municipalities[i]
+		if dept != nil && m.DepartmentID != dept.ID {
+			continue
+		}
+		if municipalityMatches(m, key) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return unresolvedLocation(dept, city, zone), nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: city %q is ambiguous, send the department", ErrUnknownLocation, city)
+	}
+
+	loc := &domain.GeoLocation{Municipality: *matches[0]}
+	for _, d := range catalog.departments {
+		if d.ID == loc.Municipality.DepartmentID {
+			loc.Department = d
+			break
+		}
+	}
+
+	if zone != 0 {
+		for i := range catalog.zones {
+			z := catalog.zones[i]
+			if z.MunicipalityID == loc.Municipality.ID && z.Number == zone {
+				loc.Zone = &z
+				break
+			}
+		}
+		if loc.Zone == nil {
+			return nil, fmt.Errorf("%w: zone %d does not exist in %s", ErrUnknownLocation, zone, loc.Municipality.Name)
+		}
+	}
+	return loc, nil
+}
+
+// unresolvedLocation conserva el municipio escrito por el usuario sin IDs del catálogo
+func unresolvedLocation(dept *domain.Department, city string, zone int) *domain.GeoLocation {
+	loc := &domain.GeoLocation{Municipality: domain.Municipality{Name: strings.TrimSpace(city)}, Unresolved: true}
+	if dept != nil {
+		loc.Department = *dept
+		loc.Municipality.DepartmentID = dept.ID
+	}
+	if zone != 0 {
+		loc.Zone = &domain.Zone{Number: zone, Name: fmt.Sprintf("Zona %d", zone)}
+	}
+	return loc
+}
+
+// municipalityMatches compara el texto normalizado contra el nombre y los alias
+func municipalityMatches(m *domain.Municipality, key string) bool {
+	if domain.NormalizeName(m.Name) == key {
+		return true
+	}
+	for _, alias := range m.Aliases {
+		if domain.NormalizeName(alias) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCatalog retorna el catálogo en memoria, recargándolo si expiró
+func (s *geoService) loadCatalog(ctx context.Context) (*geoCatalog, error) {
+	s.mu.RLock()
+	catalog := s.catalog
+	s.mu.RUnlock()
+	if catalog != nil && time.Since(catalog.loadedAt) < geoCatalogTTL {
+		return catalog, nil
+	}
+
+	departments, err := s.repo.ListDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	municipalities, err := s.repo.ListMunicipalities(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := s.repo.ListZones(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog = &geoCatalog{
+		departments:    departments,
+		municipalities: municipalities,
+		zones:          zones,
+		loadedAt:       time.Now(),
+	}
+	s.mu.Lock()
+	s.catalog = catalog
+	s.mu.Unlock()
+	return catalog, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// Confianza de los resultados del geocodificador sin conexión
+const (
+	catalogNeighbourhoodConfidence = 0.6
+	catalogZoneConfidence          = 0.45
+	catalogMunicipalityConfidence  = 0.2
+)
+
+type catalogGeocoder struct {
+	geo   ports.GeoService
+	zones ports.ZoneBoundaryService
+}
+
+// NewCatalogGeocoder crea un geocodificador sin conexión que ubica la dirección
+// en el centro de su colonia, zona o municipio según el catálogo cargado.
+func NewCatalogGeocoder(geo ports.GeoService, zones ports.ZoneBoundaryService) ports.Geocoder {
+	return &catalogGeocoder{geo: geo, zones: zones}
+}
+
+func (g *catalogGeocoder) Geocode(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+	boundaries, err := g.zones.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Colonia mencionada en la dirección
+	text := " " + domain.NormalizeName(query.Address+" "+query.Neighbourhood) + " "
+	var best *domain.ZoneBoundary
+	for i := range boundaries {
+		b := &boundaries[i]
+		if b.Kind != domain.BoundaryKindNeighbourhood || !sameMunicipality(b, query.MunicipalityID) {
+			continue
+		}
+		name := domain.NormalizeName(b.Name)
+		if strings.Contains(text, " "+name+" ") && (best == nil || len(b.Name) > len(best.Name)) {
+			best = b
+		}
+	}
+	if result := boundaryResult(best, catalogNeighbourhoodConfidence); result != nil {
+		return result, nil
+	}
+
+	// 2. Polígono de la zona del catálogo
+	if query.ZoneID != nil {
+		for i := range boundaries {
+			b := &boundaries[i]
+			if b.Kind == domain.BoundaryKindZone && b.GeoZoneID != nil && *b.GeoZoneID == *query.ZoneID {
+				if result := boundaryResult(b, catalogZoneConfidence); result != nil {
+					return result, nil
+				}
+			}
+		}
+	}
+
+	// 3. Centro del municipio
+	if query.City != "" {
+		loc, err := g.geo.Resolve(ctx, query.Department, query.City, 0)
+		if err == nil && loc.Municipality.Lat != nil && loc.Municipality.Lng != nil {
+			return &domain.GeocodeResult{
+				Lat:        *loc.Municipality.Lat,
+				Lng:        *loc.Municipality.Lng,
+				Confidence: catalogMunicipalityConfidence,
+				Source:     domain.GeocodeSourceCatalog,
+			}, nil
+		}
+	}
+	return nil, domain.ErrAddressNotFound
+}
+
+func sameMunicipality(b *domain.ZoneBoundary, municipalityID *int64) bool {
+	return b.MunicipalityID == nil || municipalityID == nil || *b.MunicipalityID == *municipalityID
+}
+
+// boundaryResult ubica el resultado en el centroide del polígono
+func boundaryResult(b *domain.ZoneBoundary, confidence float64) *domain.GeocodeResult {
+	if b == nil {
+		return nil
+	}
+	polygons, err := domain.ParseGeometry(b.Geometry)
+	if err != nil {
+		return nil
+	}
+	b.Polygons = polygons
+	lat, lng := b.Centroid()
+	return &domain.GeocodeResult{Lat: lat, Lng: lng, Confidence: confidence, Source: domain.GeocodeSourceCatalog}
+}
+
+type fallbackGeocoder struct {
+	geocoders []ports.Geocoder
+}
+
+// NewFallbackGeocoder prueba los geocodificadores en orden y retorna el primer resultado
+func NewFallbackGeocoder(geocoders ...ports.Geocoder) ports.Geocoder {
+	return &fallbackGeocoder{geocoders: geocoders}
+}
+
+func (g *fallbackGeocoder) Geocode(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+	err := domain.ErrAddressNotFound
+	for _, geocoder := range g.geocoders {
+		var result *domain.GeocodeResult
+		result, err = geocoder.Geocode(ctx, query)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, domain.ErrAddressNotFound) {
+			slog.Warn("Geocoder failed, trying next", "error", err)
+		}
+	}
+	return nil, err
+}
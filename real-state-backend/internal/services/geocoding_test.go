@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/repository"
+)
+
+// geocoderFunc adapta una función a ports.Geocoder
+type geocoderFunc func(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error)
+
+func (f geocoderFunc) Geocode(ctx context.Context, query domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+	return f(ctx, query)
+}
+
+// newGeocoderStub levanta un /search compatible con Nominatim que responde status y body
+func newGeocoderStub(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var catalogResult = &domain.GeocodeResult{Lat: 14.6, Lng: -90.5, Confidence: catalogZoneConfidence, Source: domain.GeocodeSourceCatalog}
+
+func TestFallbackGeocoder(t *testing.T) {
+	catalog := geocoderFunc(func(context.Context, domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+		return catalogResult, nil
+	})
+	query := domain.GeocodeQuery{Address: "5a Avenida 10-20", City: "Guatemala"}
+
+	tests := map[string]struct {
+		status     int
+		body       string
+		wantSource string
+	}{
+		"provider result":    {http.StatusOK, `[{"lat":"14.61","lon":"-90.51","place_rank":26}]`, domain.GeocodeSourceNominatim},
+		"provider not found": {http.StatusOK, `[]`, domain.GeocodeSourceCatalog},
+		"provider down":      {http.StatusServiceUnavailable, `simulated failure`, domain.GeocodeSourceCatalog},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newGeocoderStub(t, tt.status, tt.body)
+			geocoder := NewFallbackGeocoder(repository.NewNominatimGeocoder(server.URL, "test"), catalog)
+			result, err := geocoder.Geocode(context.Background(), query)
+			if err != nil {
+				t.Fatalf("Geocode: %v", err)
+			}
+			if result.Source != tt.wantSource {
+				t.Errorf("source = %q, want %q", result.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestFallbackGeocoderReturnsLastError(t *testing.T) {
+	notFound := geocoderFunc(func(context.Context, domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+		return nil, domain.ErrAddressNotFound
+	})
+	server := newGeocoderStub(t, http.StatusOK, `[]`)
+	geocoder := NewFallbackGeocoder(repository.NewNominatimGeocoder(server.URL, "test"), notFound)
+	if _, err := geocoder.Geocode(context.Background(), domain.GeocodeQuery{Address: "sin resultado"}); !errors.Is(err, domain.ErrAddressNotFound) {
+		t.Errorf("error = %v, want ErrAddressNotFound", err)
+	}
+
+	failing := geocoderFunc(func(context.Context, domain.GeocodeQuery) (*domain.GeocodeResult, error) {
+		return nil, errors.New("catalog unavailable")
+	})
+	geocoder = NewFallbackGeocoder(notFound, failing)
+	if _, err := geocoder.Geocode(context.Background(), domain.GeocodeQuery{Address: "x"}); err == nil || errors.Is(err, domain.ErrAddressNotFound) {
+		t.Errorf("error = %v, want the last geocoder error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type marketStatsService struct {
+	repo     ports.MarketStatsRepository
+	rateRepo ports.ExchangeRateRepository
+}
+
+// NewMarketStatsService crea el servicio y refresca la vista materializada cada
+// refreshInterval en segundo plano, para que las consultas no recorran la tabla.
+func NewMarketStatsService(ctx context.Context, repo ports.MarketStatsRepository, rateRepo ports.ExchangeRateRepository, refreshInterval time.Duration) ports.MarketStatsService {
+	s := &marketStatsService{
+		repo:     repo,
+		rateRepo: rateRepo,
+	}
+	if refreshInterval > 0 {
+		go s.refreshLoop(ctx, refreshInterval)
+	}
+	return s
+}
+
+// GetMonthly retorna las estadísticas convertidas a la moneda solicitada
+func (s *marketStatsService) GetMonthly(ctx context.Context, filter domain.MarketStatsFilter, currency string) ([]domain.MarketStat, error) {
+	if filter.To.Before(filter.From) {
+		return nil, errors.New("invalid date range")
+	}
+
+	stats, err := s.repo.GetMonthly(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" || currency == "USD" {
+		return stats, nil
+	}
+
+	rates, err := s.rateRepo.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		for _, v := range []*float64{stats[i].MedianPrice, stats[i].MedianPricePerSqM} {
+			if v == nil {
+				continue
+			}
+			converted, err := convertCurrency(rates, *v, "USD", currency)
+			if err != nil {
+				return nil, err
+			}
+			*v = round2(converted)
+		}
+		stats[i].Currency = currency
+	}
+	return stats, nil
+}
+
+func (s *marketStatsService) Refresh(ctx context.Context) error {
+	return s.repo.Refresh(ctx)
+}
+
+// refreshLoop refresca la vista materializada hasta que se cancele el contexto
+func (s *marketStatsService) refreshLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+			if err := s.repo.Refresh(refreshCtx); err != nil {
+				slog.Warn("Failed to refresh market stats", "error", err)
+			}
+			cancel()
+		}
+	}
+}
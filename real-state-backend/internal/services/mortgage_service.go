@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// defaultMaxPaymentToIncome es la relación cuota/ingreso máxima sin producto seleccionado
+const defaultMaxPaymentToIncome = 0.35
+
+// ErrInvalidMortgageInput agrupa los errores de parámetros del cálculo hipotecario
+var ErrInvalidMortgageInput = errors.New("invalid mortgage input")
+
+type mortgageService struct {
+	productRepo  ports.MortgageProductRepository
+	propertyRepo ports.PropertyRepository
+	rateRepo     ports.ExchangeRateRepository
+}
+
+func NewMortgageService(productRepo ports.MortgageProductRepository, propertyRepo ports.PropertyRepository, rateRepo ports.ExchangeRateRepository) ports.MortgageService {
+	return &mortgageService{
+		productRepo:  productRepo,
+		propertyRepo: propertyRepo,
+		rateRepo:     rateRepo,
+	}
+}
+
+// Calculate genera la cuota, el plan de amortización (sistema francés) y la
+// verificación de capacidad de pago. Los valores del producto seleccionado se
+// usan como defaults y como límites (plazo máximo y enganche mínimo).
+func (s *mortgageService) Calculate(ctx context.Context, input domain.MortgageInput) (*domain.MortgageQuote, error) {
+	quote := &domain.MortgageQuote{
+		PropertyID: input.PropertyID,
+		Currency:   input.Currency,
+		Price:      input.Price,
+		TermYears:  input.TermYears,
+	}
+	maxRatio := defaultMaxPaymentToIncome
+
+	if input.ProductID != 0 {
+		product, err := s.productRepo.GetByID(ctx, input.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if !product.Active {
+			return nil, fmt.Errorf("%w: mortgage product is not active", ErrInvalidMortgageInput)
+		}
+		quote.Product = product
+		if quote.Currency == "" {
+			quote.Currency = product.Currency
+		}
+		if quote.Currency != product.Currency {
+			return nil, fmt.Errorf("%w: product currency is %s", ErrInvalidMortgageInput, product.Currency)
+		}
+		if input.AnnualRate == nil {
+			input.AnnualRate = &product.AnnualRate
+		}
+		if quote.TermYears == 0 {
+			quote.TermYears = product.MaxTermYears
+		}
+		if quote.TermYears > product.MaxTermYears {
+			return nil, fmt.Errorf("%w: term exceeds product maximum of %d years", ErrInvalidMortgageInput, product.MaxTermYears)
+		}
+		maxRatio = product.MaxPaymentToIncome
+	}
+
+	// El precio de la propiedad se convierte a la moneda del cálculo
+	if input.PropertyID != 0 {
+		property, err := s.propertyRepo.GetByID(ctx, input.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		if quote.Currency == "" {
+			quote.Currency = property.Currency
+		}
+		rates, err := s.rateRepo.GetRates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		price, err := convertCurrency(rates, property.Price, property.Currency, quote.Currency)
+		if err != nil {
+			return nil, err
+		}
+		quote.Price = round2(price)
+	}
+
+	if quote.Currency == "" {
+		quote.Currency = "USD"
+	}
+	if quote.Price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive", ErrInvalidMortgageInput)
+	}
+	if input.DownPayment < 0 || input.DownPayment >= quote.Price {
+		return nil, fmt.Errorf("%w: down payment must be between 0 and the price", ErrInvalidMortgageInput)
+	}
+	if quote.Product != nil && input.DownPayment < quote.Price*quote.Product.MinDownPaymentPct {
+		return nil, fmt.Errorf("%w: down payment must be at least %.0f%% of the price", ErrInvalidMortgageInput, quote.Product.MinDownPaymentPct*100)
+	}
+	if input.AnnualRate == nil || *input.AnnualRate < 0 || *input.AnnualRate >= 1 {
+		return nil, fmt.Errorf("%w: annual rate is required (0.085 = 8.5%%)", ErrInvalidMortgageInput)
+	}
+	if quote.TermYears < 1 || quote.TermYears > 40 {
+		return nil, fmt.Errorf("%w: term must be between 1 and 40 years", ErrInvalidMortgageInput)
+	}
+
+	quote.DownPayment = input.DownPayment
+	quote.AnnualRate = *input.AnnualRate
+	quote.Principal = round2(quote.Price - input.DownPayment)
+
+	months := quote.TermYears * 12
+	monthlyRate := quote.AnnualRate / 12
+	payment := monthlyPayment(quote.Principal, monthlyRate, months)
+	quote.MonthlyPayment = round2(payment)
+	quote.Schedule = amortizationSchedule(quote.Principal, monthlyRate, months, quote.MonthlyPayment)
+
+	totalPaid := 0.0
+	for _, row := range quote.Schedule {
+		totalPaid += row.Payment
+	}
+	quote.TotalPaid = round2(totalPaid)
+	quote.TotalInterest = round2(totalPaid - quote.Principal)
+
+	if input.MonthlyIncome > 0 {
+		ratio := (quote.MonthlyPayment + input.MonthlyDebts) / input.MonthlyIncome
+		available := maxRatio*input.MonthlyIncome - input.MonthlyDebts
+		maxLoan := 0.0
+		if available > 0 {
+			maxLoan = presentValue(available, monthlyRate, months)
+		}
+		quote.Affordability = &domain.AffordabilityCheck{
+			MonthlyIncome:      input.MonthlyIncome,
+			MonthlyDebts:       input.MonthlyDebts,
+			PaymentToIncome:    math.Round(ratio*10000) / 10000,
+			MaxPaymentToIncome: maxRatio,
+			Affordable:         ratio <= maxRatio,
+			MaxAffordableLoan:  round2(maxLoan),
+		}
+	}
+
+	return quote, nil
+}
+
+func (s *mortgageService) ListProducts(ctx context.Context, activeOnly bool) ([]domain.MortgageProduct, error) {
+	return s.productRepo.List(ctx, activeOnly)
+}
+
+func (s *mortgageService) CreateProduct(ctx context.Context, product *domain.MortgageProduct) error {
+	return s.productRepo.Create(ctx, product)
+}
+
+func (s *mortgageService) UpdateProduct(ctx context.Context, product *domain.MortgageProduct) error {
+	return s.productRepo.Update(ctx, product)
+}
+
+func (s *mortgageService) DeleteProduct(ctx context.Context, id int64) error {
+	return s.productRepo.Delete(ctx, id)
+}
+
+// monthlyPayment calcula la cuota nivelada de un préstamo
+func monthlyPayment(principal, monthlyRate float64, months int) float64 {
+	if monthlyRate == 0 {
+		return principal / float64(months)
+	}
+	return principal * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(months)))
+}
+
+// presentValue calcula el monto de préstamo que se paga con la cuota indicada
+func presentValue(payment, monthlyRate float64, months int) float64 {
+	if monthlyRate == 0 {
+		return payment * float64(months)
+	}
+	return payment * (1 - math.Pow(1+monthlyRate, -float64(months))) / monthlyRate
+}
+
+// amortizationSchedule genera el plan de pagos; la última cuota absorbe el redondeo
+func amortizationSchedule(principal, monthlyRate float64, months int, payment float64) []domain.AmortizationRow {
+	schedule := make([]domain.AmortizationRow, 0, months)
+	balance := principal
+	for n := 1; n <= months; n++ {
+		interest := round2(balance * monthlyRate)
+		amortization := round2(payment - interest)
+		if n == months || amortization > balance {
+			amortization = balance
+		}
+		balance = round2(balance - amortization)
+		schedule = append(schedule, domain.AmortizationRow{
+			Number:    n,
+			Payment:   round2(amortization + interest),
+			Principal: amortization,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return schedule
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// notificationBatchSize limita los correos enviados por ciclo; se envían uno tras otro
+const notificationBatchSize = 20
+
+type notificationService struct {
+	repo       ports.NotificationRepository
+	users      ports.UserRepository
+	properties ports.PropertyRepository
+	sender     ports.EmailSender
+	lease      time.Duration
+	wake       chan struct{}
+
+	mu     sync.RWMutex
+	onSend map[string]ports.NotificationDataFunc // Tipos que se renderizan al enviar
+}
+
+// NewNotificationService crea el servicio y envía los correos pendientes cada interval
+// en segundo plano. Sin sender los correos quedan en cola hasta configurar uno.
+func NewNotificationService(ctx context.Context, repo ports.NotificationRepository, users ports.UserRepository, properties ports.PropertyRepository, sender ports.EmailSender, timeout, interval time.Duration) ports.NotificationService {
+	s := &notificationService{
+		repo:       repo,
+		users:      users,
+		properties: properties,
+		sender:     sender,
+		lease:      notificationBatchSize*timeout + time.Minute, // Cubre un lote completo
+		wake:       make(chan struct{}, 1),
+		onSend:     map[string]ports.NotificationDataFunc{},
+	}
+	if sender != nil && interval > 0 {
+		go s.dispatchLoop(ctx, interval, timeout)
+	}
+	return s
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID, kind string, data map[string]interface{}, dedupeKey string) error {
+	_, err := s.enqueue(ctx, userID, kind, data, dedupeKey)
+	return err
+}
+
+func (s *notificationService) RenderOnSend(kind string, fn ports.NotificationDataFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onSend[kind] = fn
+}
+
+func (s *notificationService) dataOnSend(kind string) ports.NotificationDataFunc {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.onSend[kind]
+}
+
+// enqueue renderiza la notificación en el idioma del usuario y la encola. Retorna nil
+// sin error si el usuario no la recibe (preferencias o sin correo).
+func (s *notificationService) enqueue(ctx context.Context, userID, kind string, data map[string]interface{}, dedupeKey string) (*domain.Notification, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !prefs.Allows(kind) {
+		return nil, nil
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == "" {
+		slog.Warn("Skipping notification for user without email", "user_id", userID, "kind", kind)
+		return nil, nil
+	}
+
+	values := map[string]interface{}{"Username": user.Username}
+	for k, v := range data {
+		values[k] = v
+	}
+	subject, text, html, err := renderNotification(kind, prefs.Language, values)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if s.dataOnSend(kind) != nil {
+		text, html = "", "" // El cuerpo se arma al enviar
+	}
+	n := &domain.Notification{
+		UserID:        userID,
+		Kind:          kind,
+		Language:      prefs.Language,
+		Recipient:     user.Email,
+		Subject:       subject,
+		TextBody:      text,
+		HTMLBody:      html,
+		Status:        domain.NotificationPending,
+		NextAttemptAt: &now,
+		DedupeKey:     dedupeKey,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.repo.Enqueue(ctx, n); err != nil {
+		return nil, err
+	}
+	if n.ID != 0 {
+		s.notify()
+	}
+	return n, nil
+}
+
+// HandleLeadCreated avisa al agente responsable; la clave del evento evita duplicar el
+// aviso si el outbox lo entrega más de una vez
+func (s *notificationService) HandleLeadCreated(ctx context.Context, e *domain.OutboxEvent) error {
+	var lead domain.PropertyEvent
+	if err := json.Unmarshal(e.Payload, &lead); err != nil {
+		slog.Error("Discarding malformed lead event", "event_id", e.EventID, "error", err)
+		return nil
+	}
+	property, err := s.properties.GetByID(ctx, lead.PropertyID)
+	if err != nil {
+		return err
+	}
+	// Sin agente asignado o consulta del propio agente: no hay a quién avisar
+	if property.AgentID == nil || (lead.UserID != nil && *lead.UserID == *property.AgentID) {
+		return nil
+	}
+	data := map[string]interface{}{
+		"PropertyID":    strconv.FormatInt(property.ID, 10),
+		"PropertyTitle": property.Title,
+		"Message":       lead.Metadata["message"],
+		"Contact":       lead.Metadata["contact"],
+	}
+	return s.Notify(ctx, *property.AgentID, domain.NotificationLeadAlert, data, domain.NotificationLeadAlert+":"+e.EventID)
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context) (*domain.NotificationPreferences, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	return s.repo.GetPreferences(ctx, userID)
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, changes domain.NotificationPreferencesChanges) (*domain.NotificationPreferences, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Language != nil {
+		prefs.Language = *changes.Language
+	}
+	if changes.EmailEnabled != nil {
+		prefs.EmailEnabled = *changes.EmailEnabled
+	}
+	if changes.MutedKinds != nil {
+		prefs.MutedKinds = changes.MutedKinds
+	}
+	prefs.UpdatedAt = time.Now().UTC()
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) List(ctx context.Context, limit int) ([]domain.Notification, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.repo.ListByUser(ctx, userID, limit)
+}
+
+func (s *notificationService) SendTest(ctx context.Context) (*domain.Notification, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	n, err := s.enqueue(ctx, userID, domain.NotificationTest, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, domain.ErrNotificationRecipientMissing
+	}
+	return n, nil
+}
+
+// notify adelanta el siguiente ciclo del despachador sin bloquear
+func (s *notificationService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchLoop envía los correos vencidos hasta que se cancele el contexto
+func (s *notificationService) dispatchLoop(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.dispatchDue(ctx, timeout)
+	}
+}
+
+// dispatchDue envía lotes hasta vaciar los correos vencidos
+func (s *notificationService) dispatchDue(ctx context.Context, timeout time.Duration) {
+	for ctx.Err() == nil {
+		notifications, err := s.repo.ClaimDue(ctx, time.Now().UTC(), s.lease, notificationBatchSize)
+		if err != nil {
+			slog.Warn("Failed to claim notifications", "error", err)
+			return
+		}
+		for i := range notifications {
+			s.send(ctx, &notifications[i], timeout)
+		}
+		if len(notifications) < notificationBatchSize {
+			return
+		}
+	}
+}
+
+func (s *notificationService) send(ctx context.Context, n *domain.Notification, timeout time.Duration) {
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	err := s.renderOnSend(sendCtx, n)
+	if err == nil {
+		err = s.sender.Send(sendCtx, n.Message())
+	}
+	cancel()
+	if ctx.Err() != nil {
+		return // El lease vence y el correo se retoma al reiniciar
+	}
+	if errors.Is(err, domain.ErrNotificationExpired) {
+		n.Discard(err.Error(), time.Now().UTC())
+		if err := s.repo.RecordAttempt(ctx, n); err != nil {
+			slog.Error("Failed to record notification attempt", "notification_id", n.ID, "error", err)
+		}
+		return
+	}
+
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	n.RecordAttempt(errMsg, time.Now().UTC())
+	if err := s.repo.RecordAttempt(ctx, n); err != nil {
+		slog.Error("Failed to record notification attempt", "notification_id", n.ID, "error", err)
+		return
+	}
+	switch n.Status {
+	case domain.NotificationFailed:
+		slog.Warn("Notification failed permanently", "notification_id", n.ID, "kind", n.Kind,
+			"attempts", n.Attempts, "error", n.LastError)
+	case domain.NotificationPending:
+		slog.Info("Notification send failed, will retry", "notification_id", n.ID, "attempts", n.Attempts, "error", n.LastError)
+	}
+}
+
+// renderOnSend arma el cuerpo de los tipos registrados con RenderOnSend; solo queda en
+// memoria, RecordAttempt no lo guarda
+func (s *notificationService) renderOnSend(ctx context.Context, n *domain.Notification) error {
+	if n.TextBody != "" || n.HTMLBody != "" {
+		return nil
+	}
+	fn := s.dataOnSend(n.Kind)
+	if fn == nil {
+		return errors.New("notification has no body and no renderer for its kind")
+	}
+	data, err := fn(ctx, n)
+	if err != nil {
+		return err
+	}
+	user, err := s.users.GetByID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{"Username": user.Username}
+	for k, v := range data {
+		values[k] = v
+	}
+	n.Subject, n.TextBody, n.HTMLBody, err = renderNotification(n.Kind, n.Language, values)
+	return err
+}
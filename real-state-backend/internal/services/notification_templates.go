@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// Plantillas por idioma en templates/notifications/<idioma>/: <tipo>.subject.tmpl y
+// <tipo>.txt.tmpl (texto) y <tipo>.html.tmpl, que usa header/footer de layout.html.tmpl
+//
+//go:embed templates/notifications
+var notificationTemplateFS embed.FS
+
+type notificationTemplateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// notificationTemplates se cargan al iniciar: una plantilla inválida detiene el arranque
+var notificationTemplates = mustLoadNotificationTemplates()
+
+func mustLoadNotificationTemplates() map[string]notificationTemplateSet {
+	sets := make(map[string]notificationTemplateSet, len(domain.SupportedLanguages))
+	for _, lang := range domain.SupportedLanguages {
+		dir := "templates/notifications/" + lang
+		sets[lang] = notificationTemplateSet{
+			text: texttemplate.Must(texttemplate.ParseFS(notificationTemplateFS, dir+"/*.subject.tmpl", dir+"/*.txt.tmpl")),
+			html: htmltemplate.Must(htmltemplate.ParseFS(notificationTemplateFS, dir+"/*.html.tmpl")),
+		}
+	}
+	return sets
+}
+
+// renderNotification retorna asunto, texto y HTML del tipo en el idioma; sin plantilla
+// en ese idioma se usa DefaultLanguage. data recibe además Subject para el HTML.
+func renderNotification(kind, language string, data map[string]interface{}) (subject, text, html string, err error) {
+	set, ok := notificationTemplates[language]
+	if !ok || set.text.Lookup(kind+".subject.tmpl") == nil {
+		set = notificationTemplates[domain.DefaultLanguage]
+	}
+	if set.text.Lookup(kind+".subject.tmpl") == nil {
+		return "", "", "", fmt.Errorf("no templates for notification kind %q", kind)
+	}
+
+	var buf bytes.Buffer
+	if err := set.text.ExecuteTemplate(&buf, kind+".subject.tmpl", data); err != nil {
+		return "", "", "", err
+	}
+	// El asunto es una sola línea
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+	data["Subject"] = subject
+
+	buf.Reset()
+	if err := set.text.ExecuteTemplate(&buf, kind+".txt.tmpl", data); err != nil {
+		return "", "", "", err
+	}
+	text = buf.String()
+
+	buf.Reset()
+	if err := set.html.ExecuteTemplate(&buf, kind+".html.tmpl", data); err != nil {
+		return "", "", "", err
+	}
+	return subject, text, buf.String(), nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"real-state-backend/internal/core/domain"
+)
+
+// notificationTestData tiene los valores que usan las plantillas de cada tipo
+var notificationTestData = map[string]map[string]interface{}{
+	domain.NotificationLeadAlert: {
+		"PropertyID": "7", "PropertyTitle": "Casa en Antigua", "Contact": "ana@example.com", "Message": "¿Sigue disponible?",
+	},
+	domain.NotificationSavedSearchMatch: {
+		"SearchName": "Zona 10", "PropertyID": "7", "PropertyTitle": "Casa en Antigua",
+		"Price": "250000.00", "Currency": "USD", "City": "Antigua Guatemala",
+	},
+	domain.NotificationPasswordReset: {
+		"ResetURL": "https://app.example.com/reset?token=abc", "ExpiresMinutes": 30,
+	},
+	domain.NotificationTest: {},
+}
+
+// notificationTestSubjects son los asuntos esperados por idioma y tipo
+var notificationTestSubjects = map[string]map[string]string{
+	"es": {
+		domain.NotificationLeadAlert:        "Nueva consulta: Casa en Antigua",
+		domain.NotificationSavedSearchMatch: "Nueva propiedad para tu búsqueda: Casa en Antigua",
+		domain.NotificationPasswordReset:    "Recupera tu contraseña",
+		domain.NotificationTest:             "Correo de prueba",
+	},
+	"en": {
+		domain.NotificationLeadAlert: "New inquiry: Casa en Antigua",
+		domain.NotificationTest:      "Test email",
+	},
+}
+
+func renderTestNotification(t *testing.T, kind, language string, extra map[string]interface{}) (string, string, string) {
+	t.Helper()
+	data := map[string]interface{}{"Username": "ana"}
+	for k, v := range notificationTestData[kind] {
+		data[k] = v
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	subject, text, html, err := renderNotification(kind, language, data)
+	if err != nil {
+		t.Fatalf("renderNotification(%s, %s): %v", kind, language, err)
+	}
+	return subject, text, html
+}
+
+func TestRenderNotificationEveryLanguage(t *testing.T) {
+	kinds := []string{domain.NotificationLeadAlert, domain.NotificationSavedSearchMatch, domain.NotificationPasswordReset, domain.NotificationTest}
+	greetings := map[string]string{"es": "Hola ana,", "en": "Hi ana,"}
+	for _, lang := range domain.SupportedLanguages {
+		for _, kind := range kinds {
+			t.Run(lang+"/"+kind, func(t *testing.T) {
+				subject, text, html := renderTestNotification(t, kind, lang, nil)
+				if want, ok := notificationTestSubjects[lang][kind]; ok && subject != want {
+					t.Errorf("subject = %q, want %q", subject, want)
+				}
+				if subject == "" || strings.Contains(subject, "<no value>") {
+					t.Errorf("subject = %q", subject)
+				}
+				if !strings.HasPrefix(text, greetings[lang]) {
+					t.Errorf("text does not start with %q:\n%s", greetings[lang], text)
+				}
+				if !strings.Contains(html, `<html lang="`+lang+`">`) || !strings.Contains(html, "<title>"+subject+"</title>") {
+					t.Errorf("html layout missing language or subject:\n%s", html)
+				}
+				for _, body := range []string{text, html} {
+					if strings.Contains(body, "<no value>") {
+						t.Errorf("missing template value:\n%s", body)
+					}
+					for _, v := range notificationTestData[kind] {
+						if s, ok := v.(string); ok && !strings.Contains(body, s) {
+							t.Errorf("body does not contain %q:\n%s", s, body)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenderNotificationFallsBackToDefaultLanguage(t *testing.T) {
+	subject, _, html := renderTestNotification(t, domain.NotificationTest, "fr", nil)
+	if subject != notificationTestSubjects[domain.DefaultLanguage][domain.NotificationTest] {
+		t.Errorf("subject = %q", subject)
+	}
+	if !strings.Contains(html, `<html lang="`+domain.DefaultLanguage+`">`) {
+		t.Errorf("html is not in the default language:\n%s", html)
+	}
+}
+
+func TestRenderNotificationEscapesAndFoldsSubject(t *testing.T) {
+	subject, text, html := renderTestNotification(t, domain.NotificationLeadAlert, "es", map[string]interface{}{
+		"PropertyTitle": "Casa\r\nBcc: otro@example.com",
+		"Message":       `<script>alert("x")</script>`,
+	})
+	if strings.ContainsAny(subject, "\r\n") || subject != "Nueva consulta: Casa Bcc: otro@example.com" {
+		t.Errorf("subject = %q", subject)
+	}
+	if strings.Contains(html, "<script>") || !strings.Contains(html, "&lt;script&gt;") {
+		t.Errorf("html does not escape the message:\n%s", html)
+	}
+	if !strings.Contains(text, `<script>alert("x")</script>`) {
+		t.Errorf("text altered the message:\n%s", text)
+	}
+}
+
+func TestRenderNotificationUnknownKind(t *testing.T) {
+	if _, _, _, err := renderNotification("unknown", "es", map[string]interface{}{}); err == nil {
+		t.Error("expected an error for a kind without templates")
+	}
+}
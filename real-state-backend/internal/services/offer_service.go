@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// permissionManageOffers permite negociar por el vendedor en propiedades de otros agentes
+const permissionManageOffers = "manage_offers"
+
+type offerService struct {
+	repo       ports.OfferRepository
+	properties ports.PropertyRepository
+	revisions  ports.PropertyRevisionRepository
+	auth       ports.AuthService
+	tx         ports.TxManager
+	outbox     ports.OutboxRepository
+}
+
+// NewOfferService crea el servicio y vence las ofertas cada expireInterval en segundo plano.
+// La reserva de la propiedad al aceptar una oferta se registra en outbox en la misma transacción.
+func NewOfferService(ctx context.Context, repo ports.OfferRepository, properties ports.PropertyRepository, revisions ports.PropertyRevisionRepository, auth ports.AuthService, tx ports.TxManager, outbox ports.OutboxRepository, expireInterval time.Duration) ports.OfferService {
+	s := &offerService{
+		repo:       repo,
+		properties: properties,
+		revisions:  revisions,
+		auth:       auth,
+		tx:         tx,
+		outbox:     outbox,
+	}
+	if expireInterval > 0 {
+		go s.expireLoop(ctx, expireInterval)
+	}
+	return s
+}
+
+// Submit registra una oferta del usuario sobre una propiedad publicada
+func (s *offerService) Submit(ctx context.Context, propertyID int64, terms domain.OfferTerms, message string) (*domain.Offer, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return nil, domain.ErrOfferAccessDenied
+	}
+	// Las fechas se guardan en UTC (columnas TIMESTAMP sin zona)
+	terms.ExpiresAt = terms.ExpiresAt.UTC()
+	if err := validateOfferTerms(terms, time.Now()); err != nil {
+		return nil, err
+	}
+	property, err := s.loadProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if property.Status != domain.PropertyStatusPublished {
+		return nil, domain.ErrPropertyNotAvailable
+	}
+	if property.AgentID != nil && *property.AgentID == userID {
+		return nil, fmt.Errorf("%w: the listing agent cannot make offers on the property", domain.ErrInvalidOffer)
+	}
+
+	offer := &domain.Offer{
+		PropertyID:    propertyID,
+		BuyerID:       &userID,
+		OfferTerms:    terms,
+		Status:        domain.OfferStatusSubmitted,
+		AwaitingParty: domain.OfferPartySeller,
+	}
+	event := &domain.OfferEvent{
+		AuthorID: &userID,
+		Party:    domain.OfferPartyBuyer,
+		Kind:     domain.OfferStatusSubmitted,
+		Terms:    &terms,
+		Message:  strings.TrimSpace(message),
+	}
+	if err := s.repo.Create(ctx, offer, event); err != nil {
+		return nil, err
+	}
+	offer.Events = []domain.OfferEvent{*event}
+	return offer, nil
+}
+
+// List retorna todas las ofertas al vendedor y solo las propias a los compradores
+func (s *offerService) List(ctx context.Context, propertyID int64) ([]domain.Offer, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return nil, domain.ErrOfferAccessDenied
+	}
+	property, err := s.loadProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	seller, err := s.isSeller(ctx, property, userID)
+	if err != nil {
+		return nil, err
+	}
+	if seller {
+		return s.repo.List(ctx, propertyID, "")
+	}
+	return s.repo.List(ctx, propertyID, userID)
+}
+
+func (s *offerService) Get(ctx context.Context, propertyID, id int64) (*domain.Offer, error) {
+	offer, _, err := s.load(ctx, propertyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Events, err = s.repo.ListEvents(ctx, offer.ID); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// Counter reemplaza los términos vigentes y pasa el turno a la otra parte
+func (s *offerService) Counter(ctx context.Context, propertyID, id int64, terms domain.OfferTerms, message string) (*domain.Offer, error) {
+	// Las fechas se guardan en UTC (columnas TIMESTAMP sin zona)
+	terms.ExpiresAt = terms.ExpiresAt.UTC()
+	if err := validateOfferTerms(terms, time.Now()); err != nil {
+		return nil, err
+	}
+	offer, party, err := s.loadOpen(ctx, propertyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !offer.CanTransitionTo(domain.OfferStatusCountered, party) {
+		return nil, domain.ErrInvalidOfferTransition
+	}
+
+	fromStatus := offer.Status
+	offer.OfferTerms = terms
+	offer.Status = domain.OfferStatusCountered
+	offer.AwaitingParty = domain.Counterparty(party)
+	event := s.newEvent(ctx, offer, party, domain.OfferStatusCountered, message)
+	event.Terms = &terms
+	if err := s.repo.Transition(ctx, offer, fromStatus, event); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// Respond acepta, rechaza o retira la oferta; aceptar reserva la propiedad
+func (s *offerService) Respond(ctx context.Context, propertyID, id int64, status, message string) (*domain.Offer, error) {
+	if !slices.Contains([]string{domain.OfferStatusAccepted, domain.OfferStatusRejected, domain.OfferStatusWithdrawn}, status) {
+		return nil, domain.ErrInvalidOfferTransition
+	}
+	offer, party, err := s.loadOpen(ctx, propertyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !offer.CanTransitionTo(status, party) {
+		return nil, domain.ErrInvalidOfferTransition
+	}
+
+	fromStatus := offer.Status
+	offer.Status = status
+	offer.AwaitingParty = ""
+	event := s.newEvent(ctx, offer, party, status, message)
+	if status != domain.OfferStatusAccepted {
+		if err := s.repo.Transition(ctx, offer, fromStatus, event); err != nil {
+			return nil, err
+		}
+		return offer, nil
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Accept(ctx, offer, fromStatus, event); err != nil {
+			return err
+		}
+		property, err := s.properties.GetByID(ctx, propertyID)
+		if err != nil {
+			return err
+		}
+		reserved, err := newPropertyEvent(domain.EventPropertyStatusChanged, property, domain.PropertyStatusPublished)
+		if err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, reserved); err != nil {
+			return err
+		}
+		return recordRevision(ctx, s.revisions, property, domain.RevisionStatus, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// AddMessage agrega un mensaje al hilo sin cambiar el estado de la oferta
+func (s *offerService) AddMessage(ctx context.Context, propertyID, id int64, message string) (*domain.OfferEvent, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("%w: message is required", domain.ErrInvalidOffer)
+	}
+	offer, party, err := s.load(ctx, propertyID, id)
+	if err != nil {
+		return nil, err
+	}
+	event := s.newEvent(ctx, offer, party, domain.OfferEventMessage, message)
+	if err := s.repo.AddEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// load retorna la oferta y la parte que representa el usuario
+func (s *offerService) load(ctx context.Context, propertyID, id int64) (*domain.Offer, string, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return nil, "", domain.ErrOfferAccessDenied
+	}
+	offer, err := s.repo.Get(ctx, propertyID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if offer.BuyerID != nil && *offer.BuyerID == userID {
+		return offer, domain.OfferPartyBuyer, nil
+	}
+	property, err := s.loadProperty(ctx, propertyID)
+	if err != nil {
+		return nil, "", err
+	}
+	seller, err := s.isSeller(ctx, property, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !seller {
+		return nil, "", domain.ErrOfferAccessDenied
+	}
+	return offer, domain.OfferPartySeller, nil
+}
+
+// loadOpen como load, pero vence la oferta si su vigencia ya terminó
+func (s *offerService) loadOpen(ctx context.Context, propertyID, id int64) (*domain.Offer, string, error) {
+	offer, party, err := s.load(ctx, propertyID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if offer.IsExpired(time.Now()) {
+		fromStatus := offer.Status
+		offer.Status, offer.AwaitingParty = domain.OfferStatusExpired, ""
+		event := &domain.OfferEvent{OfferID: offer.ID, Party: domain.OfferPartySystem, Kind: domain.OfferStatusExpired}
+		if err := s.repo.Transition(ctx, offer, fromStatus, event); err != nil && !errors.Is(err, domain.ErrInvalidOfferTransition) {
+			slog.Warn("Failed to expire offer", "offer_id", offer.ID, "error", err)
+		}
+		return nil, "", domain.ErrOfferExpired
+	}
+	return offer, party, nil
+}
+
+// loadProperty carga la propiedad de la oferta; solo su ausencia se reporta como
+// ErrOfferNotFound, los errores de base de datos se propagan
+func (s *offerService) loadProperty(ctx context.Context, id int64) (*domain.Property, error) {
+	property, err := s.properties.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrPropertyNotFound) {
+		return nil, fmt.Errorf("%w: %v", domain.ErrOfferNotFound, err)
+	}
+	return property, err
+}
+
+// isSeller indica si el usuario negocia por el vendedor: agente responsable o permiso manage_offers
+func (s *offerService) isSeller(ctx context.Context, property *domain.Property, userID string) (bool, error) {
+	if property.AgentID != nil && *property.AgentID == userID {
+		return true, nil
+	}
+	permissions, err := s.auth.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if p.Name == permissionManageOffers {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *offerService) newEvent(ctx context.Context, offer *domain.Offer, party, kind, message string) *domain.OfferEvent {
+	event := &domain.OfferEvent{OfferID: offer.ID, Party: party, Kind: kind, Message: strings.TrimSpace(message)}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		event.AuthorID = &userID
+	}
+	return event
+}
+
+// validateOfferTerms exige monto positivo, moneda soportada y vencimiento futuro dentro de MaxOfferTTL
+func validateOfferTerms(terms domain.OfferTerms, now time.Time) error {
+	switch {
+	case terms.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", domain.ErrInvalidOffer)
+	case terms.Currency != "USD" && terms.Currency != "GTQ":
+		return fmt.Errorf("%w: invalid currency", domain.ErrInvalidOffer)
+	case !terms.ExpiresAt.After(now) || terms.ExpiresAt.Sub(now) > domain.MaxOfferTTL:
+		return fmt.Errorf("%w: expires_at must be in the future and within %d days", domain.ErrInvalidOffer, int(domain.MaxOfferTTL.Hours()/24))
+	}
+	return nil
+}
+
+// expireLoop vence las ofertas abiertas hasta que se cancele el contexto
+func (s *offerService) expireLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.ExpireDue(ctx, time.Now().UTC())
+			if err != nil {
+				slog.Warn("Failed to expire offers", "error", err)
+			} else if n > 0 {
+				slog.Info("Expired offers", "count", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// permissionManageOrganizations identifica a los administradores de la plataforma
+const permissionManageOrganizations = "manage_organizations"
+
+type organizationService struct {
+	repo ports.OrganizationRepository
+	auth ports.AuthService
+}
+
+func NewOrganizationService(repo ports.OrganizationRepository, auth ports.AuthService) ports.OrganizationService {
+	return &organizationService{repo: repo, auth: auth}
+}
+
+func (s *organizationService) List(ctx context.Context) ([]domain.Organization, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *organizationService) Create(ctx context.Context, org *domain.Organization) error {
+	org.Active = true
+	return s.repo.Create(ctx, org)
+}
+
+// Update renombra o activa/desactiva la agencia. Al desactivarla sus sesiones dejan de validarse.
+func (s *organizationService) Update(ctx context.Context, id string, changes domain.OrganizationChanges) (*domain.Organization, error) {
+	org, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Name != nil {
+		org.Name = *changes.Name
+	}
+	if changes.Active != nil {
+		if !*changes.Active && org.ID == domain.DefaultOrganizationID {
+			return nil, domain.ErrOrganizationProtected
+		}
+		org.Active = *changes.Active
+	}
+	if err := s.repo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationService) CreateAgencyAdmin(ctx context.Context, organizationID string, user *domain.User, password string) error {
+	if _, err := s.repo.Get(ctx, organizationID); err != nil {
+		return err
+	}
+	roles, err := s.repo.ListRoles(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	var roleIDs []string
+	for _, role := range roles {
+		if role.OrganizationID == nil && role.Name == domain.RoleAgencyAdmin {
+			roleIDs = append(roleIDs, role.ID)
+		}
+	}
+	if len(roleIDs) == 0 {
+		return fmt.Errorf("role %s not found", domain.RoleAgencyAdmin)
+	}
+	user.OrganizationID = organizationID
+	return s.createUser(ctx, user, password, roleIDs)
+}
+
+func (s *organizationService) ListUsers(ctx context.Context) ([]domain.User, error) {
+	organizationID, err := currentOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListUsers(ctx, organizationID)
+}
+
+func (s *organizationService) CreateUser(ctx context.Context, user *domain.User, password string, roleIDs []string) error {
+	organizationID, err := currentOrganization(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.checkAssignable(ctx, organizationID, roleIDs); err != nil {
+		return err
+	}
+	user.OrganizationID = organizationID
+	return s.createUser(ctx, user, password, roleIDs)
+}
+
+// SetUserRoles reemplaza los roles de un usuario de la agencia. Un administrador de
+// agencia no puede quitar ni otorgar roles de plataforma.
+func (s *organizationService) SetUserRoles(ctx context.Context, userID string, roleIDs []string) (*domain.User, error) {
+	organizationID, err := currentOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.GetUser(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	platform, err := s.isPlatformAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range target.Roles {
+		if role.Platform && !platform {
+			return nil, fmt.Errorf("%w: user has platform role %s", domain.ErrRoleNotAssignable, role.Name)
+		}
+	}
+	if err := s.checkAssignable(ctx, organizationID, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetUserRoles(ctx, organizationID, userID, roleIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUser(ctx, organizationID, userID)
+}
+
+func (s *organizationService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	organizationID, err := currentOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListRoles(ctx, organizationID)
+}
+
+// CreateRole registra un rol propio de la agencia; nunca otorga permisos de plataforma
+func (s *organizationService) CreateRole(ctx context.Context, role *domain.Role) error {
+	organizationID, err := currentOrganization(ctx)
+	if err != nil {
+		return err
+	}
+	role.OrganizationID = &organizationID
+	return s.repo.CreateRole(ctx, role)
+}
+
+func (s *organizationService) createUser(ctx context.Context, user *domain.User, password string, roleIDs []string) error {
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	return s.repo.CreateUser(ctx, user, roleIDs)
+}
+
+// checkAssignable exige roles globales o de la misma agencia; los de plataforma solo
+// los otorga un administrador de la plataforma
+func (s *organizationService) checkAssignable(ctx context.Context, organizationID string, roleIDs []string) error {
+	roles, err := s.repo.ListRoles(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	available := make(map[string]domain.Role, len(roles))
+	for _, role := range roles {
+		available[role.ID] = role
+	}
+	platform, err := s.isPlatformAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range roleIDs {
+		role, ok := available[id]
+		if !ok {
+			return fmt.Errorf("%w: role %s", domain.ErrRoleNotAssignable, id)
+		}
+		if role.Platform && !platform {
+			return fmt.Errorf("%w: role %s grants platform permissions", domain.ErrRoleNotAssignable, role.Name)
+		}
+	}
+	return nil
+}
+
+func (s *organizationService) isPlatformAdmin(ctx context.Context) (bool, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return false, nil
+	}
+	permissions, err := s.auth.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if p.Name == permissionManageOrganizations {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// currentOrganization retorna la agencia del usuario autenticado (el alcance de
+// plataforma no es una agencia)
+func currentOrganization(ctx context.Context) (string, error) {
+	organizationID, _ := ctx.Value("organization_id").(string)
+	if organizationID == "" || organizationID == domain.PlatformScope {
+		return "", domain.ErrTenantRequired
+	}
+	return organizationID, nil
+}
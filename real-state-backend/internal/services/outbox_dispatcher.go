@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+const (
+	// outboxBatchSize limita los eventos tomados por ciclo
+	outboxBatchSize = 50
+	// outboxHandlerTimeout limita cada suscriptor
+	outboxHandlerTimeout = 30 * time.Second
+	// outboxCleanupInterval es cada cuánto se purgan los eventos publicados
+	outboxCleanupInterval = time.Hour
+)
+
+type outboxDispatcher struct {
+	repo      ports.OutboxRepository
+	handlers  map[string][]ports.EventHandler
+	retention time.Duration
+	lease     time.Duration
+}
+
+// StartOutboxDispatcher entrega en segundo plano, cada interval, los eventos del
+// outbox a los suscriptores de su tipo, en orden de registro. Un evento se marca
+// publicado cuando todos sus suscriptores terminan sin error; si alguno falla se
+// reintenta completo con espera exponencial (al menos una vez). Los eventos
+// publicados se eliminan después de retention.
+func StartOutboxDispatcher(ctx context.Context, repo ports.OutboxRepository, handlers map[string][]ports.EventHandler, interval, retention time.Duration) {
+	d := &outboxDispatcher{repo: repo, handlers: handlers, retention: retention, lease: outboxLease(handlers)}
+	go d.run(ctx, interval)
+}
+
+// outboxLease cubre un lote completo en el peor caso: cada evento agota el timeout
+// de todos los suscriptores de su tipo, uno tras otro
+func outboxLease(handlers map[string][]ports.EventHandler) time.Duration {
+	perEvent := 1
+	for _, hs := range handlers {
+		perEvent = max(perEvent, len(hs))
+	}
+	return time.Duration(outboxBatchSize*perEvent)*outboxHandlerTimeout + time.Minute
+}
+
+// run despacha los eventos vencidos hasta que se cancele el contexto
+func (d *outboxDispatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(outboxCleanupInterval)
+	defer cleanup.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+		case <-cleanup.C:
+			d.cleanup(ctx)
+		}
+	}
+}
+
+// dispatchDue entrega lotes hasta vaciar los eventos vencidos
+func (d *outboxDispatcher) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := d.repo.ClaimDue(ctx, time.Now().UTC(), d.lease, outboxBatchSize)
+		if err != nil {
+			slog.Warn("Failed to claim outbox events", "error", err)
+			return
+		}
+		for i := range events {
+			d.dispatch(ctx, &events[i])
+		}
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (d *outboxDispatcher) dispatch(ctx context.Context, e *domain.OutboxEvent) {
+	err := d.handle(ctx, e)
+	if ctx.Err() != nil {
+		return // El lease vence y el evento se retoma al reiniciar
+	}
+	if err == nil {
+		if err := d.repo.MarkPublished(ctx, e.ID, time.Now().UTC()); err != nil {
+			slog.Error("Failed to mark outbox event published", "event_id", e.EventID, "error", err)
+		}
+		return
+	}
+
+	e.Attempts++
+	e.NextAttemptAt = time.Now().UTC().Add(domain.OutboxRetryDelay(e.Attempts))
+	e.LastError = err.Error()
+	slog.Warn("Outbox event handler failed", "event_id", e.EventID, "event_type", e.Type,
+		"attempts", e.Attempts, "error", err)
+	if err := d.repo.MarkRetry(ctx, e); err != nil {
+		slog.Error("Failed to schedule outbox retry", "event_id", e.EventID, "error", err)
+	}
+}
+
+// handle ejecuta los suscriptores con la organización del evento en el contexto; los
+// eventos sin organización (ej. login) se procesan con alcance de plataforma
+func (d *outboxDispatcher) handle(ctx context.Context, e *domain.OutboxEvent) error {
+	organizationID := domain.PlatformScope
+	if e.OrganizationID != nil {
+		organizationID = *e.OrganizationID
+	}
+	ctx = context.WithValue(ctx, "organization_id", organizationID)
+	for _, handler := range d.handlers[e.Type] {
+		handlerCtx, cancel := context.WithTimeout(ctx, outboxHandlerTimeout)
+		err := handler(handlerCtx, e)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *outboxDispatcher) cleanup(ctx context.Context) {
+	n, err := d.repo.DeletePublished(ctx, time.Now().UTC().Add(-d.retention))
+	if err != nil {
+		slog.Warn("Failed to delete published outbox events", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("Deleted published outbox events", "count", n)
+	}
+}
+
+// AuditLogHandler persiste los eventos audit.logged en audit_logs. El ID del registro
+// es el del evento, así que una entrega repetida no lo duplica.
+func AuditLogHandler(repo ports.AuditRepository) ports.EventHandler {
+	return func(ctx context.Context, e *domain.OutboxEvent) error {
+		var log domain.AuditLog
+		if err := json.Unmarshal(e.Payload, &log); err != nil {
+			slog.Error("Discarding malformed audit event", "event_id", e.EventID, "error", err)
+			return nil
+		}
+		log.ID = e.EventID
+		if log.OrganizationID == nil {
+			log.OrganizationID = e.OrganizationID
+		}
+		return repo.LogEvent(ctx, &log)
+	}
+}
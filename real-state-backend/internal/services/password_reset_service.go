@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type passwordResetService struct {
+	users         ports.UserRepository
+	resets        ports.PasswordResetRepository
+	sessions      ports.SessionRepository
+	notifications ports.NotificationService
+	auth          ports.AuthService
+	outbox        ports.OutboxRepository
+	tx            ports.TxManager
+	resetURL      string
+}
+
+// NewPasswordResetService crea el servicio de recuperación. resetURL es la página del
+// frontend que recibe el token en el parámetro token. El correo se renderiza al enviarse
+// para que el token no quede guardado en la cola de notificaciones.
+func NewPasswordResetService(users ports.UserRepository, resets ports.PasswordResetRepository, sessions ports.SessionRepository, notifications ports.NotificationService, auth ports.AuthService, outbox ports.OutboxRepository, tx ports.TxManager, resetURL string) ports.PasswordResetService {
+	s := &passwordResetService{
+		users:         users,
+		resets:        resets,
+		sessions:      sessions,
+		notifications: notifications,
+		auth:          auth,
+		outbox:        outbox,
+		tx:            tx,
+		resetURL:      resetURL,
+	}
+	notifications.RenderOnSend(domain.NotificationPasswordReset, s.issueLink)
+	return s
+}
+
+// RequestReset registra un enlace nuevo (invalida los anteriores) y encola el correo en
+// la misma transacción; el token se emite al enviarlo. Un correo desconocido o de una
+// agencia inactiva no es un error.
+func (s *passwordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil || !user.OrganizationActive {
+		slog.Info("Password reset requested for unknown or inactive account")
+		return nil
+	}
+	now := time.Now().UTC()
+	reset := &domain.PasswordReset{
+		UserID:    user.ID,
+		ExpiresAt: now.Add(domain.PasswordResetTTL),
+		CreatedAt: now,
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.resets.Create(ctx, reset); err != nil {
+			return err
+		}
+		// La clave de deduplicación identifica el enlace al enviar el correo
+		dedupeKey := domain.NotificationPasswordReset + ":" + strconv.FormatInt(reset.ID, 10)
+		return s.notifications.Notify(ctx, user.ID, domain.NotificationPasswordReset, nil, dedupeKey)
+	})
+}
+
+// ResetPassword consume el enlace, guarda la contraseña, cierra las sesiones abiertas
+// y lo audita en una sola transacción
+func (s *passwordResetService) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		userID, err := s.resets.Consume(ctx, hashResetToken(token), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if err := s.users.UpdatePassword(ctx, userID, hash); err != nil {
+			return err
+		}
+		if err := s.sessions.RevokeByUserID(ctx, userID); err != nil {
+			return err
+		}
+		event, err := domain.NewOutboxEvent(domain.EventAuditLogged, domain.AggregateUser, userID, &domain.AuditLog{
+			EventType: "PASSWORD_RESET",
+			UserID:    &userID,
+			Resource:  "auth",
+			Action:    "reset_password",
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, event)
+	})
+}
+
+// issueLink emite el token del enlace al enviar el correo. Cada intento reemplaza el
+// token anterior; un enlace usado, reemplazado o vencido descarta el correo.
+func (s *passwordResetService) issueLink(ctx context.Context, n *domain.Notification) (map[string]interface{}, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(n.DedupeKey, domain.NotificationPasswordReset+":"), 10, 64)
+	if err != nil {
+		return nil, domain.ErrNotificationExpired
+	}
+	token, err := newResetToken()
+	if err != nil {
+		return nil, err
+	}
+	link, err := url.Parse(s.resetURL)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	expiresAt, err := s.resets.IssueToken(ctx, id, n.UserID, hashResetToken(token), now)
+	if errors.Is(err, domain.ErrPasswordResetInvalid) {
+		return nil, domain.ErrNotificationExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return map[string]interface{}{
+		"ResetURL":       link.String(),
+		"ExpiresMinutes": int(math.Ceil(expiresAt.Sub(now).Minutes())),
+	}, nil
+}
+
+// newResetToken genera 32 bytes aleatorios; el token solo viaja en el correo
+func newResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashResetToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// fakeNotificationRepo guarda las notificaciones encoladas y los intentos registrados
+type fakeNotificationRepo struct {
+	ports.NotificationRepository
+	queued   []*domain.Notification
+	attempts []domain.Notification
+}
+
+func (r *fakeNotificationRepo) GetPreferences(_ context.Context, userID string) (*domain.NotificationPreferences, error) {
+	return domain.DefaultNotificationPreferences(userID), nil
+}
+
+func (r *fakeNotificationRepo) Enqueue(_ context.Context, n *domain.Notification) error {
+	n.ID = int64(len(r.queued) + 1)
+	stored := *n
+	r.queued = append(r.queued, &stored)
+	return nil
+}
+
+func (r *fakeNotificationRepo) RecordAttempt(_ context.Context, n *domain.Notification) error {
+	r.attempts = append(r.attempts, *n)
+	return nil
+}
+
+type fakeUserRepo struct {
+	ports.UserRepository
+	user *domain.User
+}
+
+func (r *fakeUserRepo) GetByEmail(context.Context, string) (*domain.User, error) { return r.user, nil }
+func (r *fakeUserRepo) GetByID(context.Context, string) (*domain.User, error)    { return r.user, nil }
+
+// fakeResetRepo guarda el hash emitido; used simula un enlace usado o reemplazado
+type fakeResetRepo struct {
+	ports.PasswordResetRepository
+	reset     *domain.PasswordReset
+	tokenHash string
+	used      bool
+}
+
+func (r *fakeResetRepo) Create(_ context.Context, reset *domain.PasswordReset) error {
+	reset.ID = 7
+	r.reset = reset
+	return nil
+}
+
+func (r *fakeResetRepo) IssueToken(_ context.Context, id int64, userID, tokenHash string, now time.Time) (time.Time, error) {
+	if r.used || id != r.reset.ID || userID != r.reset.UserID || !r.reset.ExpiresAt.After(now) {
+		return time.Time{}, domain.ErrPasswordResetInvalid
+	}
+	r.tokenHash = tokenHash
+	return r.reset.ExpiresAt, nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeEmailSender struct {
+	sent []*domain.EmailMessage
+}
+
+func (s *fakeEmailSender) Send(_ context.Context, msg *domain.EmailMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func newTestPasswordReset(t *testing.T) (ports.PasswordResetService, *notificationService, *fakeNotificationRepo, *fakeResetRepo, *fakeEmailSender) {
+	t.Helper()
+	user := &domain.User{ID: "u-1", Username: "ana", Email: "ana@example.com", OrganizationActive: true}
+	users := &fakeUserRepo{user: user}
+	notifications := &fakeNotificationRepo{}
+	resets := &fakeResetRepo{}
+	sender := &fakeEmailSender{}
+	ns := NewNotificationService(context.Background(), notifications, users, nil, sender, 5*time.Second, 0).(*notificationService)
+	s := NewPasswordResetService(users, resets, nil, ns, nil, nil, fakeTxManager{}, "https://app.example.com/reset")
+	return s, ns, notifications, resets, sender
+}
+
+func TestPasswordResetTokenIsNotQueued(t *testing.T) {
+	s, ns, notifications, resets, sender := newTestPasswordReset(t)
+	if err := s.RequestReset(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	if len(notifications.queued) != 1 {
+		t.Fatalf("queued %d notifications, want 1", len(notifications.queued))
+	}
+	queued := notifications.queued[0]
+	if queued.TextBody != "" || queued.HTMLBody != "" || strings.Contains(queued.Subject, "token") {
+		t.Errorf("queued notification keeps content: %+v", queued)
+	}
+
+	ns.send(context.Background(), queued, time.Second)
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sender.sent))
+	}
+	msg := sender.sent[0]
+	start := strings.Index(msg.Text, "https://app.example.com/reset?token=")
+	if start < 0 {
+		t.Fatalf("email has no reset link:\n%s", msg.Text)
+	}
+	link, err := url.Parse(strings.Fields(msg.Text[start:])[0])
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	if hashResetToken(link.Query().Get("token")) != resets.tokenHash {
+		t.Error("emailed token does not match the issued hash")
+	}
+	if !strings.Contains(msg.HTML, link.Query().Get("token")) {
+		t.Errorf("html has no reset link:\n%s", msg.HTML)
+	}
+	if attempt := notifications.attempts[0]; attempt.Status != domain.NotificationSent {
+		t.Errorf("status = %q", attempt.Status)
+	}
+}
+
+func TestPasswordResetDiscardsUsedLink(t *testing.T) {
+	s, ns, notifications, resets, sender := newTestPasswordReset(t)
+	if err := s.RequestReset(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	resets.used = true
+
+	ns.send(context.Background(), notifications.queued[0], time.Second)
+	if len(sender.sent) != 0 {
+		t.Errorf("sent %d emails for a used link", len(sender.sent))
+	}
+	attempt := notifications.attempts[0]
+	if attempt.Status != domain.NotificationFailed || attempt.NextAttemptAt != nil || attempt.Attempts != 0 {
+		t.Errorf("attempt = %+v, want discarded", attempt)
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+// Permisos que dan acceso a documentos de propiedades de otros agentes
+const (
+	permissionViewDocuments   = "view_property_documents"
+	permissionUploadDocuments = "update_property"
+)
+
+type propertyDocumentService struct {
+	repo         ports.PropertyDocumentRepository
+	storage      ports.DocumentStorage
+	propertyRepo ports.PropertyRepository
+	auth         ports.AuthService
+	audit        ports.AuditRepository
+	tx           ports.TxManager
+}
+
+func NewPropertyDocumentService(repo ports.PropertyDocumentRepository, storage ports.DocumentStorage, propertyRepo ports.PropertyRepository, auth ports.AuthService, audit ports.AuditRepository, tx ports.TxManager) ports.PropertyDocumentService {
+	return &propertyDocumentService{
+		repo:         repo,
+		storage:      storage,
+		propertyRepo: propertyRepo,
+		auth:         auth,
+		audit:        audit,
+		tx:           tx,
+	}
+}
+
+// Upload guarda el archivo calculando tamaño y SHA-256 al vuelo; con DocumentID agrega una versión
+func (s *propertyDocumentService) Upload(ctx context.Context, upload domain.DocumentUpload) (*domain.PropertyDocument, error) {
+	userID, err := s.authorize(ctx, upload.PropertyID, permissionUploadDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc *domain.PropertyDocument
+	if upload.DocumentID != 0 {
+		if doc, err = s.repo.GetDocument(ctx, upload.PropertyID, upload.DocumentID); err != nil {
+			return nil, err
+		}
+	} else {
+		if !slices.Contains(domain.DocumentTypes, upload.DocumentType) {
+			return nil, fmt.Errorf("%w: invalid document type %q", domain.ErrInvalidDocument, upload.DocumentType)
+		}
+		title := strings.TrimSpace(upload.Title)
+		if title == "" {
+			title = upload.FileName
+		}
+		doc = &domain.PropertyDocument{PropertyID: upload.PropertyID, DocumentType: upload.DocumentType, Title: title}
+	}
+
+	// El tipo se detecta por contenido, no por la extensión ni el header del cliente
+	content := bufio.NewReader(upload.Content)
+	head, _ := content.Peek(512)
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !slices.Contains(domain.DocumentContentTypes, contentType) {
+		return nil, fmt.Errorf("%w: unsupported file type %s", domain.ErrInvalidDocument, contentType)
+	}
+
+	key := fmt.Sprintf("properties/%d/%s%s", upload.PropertyID, uuid.New().String(), strings.ToLower(filepath.Ext(upload.FileName)))
+	hash := sha256.New()
+	counter := &countingWriter{}
+	limited := io.LimitReader(content, domain.MaxDocumentSize+1)
+	if err := s.storage.Save(ctx, key, io.TeeReader(limited, io.MultiWriter(hash, counter))); err != nil {
+		return nil, err
+	}
+	if counter.n > domain.MaxDocumentSize || counter.n == 0 {
+		s.discard(ctx, key)
+		return nil, fmt.Errorf("%w: file must be between 1 byte and %d MB", domain.ErrInvalidDocument, domain.MaxDocumentSize>>20)
+	}
+
+	version := &domain.DocumentVersion{
+		FileName:    filepath.Base(upload.FileName),
+		ContentType: contentType,
+		SizeBytes:   counter.n,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+		UploadedBy:  &userID,
+	}
+	// Documento nuevo y primera versión se confirman juntos; si la transacción
+	// falla ninguna fila apunta al archivo y se puede eliminar
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if doc.ID == 0 {
+			doc.CreatedBy = &userID
+			if err := s.repo.CreateDocument(ctx, doc); err != nil {
+				return err
+			}
+		}
+		version.DocumentID = doc.ID
+		return s.repo.AddVersion(ctx, version)
+	})
+	if err != nil {
+		s.discard(ctx, key)
+		return nil, err
+	}
+	doc.LatestVersion = version
+	doc.UpdatedAt = version.CreatedAt
+	return doc, nil
+}
+
+func (s *propertyDocumentService) List(ctx context.Context, propertyID int64) ([]domain.PropertyDocument, error) {
+	if _, err := s.authorize(ctx, propertyID, permissionViewDocuments); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDocuments(ctx, propertyID)
+}
+
+func (s *propertyDocumentService) ListVersions(ctx context.Context, propertyID, documentID int64) ([]domain.DocumentVersion, error) {
+	if _, err := s.authorize(ctx, propertyID, permissionViewDocuments); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetDocument(ctx, propertyID, documentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, documentID)
+}
+
+// Download no entrega el archivo si la descarga no quedó registrada en auditoría
+func (s *propertyDocumentService) Download(ctx context.Context, propertyID, documentID int64, version int, origin domain.RequestOrigin) (*domain.DocumentVersion, io.ReadCloser, error) {
+	userID, err := s.authorize(ctx, propertyID, permissionViewDocuments)
+	if err != nil {
+		if errors.Is(err, domain.ErrDocumentAccessDenied) {
+			s.logDownload(ctx, "DOCUMENT_DOWNLOAD_DENIED", userID, origin, map[string]interface{}{
+				"property_id": propertyID, "document_id": documentID, "version": version,
+			})
+		}
+		return nil, nil, err
+	}
+	if _, err := s.repo.GetDocument(ctx, propertyID, documentID); err != nil {
+		return nil, nil, err
+	}
+	v, err := s.repo.GetVersion(ctx, documentID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.logDownload(ctx, "DOCUMENT_DOWNLOAD", userID, origin, map[string]interface{}{
+		"property_id": propertyID, "document_id": documentID, "version": v.Version, "checksum_sha256": v.Checksum,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("audit log failed: %w", err)
+	}
+	file, err := s.storage.Open(ctx, v.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, file, nil
+}
+
+// authorize permite al agente responsable de la propiedad o a quien tenga el permiso
+func (s *propertyDocumentService) authorize(ctx context.Context, propertyID int64, permission string) (string, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	if userID == "" {
+		return "", domain.ErrDocumentAccessDenied
+	}
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if errors.Is(err, domain.ErrPropertyNotFound) {
+		return userID, fmt.Errorf("%w: %v", domain.ErrDocumentNotFound, err)
+	}
+	if err != nil {
+		return userID, err
+	}
+	if property.AgentID != nil && *property.AgentID == userID {
+		return userID, nil
+	}
+	permissions, err := s.auth.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return userID, err
+	}
+	for _, p := range permissions {
+		if p.Name == permission {
+			return userID, nil
+		}
+	}
+	return userID, domain.ErrDocumentAccessDenied
+}
+
+func (s *propertyDocumentService) logDownload(ctx context.Context, eventType, userID string, origin domain.RequestOrigin, values map[string]interface{}) error {
+	log := &domain.AuditLog{
+		EventType: eventType,
+		Resource:  "property_documents",
+		Action:    "download",
+		NewValues: values,
+		IPAddress: origin.IPAddress,
+		UserAgent: origin.UserAgent,
+		Timestamp: time.Now(),
+	}
+	if userID != "" {
+		log.UserID = &userID
+	}
+	err := s.audit.LogEvent(ctx, log)
+	if err != nil {
+		slog.Error("Failed to log document download", "event", eventType, "error", err)
+	}
+	return err
+}
+
+// discard elimina un archivo que no llegó a registrarse
+func (s *propertyDocumentService) discard(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		slog.Warn("Failed to delete orphan document", "key", key, "error", err)
+	}
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
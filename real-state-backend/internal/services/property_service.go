@@ -2,37 +2,635 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"real-state-backend/internal/core/domain"
 	"real-state-backend/internal/core/ports"
+	"sort"
+	"strconv"
+	"time"
 )
 
+var (
+	ErrInvalidStatus           = errors.New("invalid status")
+	ErrInvalidStatusTransition = errors.New("status transition not allowed")
+	ErrInvalidLanguage         = errors.New("invalid language")
+)
+
+// minZoneConfidence es la confianza mínima de una geocodificación para asignar la zona por polígono
+const minZoneConfidence = 0.5
+
 type propertyService struct {
-	repo ports.PropertyRepository
+	repo         ports.PropertyRepository
+	revisions    ports.PropertyRevisionRepository
+	translations ports.PropertyTranslationRepository
+	zones        ports.ZoneBoundaryService
+	geocoder     ports.Geocoder
+	commissions  ports.CommissionService
+	tx           ports.TxManager
+	outbox       ports.OutboxRepository
+	geocodes     chan int64
 }
 
-func NewPropertyService(repo ports.PropertyRepository) ports.PropertyService {
-	return &propertyService{
-		repo: repo,
+// NewPropertyService crea el servicio y arranca el worker que geocodifica las
+// direcciones en segundo plano. queueSize define cuántas quedan en cola.
+// Los cambios se registran en outbox en la misma transacción que la escritura.
+func NewPropertyService(ctx context.Context, repo ports.PropertyRepository, revisions ports.PropertyRevisionRepository, translations ports.PropertyTranslationRepository, zones ports.ZoneBoundaryService, geocoder ports.Geocoder, commissions ports.CommissionService, tx ports.TxManager, outbox ports.OutboxRepository, queueSize int) ports.PropertyService {
+	s := &propertyService{
+		repo:         repo,
+		revisions:    revisions,
+		translations: translations,
+		zones:        zones,
+		geocoder:     geocoder,
+		commissions:  commissions,
+		tx:           tx,
+		outbox:       outbox,
+		geocodes:     make(chan int64, queueSize),
 	}
+	go s.runGeocoder(ctx)
+	return s
 }
 
 // CORRECCIÓN AQUÍ: Agregamos ctx y el puntero *
-func (s *propertyService) CreateProperty(ctx context.Context, p *domain.Property) error {
+func (s *propertyService) CreateProperty(ctx context.Context, p *domain.Property, confirmDuplicate bool) error {
 	// Lógica de negocio (ej: validar que el título no esté vacío)
 	if p.Title == "" {
 		return fmt.Errorf("el título es obligatorio")
 	}
+	p.NormalizeRegistry()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	if p.Status == "" {
+		p.Status = domain.PropertyStatusPublished
+	}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" && p.AgentID == nil {
+		p.AgentID = &userID
+	}
+	if !domain.IsValidPropertyStatus(p.Status) {
+		return fmt.Errorf("invalid status")
+	}
+	if p.SourceLanguage == "" {
+		p.SourceLanguage = domain.DefaultLanguage
+	}
+	if domain.NormalizeLanguage(p.SourceLanguage) != p.SourceLanguage {
+		return ErrInvalidLanguage
+	}
+	if err := s.checkRegistry(ctx, p); err != nil {
+		return err
+	}
+
+	// Detección de duplicados salvo que el cliente confirme explícitamente
+	if !confirmDuplicate {
+		candidates, err := s.findDuplicateCandidates(ctx, p)
+		if err != nil {
+			return err
+		}
+		if len(candidates) > 0 {
+			return &domain.DuplicatePropertyError{Candidates: candidates}
+		}
+	}
+
+	// Coordenadas enviadas por el cliente se consideran ingresadas manualmente
+	if hasCoordinates(p) && p.GeocodeSource == "" {
+		p.SetGeocode(&domain.GeocodeResult{Lat: p.Lat, Lng: p.Lng, Confidence: 1, Source: domain.GeocodeSourceManual}, time.Now())
+	}
+	s.assignZone(ctx, p)
+	change := propertyChange{event: domain.EventPropertyCreated, revision: domain.RevisionCreate}
+	err := s.writeWithEvent(ctx, p, change, func(ctx context.Context) error {
+		return s.repo.Create(ctx, p)
+	})
+	if err != nil {
+		return err
+	}
+	s.enqueueGeocode(p)
+	return nil
+}
+
+// UpdateProperty aplica los cambios sobre la fila bloqueada y los persiste
+func (s *propertyService) UpdateProperty(ctx context.Context, id int64, apply func(p *domain.Property) error) (*domain.Property, error) {
+	var p *domain.Property
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// El cambio se aplica sobre la fila bloqueada: el geocodificador y el barrido de
+		// zonas no pueden guardar coordenadas entre la lectura y la escritura
+		var err error
+		if p, err = s.repo.GetByIDForUpdate(ctx, id); err != nil {
+			return err
+		}
+		lat, lng, source := p.Lat, p.Lng, p.GeocodeSource
+		if err := apply(p); err != nil {
+			return err
+		}
+		if p.Title == "" {
+			return fmt.Errorf("el título es obligatorio")
+		}
+		p.NormalizeRegistry()
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if err := s.checkRegistry(ctx, p); err != nil {
+			return err
+		}
+		geocodeChanged := p.Lat != lat || p.Lng != lng || p.GeocodeSource != source
+		if geocodeChanged {
+			p.SetZoneBoundary(nil)
+			s.assignZone(ctx, p)
+		}
+		change := propertyChange{event: domain.EventPropertyUpdated, revision: domain.RevisionUpdate}
+		return s.writeWithEvent(ctx, p, change, func(ctx context.Context) error {
+			if err := s.repo.Update(ctx, p); err != nil {
+				return err
+			}
+			if geocodeChanged {
+				return s.repo.ReplaceGeocode(ctx, p)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.enqueueGeocode(p)
+	return p, nil
+}
+
+// SetCoordinates fija coordenadas manuales con confianza 1 y reasigna la zona
+func (s *propertyService) SetCoordinates(ctx context.Context, id int64, lat, lng float64) (*domain.Property, error) {
+	result := &domain.GeocodeResult{Lat: lat, Lng: lng, Confidence: 1, Source: domain.GeocodeSourceManual}
+	p, err := s.updateGeocode(ctx, id, result, domain.RevisionCoordinates)
+	if err != nil {
+		return nil, err
+	}
+	p.ComputeAreas()
+	return p, nil
+}
+
+// Barrido de propiedades sin coordenadas: recupera las que no entraron a la cola
+// (cola llena, reinicio) y reintenta las no encontradas tras geocodeRetryAfter
+const (
+	geocodeSweepInterval = 10 * time.Minute
+	geocodeRetryAfter    = 24 * time.Hour
+)
+
+// enqueueGeocode encola la propiedad si aún no tiene coordenadas; si la cola
+// está llena se omite y la recoge el siguiente barrido
+func (s *propertyService) enqueueGeocode(p *domain.Property) {
+	if s.geocoder == nil || hasCoordinates(p) || p.Address == "" {
+		return
+	}
+	select {
+	case s.geocodes <- p.ID:
+	default:
+		slog.Warn("Geocode queue full, skipping property", "property_id", p.ID)
+	}
+}
+
+// runGeocoder consume la cola de geocodificación hasta que se cancele el contexto
+func (s *propertyService) runGeocoder(ctx context.Context) {
+	sweep := time.NewTicker(geocodeSweepInterval)
+	defer sweep.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sweep.C:
+			s.sweepGeocodes(ctx)
+		case id := <-s.geocodes:
+			jobCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			if err := s.geocode(jobCtx, id); err != nil {
+				slog.Warn("Failed to geocode property", "property_id", id, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// sweepGeocodes encola, hasta llenar la cola, propiedades sin coordenadas que no se
+// han intentado en geocodeRetryAfter
+func (s *propertyService) sweepGeocodes(ctx context.Context) {
+	free := cap(s.geocodes) - len(s.geocodes)
+	if s.geocoder == nil || free == 0 {
+		return
+	}
+	now := time.Now()
+	ids, err := s.repo.ClaimPendingGeocodes(ctx, now, now.Add(-geocodeRetryAfter), free)
+	if err != nil {
+		slog.Warn("Failed to list properties pending geocoding", "error", err)
+		return
+	}
+	for _, id := range ids {
+		select {
+		case s.geocodes <- id:
+		default:
+			return // Se retoman en el siguiente barrido tras geocodeRetryAfter
+		}
+	}
+	if len(ids) > 0 {
+		slog.Info("Queued properties pending geocoding", "count", len(ids))
+	}
+}
+
+// geocode obtiene las coordenadas de la dirección; no toca propiedades que ya tengan
+func (s *propertyService) geocode(ctx context.Context, id int64) error {
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasCoordinates(p) {
+		return nil
+	}
+	result, err := s.geocoder.Geocode(ctx, domain.NewGeocodeQuery(p))
+	if errors.Is(err, domain.ErrAddressNotFound) {
+		slog.Info("Address not found by geocoder", "property_id", id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.updateGeocode(ctx, id, result, domain.RevisionGeocode)
+	if errors.Is(err, domain.ErrCoordinatesAlreadySet) {
+		slog.Info("Property got coordinates while geocoding, result discarded", "property_id", id)
+		return nil
+	}
+	return err
+}
+
+// updateGeocode bloquea y relee la propiedad, guarda las coordenadas y registra la
+// revisión de la fila tal como quedó, todo en una transacción
+func (s *propertyService) updateGeocode(ctx context.Context, id int64, result *domain.GeocodeResult, changeType string) (*domain.Property, error) {
+	var p *domain.Property
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if p, err = s.repo.GetByIDForUpdate(ctx, id); err != nil {
+			return err
+		}
+		p.SetGeocode(result, time.Now())
+		// Un resultado aproximado (ej. centro del municipio) no define la colonia
+		if result.Confidence >= minZoneConfidence {
+			s.assignZone(ctx, p)
+		}
+		if err := s.repo.UpdateGeocode(ctx, p); err != nil {
+			return err
+		}
+		return recordRevision(ctx, s.revisions, p, changeType, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// assignZone asigna la zona/colonia según el polígono que contiene las coordenadas.
+// Un fallo al consultar los polígonos no bloquea el guardado.
+func (s *propertyService) assignZone(ctx context.Context, p *domain.Property) {
+	if s.zones == nil || !hasCoordinates(p) {
+		return
+	}
+	boundary, err := s.zones.Locate(ctx, p.Lat, p.Lng)
+	if err != nil {
+		slog.Warn("Zone lookup failed", "property_id", p.ID, "error", err)
+		return
+	}
+	p.SetZoneBoundary(boundary)
+}
+
+// ChangeStatus aplica un cambio de estado validando las transiciones permitidas.
+// Al salir del mercado se registra closed_at (usado para el tiempo en mercado).
+func (s *propertyService) ChangeStatus(ctx context.Context, id int64, status string, sellingAgentID *string) (*domain.Property, error) {
+	if !domain.IsValidPropertyStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+
+	// La transición se valida sobre la fila bloqueada dentro de la misma transacción
+	var p *domain.Property
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if p, err = s.repo.GetByIDForUpdate(ctx, id); err != nil {
+			return err
+		}
+		if !p.CanTransitionTo(status) {
+			return ErrInvalidStatusTransition
+		}
+		previous := p.Status
+		// Reactivar una propiedad no debe duplicar la identidad registral de otra activa
+		if !domain.IsActiveStatus(p.Status) {
+			p.Status = status
+			if err := s.checkRegistry(ctx, p); err != nil {
+				return err
+			}
+		}
+
+		var closedAt *time.Time
+		if domain.IsClosedStatus(status) {
+			now := time.Now()
+			closedAt = &now
+		}
+		p.Status, p.ClosedAt = status, closedAt
+		change := propertyChange{event: domain.EventPropertyStatusChanged, previousStatus: previous, revision: domain.RevisionStatus}
+		return s.writeWithEvent(ctx, p, change, func(ctx context.Context) error {
+			if err := s.repo.UpdateStatus(ctx, id, previous, status, closedAt); err != nil {
+				return err
+			}
+			return s.settleCommissions(ctx, id, status, sellingAgentID)
+		})
+	})
+	if errors.Is(err, domain.ErrPropertyStatusChanged) {
+		return nil, ErrInvalidStatusTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// settleCommissions liquida las comisiones al vender o rentar. Sin regla para la operación
+// no hay qué liquidar: se puede liquidar después con POST /properties/{id}/commissions.
+func (s *propertyService) settleCommissions(ctx context.Context, id int64, status string, sellingAgentID *string) error {
+	if s.commissions == nil || domain.OperationForStatus(status) == "" {
+		return nil
+	}
+	_, err := s.commissions.Settle(ctx, id, sellingAgentID)
+	if errors.Is(err, domain.ErrCommissionRuleNotFound) {
+		slog.Warn("No commission rule for closing, settlement skipped", "property_id", id, "status", status)
+		return nil
+	}
+	return err
+}
+
+// propertyChange describe el evento de outbox y la revisión que genera un cambio
+type propertyChange struct {
+	event          string
+	previousStatus string
+	revision       string
+	revertedFrom   *int
+}
+
+// writeWithEvent ejecuta write y registra el evento y la revisión de la propiedad en
+// la misma transacción: si cualquiera falla no se confirma ninguno
+func (s *propertyService) writeWithEvent(ctx context.Context, p *domain.Property, change propertyChange, write func(ctx context.Context) error) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := write(ctx); err != nil {
+			return err
+		}
+		p.ComputeAreas()
+		event, err := newPropertyEvent(change.event, p, change.previousStatus)
+		if err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, event); err != nil {
+			return err
+		}
+		return recordRevision(ctx, s.revisions, p, change.revision, change.revertedFrom)
+	})
+}
+
+// newPropertyEvent arma el evento de outbox de una propiedad sin sus datos registrales
+func newPropertyEvent(eventType string, p *domain.Property, previousStatus string) (*domain.OutboxEvent, error) {
+	data := domain.PropertyEventData{Property: *p, PreviousStatus: previousStatus}
+	data.Property.HideRegistry()
+	return domain.NewOutboxEvent(eventType, domain.AggregateProperty, strconv.FormatInt(p.ID, 10), data)
+}
 
-	return s.repo.Create(ctx, p)
+// checkRegistry rechaza una propiedad activa cuya identidad registral ya usa otra activa.
+// El índice único parcial cubre las carreras entre esta verificación y el guardado.
+func (s *propertyService) checkRegistry(ctx context.Context, p *domain.Property) error {
+	if !domain.IsActiveStatus(p.Status) || (!p.HasRegistry() && p.RegistryIUSI == "") {
+		return nil
+	}
+	conflict, err := s.repo.FindRegistryConflict(ctx, p)
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		return conflict
+	}
+	return nil
+}
+
+func (s *propertyService) ListRevisions(ctx context.Context, id int64) ([]domain.PropertyRevision, error) {
+	return s.revisions.List(ctx, id)
+}
+
+func (s *propertyService) GetRevision(ctx context.Context, id int64, revision int) (*domain.PropertyRevision, error) {
+	return s.revisions.Get(ctx, id, revision)
+}
+
+// DiffRevisions compara dos revisiones; to = 0 compara contra la más reciente
+func (s *propertyService) DiffRevisions(ctx context.Context, id int64, from, to int) (*domain.RevisionDiff, error) {
+	older, err := s.revisions.Get(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := s.revisions.Get(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RevisionDiff{
+		PropertyID: id,
+		From:       older.Revision,
+		To:         newer.Revision,
+		Changes:    domain.DiffProperties(older.Snapshot, newer.Snapshot),
+	}, nil
+}
+
+// RevertToRevision restaura el contenido de una revisión. El estado no se
+// restaura porque tiene sus propias transiciones (ver ChangeStatus).
+func (s *propertyService) RevertToRevision(ctx context.Context, id int64, revision int) (*domain.Property, error) {
+	rev, err := s.revisions.Get(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored domain.Property
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Estado y fechas salen de la fila bloqueada, no de una lectura previa
+		current, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		restored = *rev.Snapshot
+		restored.ID = current.ID
+		restored.Status, restored.ClosedAt = current.Status, current.ClosedAt
+		restored.CreatedAt = current.CreatedAt
+		// El polígono pudo cambiar o eliminarse desde entonces
+		restored.ZoneBoundaryID, restored.Neighbourhood = nil, ""
+		s.assignZone(ctx, &restored)
+
+		change := propertyChange{event: domain.EventPropertyUpdated, revision: domain.RevisionRevert, revertedFrom: &rev.Revision}
+		return s.writeWithEvent(ctx, &restored, change, func(ctx context.Context) error {
+			if err := s.repo.Update(ctx, &restored); err != nil {
+				return err
+			}
+			return s.repo.ReplaceGeocode(ctx, &restored)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// Localize carga en una sola consulta las traducciones de los idiomas preferidos
+func (s *propertyService) Localize(ctx context.Context, properties []domain.Property, languages []string) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+	var translations []domain.PropertyTranslation
+	if len(languages) > 0 {
+		var err error
+		if translations, err = s.translations.List(ctx, ids, languages); err != nil {
+			return err
+		}
+	}
+
+	byProperty := make(map[int64][]domain.PropertyTranslation)
+	for _, t := range translations {
+		byProperty[t.PropertyID] = append(byProperty[t.PropertyID], t)
+	}
+	for i := range properties {
+		properties[i].Localize(languages, byProperty[properties[i].ID])
+	}
+	return nil
+}
+
+func (s *propertyService) ListTranslations(ctx context.Context, id int64) ([]domain.PropertyTranslation, error) {
+	return s.translations.List(ctx, []int64{id}, nil)
+}
+
+// SaveTranslation crea o reemplaza la traducción; el idioma original se edita con UpdateProperty
+func (s *propertyService) SaveTranslation(ctx context.Context, t *domain.PropertyTranslation) error {
+	if domain.NormalizeLanguage(t.Language) != t.Language {
+		return fmt.Errorf("%w: unsupported language %q", ErrInvalidLanguage, t.Language)
+	}
+	p, err := s.repo.GetByID(ctx, t.PropertyID)
+	if err != nil {
+		return err
+	}
+	if t.Language == p.SourceLanguage {
+		return fmt.Errorf("%w: %q is the source language of the property", ErrInvalidLanguage, t.Language)
+	}
+	return s.translations.Upsert(ctx, t)
+}
+
+func (s *propertyService) DeleteTranslation(ctx context.Context, id int64, language string) error {
+	return s.translations.Delete(ctx, id, language)
+}
+
+// duplicatePageSize es el número de pares candidatos leídos por consulta
+const duplicatePageSize = 500
+
+// FindDuplicates retorna los pares del catálogo que probablemente son el mismo inmueble.
+// Recorre todos los candidatos por páginas y conserva los limit de mayor puntaje.
+func (s *propertyService) FindDuplicates(ctx context.Context, limit int) ([]domain.DuplicatePair, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	result := make([]domain.DuplicatePair, 0)
+	var afterFirst, afterSecond int64
+	for {
+		pairs, err := s.repo.FindDuplicatePairs(ctx, afterFirst, afterSecond, duplicatePageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range pairs {
+			score, reasons, distance := duplicateScore(&pair.First, &pair.Second)
+			if score < duplicateThreshold {
+				continue
+			}
+			pair.Score, pair.Reasons, pair.DistanceMeters = score, reasons, distance
+			result = append(result, pair)
+		}
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+		if len(result) > limit {
+			result = result[:limit]
+		}
+		if len(pairs) < duplicatePageSize {
+			return result, nil
+		}
+		last := pairs[len(pairs)-1]
+		afterFirst, afterSecond = last.First.ID, last.Second.ID
+	}
+}
+
+// BackfillAddresses recalcula por lotes la dirección normalizada con domain.NormalizeAddress
+// para que las filas existentes se comparen igual que las nuevas
+func (s *propertyService) BackfillAddresses(ctx context.Context, batchSize int) (int, error) {
+	if batchSize < 1 {
+		batchSize = 500
+	}
+
+	updated := 0
+	var afterID int64
+	for {
+		properties, err := s.repo.ListAddresses(ctx, afterID, batchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(properties) == 0 {
+			return updated, nil
+		}
+		for _, p := range properties {
+			afterID = p.ID
+			changed, err := s.repo.UpdateAddressNormalized(ctx, p.ID, domain.NormalizeAddress(p.Address))
+			if err != nil {
+				return updated, err
+			}
+			if changed {
+				updated++
+			}
+		}
+	}
+}
+
+// findDuplicateCandidates busca propiedades existentes que superen el umbral de similitud
+func (s *propertyService) findDuplicateCandidates(ctx context.Context, p *domain.Property) ([]domain.DuplicateCandidate, error) {
+	similar, err := s.repo.FindSimilar(ctx, p, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]domain.DuplicateCandidate, 0)
+	for i := range similar {
+		score, reasons, distance := duplicateScore(p, &similar[i])
+		if score < duplicateThreshold {
+			continue
+		}
+		candidates = append(candidates, domain.DuplicateCandidate{
+			Property:       similar[i],
+			Score:          score,
+			Reasons:        reasons,
+			DistanceMeters: distance,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates, nil
 }
 
 // Asegúrate de que los otros métodos también tengan el contexto:
 func (s *propertyService) GetProperty(ctx context.Context, id int64) (*domain.Property, error) {
-	return s.repo.GetByID(ctx, id)
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.ComputeAreas()
+	return p, nil
 }
 
-func (s *propertyService) ListProperties(ctx context.Context, page, pageSize int) ([]domain.Property, error) {
+func (s *propertyService) ListProperties(ctx context.Context, filter domain.PropertyFilter, page, pageSize int) ([]domain.Property, error) {
 	offset := (page - 1) * pageSize
-	return s.repo.GetAll(ctx, pageSize, offset)
+	properties, err := s.repo.GetAll(ctx, filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range properties {
+		properties[i].ComputeAreas()
+	}
+	return properties, nil
 }
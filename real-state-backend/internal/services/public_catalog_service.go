@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// Paginación y tamaño máximo de la caché del catálogo público
+const (
+	publicPageSize     = 20
+	publicCacheMaxKeys = 1000
+)
+
+type publicCacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+type publicCatalogService struct {
+	properties ports.PropertyService
+	precision  int
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]publicCacheEntry
+}
+
+// NewPublicCatalogService crea el catálogo público; precision son los decimales de
+// las coordenadas expuestas y ttl la vigencia de la caché (0 = sin caché).
+func NewPublicCatalogService(properties ports.PropertyService, precision int, ttl time.Duration) ports.PublicCatalogService {
+	return &publicCatalogService{
+		properties: properties,
+		precision:  precision,
+		ttl:        ttl,
+		cache:      make(map[string]publicCacheEntry),
+	}
+}
+
+// List solo incluye propiedades publicadas, sin importar el filtro recibido
+func (s *publicCatalogService) List(ctx context.Context, filter domain.PropertyFilter, page int, languages []string) ([]domain.PublicProperty, error) {
+	filter.Statuses = []string{domain.PropertyStatusPublished}
+	filter.Fields = nil
+	key := fmt.Sprintf("list:%+v:%d:%s", filter, page, strings.Join(languages, ","))
+	if cached, ok := s.cached(key); ok {
+		return cached.([]domain.PublicProperty), nil
+	}
+
+	properties, err := s.properties.ListProperties(ctx, filter, page, publicPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.properties.Localize(ctx, properties, languages); err != nil {
+		return nil, err
+	}
+	public := make([]domain.PublicProperty, len(properties))
+	for i := range properties {
+		public[i] = domain.NewPublicProperty(&properties[i], s.precision)
+	}
+	s.store(key, public)
+	return public, nil
+}
+
+func (s *publicCatalogService) Get(ctx context.Context, id int64, languages []string) (*domain.PublicProperty, error) {
+	key := fmt.Sprintf("get:%d:%s", id, strings.Join(languages, ","))
+	if cached, ok := s.cached(key); ok {
+		return cached.(*domain.PublicProperty), nil
+	}
+
+	p, err := s.properties.GetProperty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != domain.PropertyStatusPublished {
+		return nil, domain.ErrPropertyNotListed
+	}
+	localized := []domain.Property{*p}
+	if err := s.properties.Localize(ctx, localized, languages); err != nil {
+		return nil, err
+	}
+	public := domain.NewPublicProperty(&localized[0], s.precision)
+	s.store(key, &public)
+	return &public, nil
+}
+
+func (s *publicCatalogService) cached(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// store guarda la respuesta; al llegar al máximo descarta las vencidas (o todas)
+func (s *publicCatalogService) store(key string, value any) {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if len(s.cache) >= publicCacheMaxKeys {
+		for k, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= publicCacheMaxKeys {
+			s.cache = make(map[string]publicCacheEntry)
+		}
+	}
+	s.cache[key] = publicCacheEntry{value: value, expiresAt: now.Add(s.ttl)}
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// recordRevision guarda en el historial la copia de la propiedad tal como quedó en la
+// transacción del cambio; debe llamarse dentro de ella para que ambos se confirmen juntos
+func recordRevision(ctx context.Context, revisions ports.PropertyRevisionRepository, p *domain.Property, changeType string, revertedFrom *int) error {
+	if revisions == nil {
+		return nil
+	}
+	snapshot := *p
+	rev := &domain.PropertyRevision{
+		PropertyID:   p.ID,
+		ChangeType:   changeType,
+		RevertedFrom: revertedFrom,
+		Snapshot:     &snapshot,
+	}
+	if uid, ok := ctx.Value("user_id").(string); ok && uid != "" {
+		rev.ChangedBy = &uid
+	}
+	return revisions.Create(ctx, rev)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+type savedSearchService struct {
+	repo          ports.SavedSearchRepository
+	properties    ports.PropertyRepository
+	notifications ports.NotificationService
+}
+
+func NewSavedSearchService(repo ports.SavedSearchRepository, properties ports.PropertyRepository, notifications ports.NotificationService) ports.SavedSearchService {
+	return &savedSearchService{repo: repo, properties: properties, notifications: notifications}
+}
+
+// Create guarda la búsqueda del usuario autenticado hasta MaxSavedSearches
+func (s *savedSearchService) Create(ctx context.Context, search *domain.SavedSearch) error {
+	userID, _ := ctx.Value("user_id").(string)
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= domain.MaxSavedSearches {
+		return domain.ErrSavedSearchLimit
+	}
+	search.UserID = userID
+	search.CreatedAt = time.Now().UTC()
+	search.UpdatedAt = search.CreatedAt
+	return s.repo.Create(ctx, search)
+}
+
+func (s *savedSearchService) List(ctx context.Context) ([]domain.SavedSearch, error) {
+	userID, _ := ctx.Value("user_id").(string)
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *savedSearchService) Delete(ctx context.Context, id int64) error {
+	userID, _ := ctx.Value("user_id").(string)
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// HandlePropertyCreated avisa de las propiedades creadas ya publicadas
+func (s *savedSearchService) HandlePropertyCreated(ctx context.Context, e *domain.OutboxEvent) error {
+	return s.notifyMatches(ctx, e)
+}
+
+// HandlePropertyStatusChanged avisa cuando una propiedad pasa a publicada: un borrador
+// que se publica o una propiedad que vuelve al mercado
+func (s *savedSearchService) HandlePropertyStatusChanged(ctx context.Context, e *domain.OutboxEvent) error {
+	var data domain.PropertyEventData
+	if err := json.Unmarshal(e.Payload, &data); err != nil {
+		slog.Warn("Ignoring saved search event with invalid payload", "event_id", e.EventID, "error", err)
+		return nil
+	}
+	if data.Property.Status != domain.PropertyStatusPublished || data.PreviousStatus == domain.PropertyStatusPublished {
+		return nil
+	}
+	return s.notifyMatches(ctx, e)
+}
+
+// notifyMatches avisa una sola vez a cada usuario de la agencia con alguna búsqueda
+// que cumple la propiedad publicada. La clave del evento y el usuario evita duplicar
+// el aviso si el outbox lo entrega más de una vez.
+func (s *savedSearchService) notifyMatches(ctx context.Context, e *domain.OutboxEvent) error {
+	propertyID, err := strconv.ParseInt(e.AggregateID, 10, 64)
+	if err != nil || e.AggregateType != domain.AggregateProperty || e.OrganizationID == nil {
+		slog.Warn("Ignoring saved search event without property", "event_id", e.EventID)
+		return nil
+	}
+	property, err := s.properties.GetByID(ctx, propertyID)
+	if errors.Is(err, domain.ErrPropertyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Los borradores no se anuncian, ni las que dejaron de estar publicadas desde el evento
+	if property.Status != domain.PropertyStatusPublished {
+		return nil
+	}
+	searches, err := s.repo.ListCandidates(ctx, *e.OrganizationID, property.Type)
+	if err != nil {
+		return err
+	}
+	notified := make(map[string]bool)
+	for _, search := range searches {
+		// El agente responsable no recibe aviso de su propia propiedad
+		if notified[search.UserID] || (property.AgentID != nil && *property.AgentID == search.UserID) ||
+			!search.Criteria.Matches(property) {
+			continue
+		}
+		notified[search.UserID] = true
+		data := map[string]interface{}{
+			"SearchName":    search.Name,
+			"PropertyID":    strconv.FormatInt(property.ID, 10),
+			"PropertyTitle": property.Title,
+			"Price":         strconv.FormatFloat(property.Price, 'f', 2, 64),
+			"Currency":      property.Currency,
+			"City":          property.City,
+		}
+		dedupeKey := domain.NotificationSavedSearchMatch + ":" + e.EventID + ":" + search.UserID
+		if err := s.notifications.Notify(ctx, search.UserID, domain.NotificationSavedSearchMatch, data, dedupeKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// ErrInvalidShareLinkTTL indica una vigencia fuera de rango
+var ErrInvalidShareLinkTTL = errors.New("share link ttl must be between 1 hour and 30 days")
+
+type shareLinkService struct {
+	repo         ports.ShareLinkRepository
+	propertyRepo ports.PropertyRepository
+	secret       []byte
+}
+
+func NewShareLinkService(repo ports.ShareLinkRepository, propertyRepo ports.PropertyRepository, secret string) ports.ShareLinkService {
+	return &shareLinkService{repo: repo, propertyRepo: propertyRepo, secret: []byte(secret)}
+}
+
+// Create genera un enlace para una propiedad publicada o reservada; ttl = 0 usa la vigencia por defecto
+func (s *shareLinkService) Create(ctx context.Context, propertyID int64, ttl time.Duration) (*domain.ShareLink, error) {
+	if ttl == 0 {
+		ttl = domain.DefaultShareLinkTTL
+	}
+	if ttl < time.Hour || ttl > domain.MaxShareLinkTTL {
+		return nil, ErrInvalidShareLinkTTL
+	}
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.IsListedStatus(property.Status) {
+		return nil, domain.ErrPropertyNotListed
+	}
+
+	link := &domain.ShareLink{
+		PropertyID: propertyID,
+		ExpiresAt:  time.Now().Add(ttl).Truncate(time.Second),
+	}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		link.CreatedBy = &userID
+	}
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	link.Token = s.sign(link.ID, link.ExpiresAt)
+	return link, nil
+}
+
+func (s *shareLinkService) List(ctx context.Context, propertyID int64) ([]domain.ShareLink, error) {
+	return s.repo.List(ctx, propertyID)
+}
+
+func (s *shareLinkService) Revoke(ctx context.Context, propertyID, id int64) error {
+	return s.repo.Revoke(ctx, propertyID, id)
+}
+
+// Open verifica firma y vencimiento antes de consultar la BD; revocación y
+// estado de la propiedad se validan contra los datos actuales
+func (s *shareLinkService) Open(ctx context.Context, token string) (*domain.Property, *domain.ShareLink, error) {
+	id, expiresAt, ok := s.verify(token)
+	if !ok || !time.Now().Before(expiresAt) {
+		return nil, nil, domain.ErrShareLinkInvalid
+	}
+	link, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrShareLinkNotFound) {
+			return nil, nil, domain.ErrShareLinkInvalid
+		}
+		return nil, nil, err
+	}
+	if !link.IsActive(time.Now()) {
+		return nil, nil, domain.ErrShareLinkInvalid
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, link.PropertyID)
+	if err != nil || !domain.IsListedStatus(property.Status) {
+		return nil, nil, domain.ErrShareLinkInvalid
+	}
+	if err := s.repo.RecordOpen(ctx, link.ID); err != nil {
+		if errors.Is(err, domain.ErrShareLinkInvalid) {
+			return nil, nil, err
+		}
+		slog.Warn("Error recording share link open", "link_id", link.ID, "error", err)
+	}
+	property.ComputeAreas()
+	return property, link, nil
+}
+
+// sign arma el token "<payload>.<firma>" con payload = base64url("<id>.<vencimiento unix>")
+func (s *shareLinkService) sign(id int64, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", id, expiresAt.Unix())))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+func (s *shareLinkService) verify(token string) (int64, time.Time, bool) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, time.Time{}, false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.mac(payload)) {
+		return 0, time.Time{}, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	var id, expires int64
+	if _, err := fmt.Sscanf(string(raw), "%d.%d", &id, &expires); err != nil || id < 1 {
+		return 0, time.Time{}, false
+	}
+	return id, time.Unix(expires, 0), true
+}
+
+// mac separa el uso de la clave de cualquier otra firma con el mismo secreto
+func (s *shareLinkService) mac(payload string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("share-link:" + payload))
+	return h.Sum(nil)
+}
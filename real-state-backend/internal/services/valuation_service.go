@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// minComparables es la cantidad mínima de comparables para emitir una estimación
+const minComparables = 3
+
+// ErrInsufficientComparables indica que no hay suficientes propiedades de referencia
+var ErrInsufficientComparables = errors.New("insufficient comparables for valuation")
+
+type valuationService struct {
+	propertyRepo ports.PropertyRepository
+	rateRepo     ports.ExchangeRateRepository
+}
+
+func NewValuationService(propertyRepo ports.PropertyRepository, rateRepo ports.ExchangeRateRepository) ports.ValuationService {
+	return &valuationService{
+		propertyRepo: propertyRepo,
+		rateRepo:     rateRepo,
+	}
+}
+
+// Estimate calcula el rango de precio a partir del precio por m² de los comparables.
+// Se descartan valores atípicos fuera de [Q1 - 1.5·IQR, Q3 + 1.5·IQR].
+func (s *valuationService) Estimate(ctx context.Context, criteria domain.ComparableCriteria, currency string) (*domain.ValuationEstimate, error) {
+	if criteria.AreaSqM <= 0 {
+		return nil, errors.New("area must be positive")
+	}
+	if criteria.RadiusKm <= 0 {
+		criteria.RadiusKm = 2
+	}
+	// Para terrenos no se comparan habitaciones
+	if criteria.Type == "Terreno" {
+		criteria.Bedrooms = 0
+	}
+
+	rates, err := s.rateRepo.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := s.propertyRepo.FindComparables(ctx, criteria, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	hasCoords := criteria.Lat != 0 || criteria.Lng != 0
+	comparables := make([]domain.Comparable, 0, len(properties))
+	for _, p := range properties {
+		price, err := convertCurrency(rates, p.Price, p.Currency, currency)
+		if err != nil {
+			slog.Warn("Skipping comparable without exchange rate", "property_id", p.ID, "currency", p.Currency)
+			continue
+		}
+		c := domain.Comparable{
+			PropertyID:  p.ID,
+			Title:       p.Title,
+			City:        p.City,
+			Price:       p.Price,
+			Currency:    p.Currency,
+			AreaSqM:     p.AreaSqM,
+			Bedrooms:    p.Bedrooms,
+			PricePerSqM: price / p.AreaSqM,
+		}
+		if hasCoords && hasCoordinates(&p) {
+			distance := haversineMeters(criteria.Lat, criteria.Lng, p.Lat, p.Lng)
+			if distance > criteria.RadiusKm*1000 {
+				continue
+			}
+			c.DistanceMeters = &distance
+		}
+		comparables = append(comparables, c)
+	}
+
+	if len(comparables) < minComparables {
+		return nil, ErrInsufficientComparables
+	}
+
+	sort.Slice(comparables, func(i, j int) bool { return comparables[i].PricePerSqM < comparables[j].PricePerSqM })
+	q1, _, q3 := quartiles(pricesPerSqM(comparables))
+	iqr := q3 - q1
+
+	// Filtrado de atípicos y recálculo sobre la muestra limpia
+	kept := make([]domain.Comparable, 0, len(comparables))
+	for _, c := range comparables {
+		if c.PricePerSqM >= q1-1.5*iqr && c.PricePerSqM <= q3+1.5*iqr {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) < minComparables {
+		return nil, ErrInsufficientComparables
+	}
+	q1, median, q3 := quartiles(pricesPerSqM(kept))
+
+	return &domain.ValuationEstimate{
+		Currency:          currency,
+		AreaSqM:           criteria.AreaSqM,
+		EstimateLow:       round2(q1 * criteria.AreaSqM),
+		Estimate:          round2(median * criteria.AreaSqM),
+		EstimateHigh:      round2(q3 * criteria.AreaSqM),
+		MedianPricePerSqM: round2(median),
+		Q1PricePerSqM:     round2(q1),
+		Q3PricePerSqM:     round2(q3),
+		IQR:               round2(q3 - q1),
+		SampleSize:        len(kept),
+		OutliersDiscarded: len(comparables) - len(kept),
+		Comparables:       kept,
+	}, nil
+}
+
+func pricesPerSqM(comparables []domain.Comparable) []float64 {
+	values := make([]float64, len(comparables))
+	for i, c := range comparables {
+		values[i] = c.PricePerSqM
+	}
+	return values
+}
+
+// quartiles retorna Q1, mediana y Q3 de una muestra ordenada (interpolación lineal)
+func quartiles(sorted []float64) (float64, float64, float64) {
+	return percentile(sorted, 0.25), percentile(sorted, 0.5), percentile(sorted, 0.75)
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
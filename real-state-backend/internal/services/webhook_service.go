@@ -0,0 +1,351 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookBatchSize limita las entregas enviadas en paralelo por ciclo
+	webhookBatchSize = 10
+	// maxWebhookResponseBody limita la respuesta guardada en la bitácora
+	maxWebhookResponseBody = 1024
+)
+
+// sharedAddressSpace es el rango CGNAT (RFC 6598), no enrutable desde internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+type webhookService struct {
+	repo         ports.WebhookRepository
+	client       *http.Client
+	lease        time.Duration
+	wake         chan struct{}
+	allowPrivate bool
+}
+
+// NewWebhookService crea el servicio y despacha las entregas pendientes cada interval
+// en segundo plano. timeout limita cada envío; las redirecciones no se siguen. Sin
+// allowPrivate los destinos privados o locales se rechazan al guardar y al conectar.
+func NewWebhookService(ctx context.Context, repo ports.WebhookRepository, timeout, interval time.Duration, allowPrivate bool) ports.WebhookService {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		// Se valida la dirección ya resuelta de cada conexión (evita DNS rebinding);
+		// sin proxy para que el control aplique al destino real
+		dialer.Control = webhookDialControl
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+	s := &webhookService{
+		repo: repo,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		lease:        2*timeout + time.Minute,
+		wake:         make(chan struct{}, 1),
+		allowPrivate: allowPrivate,
+	}
+	if interval > 0 {
+		go s.dispatchLoop(ctx, interval)
+	}
+	return s
+}
+
+// EnqueueEvent encola un evento del outbox para las suscripciones de la agencia dueña
+// de la propiedad. El ID del evento es el del outbox: un reintento no duplica entregas.
+func (s *webhookService) EnqueueEvent(ctx context.Context, e *domain.OutboxEvent) error {
+	propertyID, err := strconv.ParseInt(e.AggregateID, 10, 64)
+	if err != nil || e.AggregateType != domain.AggregateProperty {
+		slog.Warn("Ignoring webhook event without property", "event_id", e.EventID, "event_type", e.Type)
+		return nil
+	}
+	event := &domain.WebhookEvent{
+		ID:         e.EventID,
+		Type:       e.Type,
+		PropertyID: propertyID,
+		CreatedAt:  e.CreatedAt.UTC().Truncate(time.Millisecond),
+		Data:       e.Payload,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	n, err := s.repo.Enqueue(ctx, event, payload)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.notify()
+	}
+	return nil
+}
+
+func (s *webhookService) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return err
+	}
+	if err := s.checkURL(ctx, sub.URL); err != nil {
+		return err
+	}
+	sub.Secret = secret
+	sub.Active = true
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		sub.CreatedBy = &userID
+	}
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *webhookService) Get(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *webhookService) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// Update aplica los cambios; con RotateSecret el nuevo secreto se retorna una sola vez
+func (s *webhookService) Update(ctx context.Context, id int64, changes domain.WebhookSubscriptionChanges) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.URL != nil {
+		if err := s.checkURL(ctx, *changes.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *changes.URL
+	}
+	if changes.Description != nil {
+		sub.Description = *changes.Description
+	}
+	if changes.EventTypes != nil {
+		sub.EventTypes = changes.EventTypes
+	}
+	if changes.Active != nil {
+		sub.Active = *changes.Active
+	}
+	if changes.RotateSecret {
+		if sub.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *webhookService) Ping(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	event := newWebhookEvent(domain.WebhookEventPing, 0, map[string]int64{"subscription_id": id})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.EnqueueTo(ctx, id, event, payload)
+	if err != nil {
+		return nil, err
+	}
+	s.notify()
+	return delivery, nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if _, err := s.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	return s.repo.GetDelivery(ctx, subscriptionID, id)
+}
+
+// Redeliver reenvía el evento de una entrega (exitosa, fallida o pendiente) como una entrega nueva
+func (s *webhookService) Redeliver(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	delivery, err := s.repo.Redeliver(ctx, subscriptionID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.notify()
+	return delivery, nil
+}
+
+// notify adelanta el siguiente ciclo del despachador sin bloquear
+func (s *webhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchLoop envía las entregas vencidas hasta que se cancele el contexto
+func (s *webhookService) dispatchLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.dispatchDue(ctx)
+	}
+}
+
+// dispatchDue envía lotes hasta vaciar las entregas vencidas
+func (s *webhookService) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDue(ctx, time.Now().UTC(), s.lease, webhookBatchSize)
+		if err != nil {
+			slog.Warn("Failed to claim webhook deliveries", "error", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(d *domain.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, d)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+	}
+}
+
+func (s *webhookService) deliver(ctx context.Context, d *domain.WebhookDelivery) {
+	start := time.Now()
+	attempt := domain.WebhookAttempt{Attempt: d.Attempts + 1}
+	attempt.StatusCode, attempt.ResponseBody, attempt.Error = s.send(ctx, d)
+	attempt.DurationMs = int(time.Since(start).Milliseconds())
+	attempt.CreatedAt = time.Now().UTC()
+
+	d.RecordAttempt(attempt)
+	if err := s.repo.RecordAttempt(ctx, d, &attempt); err != nil {
+		slog.Error("Failed to record webhook attempt", "delivery_id", d.ID, "error", err)
+		return
+	}
+	if d.Status == domain.WebhookDeliveryFailed {
+		slog.Warn("Webhook delivery failed permanently", "delivery_id", d.ID, "subscription_id", d.SubscriptionID,
+			"attempts", d.Attempts, "error", d.LastError)
+	}
+}
+
+// send hace un envío firmado; retorna el código y cuerpo de la respuesta (si la hubo)
+// y el error, que incluye las respuestas fuera de 2xx
+func (s *webhookService) send(ctx context.Context, d *domain.WebhookDelivery) (*int, string, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Target.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, "", err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "real-state-backend-webhooks/1.0")
+	req.Header.Set(domain.WebhookHeaderEvent, d.EventType)
+	req.Header.Set(domain.WebhookHeaderEventID, d.EventID)
+	req.Header.Set(domain.WebhookHeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(domain.WebhookHeaderSignature, domain.SignWebhook(d.Target.Secret, time.Now(), d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &resp.StatusCode, string(body), resp.Status
+	}
+	return &resp.StatusCode, string(body), ""
+}
+
+func newWebhookEvent(eventType string, propertyID int64, data interface{}) *domain.WebhookEvent {
+	return &domain.WebhookEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		PropertyID: propertyID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+		Data:       data,
+	}
+}
+
+// newWebhookSecret genera el secreto de firma de una suscripción
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// checkURL resuelve el host del destino y rechaza direcciones privadas o locales
+func (s *webhookService) checkURL(ctx context.Context, raw string) error {
+	if s.allowPrivate {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrWebhookURLNotAllowed, err)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: %s does not resolve", domain.ErrWebhookURLNotAllowed, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", domain.ErrWebhookURLNotAllowed, u.Hostname(), addr)
+		}
+	}
+	return nil
+}
+
+// webhookDialControl rechaza la conexión si la dirección resuelta no es pública
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookURLNotAllowed, addrPort.Addr())
+	}
+	return nil
+}
+
+// publicAddress excluye loopback, redes privadas, link-local (incluida la metadata de
+// la nube en 169.254.169.254), CGNAT, multicast y la dirección no especificada
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
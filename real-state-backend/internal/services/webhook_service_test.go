@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// fakeWebhookRepo guarda los intentos registrados; los demás métodos no se usan
+type fakeWebhookRepo struct {
+	ports.WebhookRepository
+	attempts []domain.WebhookAttempt
+	created  []*domain.WebhookSubscription
+}
+
+func (r *fakeWebhookRepo) RecordAttempt(_ context.Context, _ *domain.WebhookDelivery, a *domain.WebhookAttempt) error {
+	r.attempts = append(r.attempts, *a)
+	return nil
+}
+
+func (r *fakeWebhookRepo) CreateSubscription(_ context.Context, sub *domain.WebhookSubscription) error {
+	r.created = append(r.created, sub)
+	return nil
+}
+
+const testWebhookSecret = "whsec_test"
+
+func newTestWebhookService(t *testing.T, allowPrivate bool) (*webhookService, *fakeWebhookRepo) {
+	t.Helper()
+	repo := &fakeWebhookRepo{}
+	s := NewWebhookService(context.Background(), repo, 5*time.Second, 0, allowPrivate).(*webhookService)
+	return s, repo
+}
+
+func newTestDelivery(url string) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:        42,
+		EventID:   "evt-1",
+		EventType: domain.EventPropertyCreated,
+		Payload:   []byte(`{"id":"evt-1","type":"property.created"}`),
+		Status:    domain.WebhookDeliveryPending,
+		Target:    domain.WebhookTarget{URL: url, Secret: testWebhookSecret},
+	}
+}
+
+func TestWebhookDeliverySignsRequest(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(domain.WebhookHeaderEvent); got != domain.EventPropertyCreated {
+			t.Errorf("event header = %q", got)
+		}
+		if got := r.Header.Get(domain.WebhookHeaderEventID); got != "evt-1" {
+			t.Errorf("event id header = %q", got)
+		}
+		if got := r.Header.Get(domain.WebhookHeaderDelivery); got != "42" {
+			t.Errorf("delivery header = %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("content type = %q", got)
+		}
+		signature := r.Header.Get(domain.WebhookHeaderSignature)
+		if !domain.VerifyWebhookSignature(testWebhookSecret, signature, body, time.Now(), time.Minute) {
+			t.Errorf("signature %q does not verify", signature)
+		}
+		if domain.VerifyWebhookSignature("whsec_other", signature, body, time.Now(), time.Minute) {
+			t.Error("signature verifies with another secret")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, repo := newTestWebhookService(t, true)
+	d := newTestDelivery(server.URL)
+	s.deliver(context.Background(), d)
+
+	if received.Load() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", received.Load())
+	}
+	if d.Status != domain.WebhookDeliverySucceeded || d.DeliveredAt == nil {
+		t.Errorf("status = %q, delivered_at = %v", d.Status, d.DeliveredAt)
+	}
+	if len(repo.attempts) != 1 || repo.attempts[0].StatusCode == nil || *repo.attempts[0].StatusCode != http.StatusNoContent {
+		t.Errorf("recorded attempts = %+v", repo.attempts)
+	}
+}
+
+func TestWebhookDeliveryRetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s, repo := newTestWebhookService(t, true)
+	d := newTestDelivery(server.URL)
+	s.deliver(context.Background(), d)
+
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 1 {
+		t.Fatalf("status = %q, attempts = %d", d.Status, d.Attempts)
+	}
+	attempt := repo.attempts[0]
+	if attempt.StatusCode == nil || *attempt.StatusCode != http.StatusInternalServerError || attempt.Error == "" {
+		t.Errorf("attempt = %+v", attempt)
+	}
+	if !strings.Contains(attempt.ResponseBody, "boom") {
+		t.Errorf("response body = %q", attempt.ResponseBody)
+	}
+	if d.NextAttemptAt == nil || !d.NextAttemptAt.Equal(attempt.CreatedAt.Add(30*time.Second)) {
+		t.Errorf("next attempt = %v, want %v", d.NextAttemptAt, attempt.CreatedAt.Add(30*time.Second))
+	}
+
+	// El último intento permitido deja la entrega como fallida, sin otro reintento
+	d.Attempts = domain.MaxWebhookAttempts - 1
+	s.deliver(context.Background(), d)
+	if d.Status != domain.WebhookDeliveryFailed || d.NextAttemptAt != nil {
+		t.Errorf("after last attempt status = %q, next attempt = %v", d.Status, d.NextAttemptAt)
+	}
+}
+
+func TestWebhookRetryDelaySchedule(t *testing.T) {
+	want := []time.Duration{
+		30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute,
+		16 * time.Minute, 32 * time.Minute, 64 * time.Minute, 128 * time.Minute, 256 * time.Minute,
+		domain.MaxWebhookRetryDelay,
+	}
+	for i, delay := range want {
+		if got := domain.WebhookRetryDelay(i + 1); got != delay {
+			t.Errorf("WebhookRetryDelay(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+}
+
+func TestWebhookDeliveryDoesNotFollowRedirects(t *testing.T) {
+	var followed atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	s, _ := newTestWebhookService(t, true)
+	d := newTestDelivery(server.URL)
+	s.deliver(context.Background(), d)
+
+	if followed.Load() != 0 {
+		t.Errorf("redirect was followed %d times", followed.Load())
+	}
+	if d.LastStatusCode == nil || *d.LastStatusCode != http.StatusFound || d.Status != domain.WebhookDeliveryPending {
+		t.Errorf("status code = %v, status = %q", d.LastStatusCode, d.Status)
+	}
+}
+
+func TestWebhookRejectsPrivateDestinations(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, repo := newTestWebhookService(t, false)
+
+	// Al guardar: el receptor local resuelve a loopback
+	err := s.Create(context.Background(), &domain.WebhookSubscription{URL: server.URL})
+	if !errors.Is(err, domain.ErrWebhookURLNotAllowed) {
+		t.Errorf("Create error = %v, want ErrWebhookURLNotAllowed", err)
+	}
+	if len(repo.created) != 0 {
+		t.Error("subscription to a private destination was saved")
+	}
+
+	// Al conectar: una suscripción guardada antes no llega al destino
+	d := newTestDelivery(server.URL)
+	s.deliver(context.Background(), d)
+	if received.Load() != 0 {
+		t.Errorf("receiver got %d requests", received.Load())
+	}
+	if d.LastStatusCode != nil || !strings.Contains(d.LastError, domain.ErrWebhookURLNotAllowed.Error()) {
+		t.Errorf("status code = %v, error = %q", d.LastStatusCode, d.LastError)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.10":    false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"::ffff:10.0.0.1": false,
+		"224.0.0.1":       false,
+	}
+	for addr, want := range tests {
+		if got := publicAddress(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("publicAddress(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"real-state-backend/internal/core/domain"
+	"real-state-backend/internal/core/ports"
+)
+
+// zoneCacheTTL define cada cuánto se recargan los polígonos en memoria
+const zoneCacheTTL = 10 * time.Minute
+
+// ErrInvalidGeoJSON indica que el archivo cargado no es un GeoJSON válido
+var ErrInvalidGeoJSON = errors.New("invalid GeoJSON")
+
+type zoneBoundaryService struct {
+	repo         ports.ZoneBoundaryRepository
+	propertyRepo ports.PropertyRepository
+	revisions    ports.PropertyRevisionRepository
+	tx           ports.TxManager
+
+	mu         sync.RWMutex
+	boundaries []domain.ZoneBoundary
+	loadedAt   time.Time
+}
+
+func NewZoneBoundaryService(repo ports.ZoneBoundaryRepository, propertyRepo ports.PropertyRepository, revisions ports.PropertyRevisionRepository, tx ports.TxManager) ports.ZoneBoundaryService {
+	return &zoneBoundaryService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		revisions:    revisions,
+		tx:           tx,
+	}
+}
+
+// geoJSONFeature es un Feature GeoJSON con los metadatos del límite en properties
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties struct {
+		Name           string `json:"name"`
+		Kind           string `json:"kind"`
+		MunicipalityID *int64 `json:"municipality_id"`
+		GeoZoneID      *int64 `json:"geo_zone_id"`
+	} `json:"properties"`
+}
+
+// Import valida todos los features antes de guardar; un feature inválido rechaza la carga completa
+func (s *zoneBoundaryService) Import(ctx context.Context, geojson []byte) ([]domain.ZoneBoundary, error) {
+	var doc struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}
+	if err := json.Unmarshal(geojson, &doc); err != nil {
+		return nil, ErrInvalidGeoJSON
+	}
+
+	features := doc.Features
+	switch doc.Type {
+	case "FeatureCollection":
+	case "Feature":
+		var feature geoJSONFeature
+		if err := json.Unmarshal(geojson, &feature); err != nil {
+			return nil, ErrInvalidGeoJSON
+		}
+		features = []geoJSONFeature{feature}
+	default:
+		return nil, fmt.Errorf("%w: expected Feature or FeatureCollection", ErrInvalidGeoJSON)
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("%w: no features", ErrInvalidGeoJSON)
+	}
+
+	boundaries := make([]domain.ZoneBoundary, 0, len(features))
+	for i, f := range features {
+		if f.Properties.Name == "" {
+			return nil, fmt.Errorf("%w: feature %d has no properties.name", ErrInvalidGeoJSON, i)
+		}
+		kind := f.Properties.Kind
+		if kind == "" {
+			kind = domain.BoundaryKindNeighbourhood
+		}
+		if kind != domain.BoundaryKindZone && kind != domain.BoundaryKindNeighbourhood {
+			return nil, fmt.Errorf("%w: feature %d has invalid kind %q", ErrInvalidGeoJSON, i, kind)
+		}
+		polygons, err := domain.ParseGeometry(f.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: feature %d: %v", ErrInvalidGeoJSON, i, err)
+		}
+
+		b := domain.ZoneBoundary{
+			Name:           f.Properties.Name,
+			Kind:           kind,
+			MunicipalityID: f.Properties.MunicipalityID,
+			GeoZoneID:      f.Properties.GeoZoneID,
+			Geometry:       f.Geometry,
+			Polygons:       polygons,
+		}
+		b.ComputeBounds()
+		boundaries = append(boundaries, b)
+	}
+
+	// El archivo se importa completo o no se importa
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for i := range boundaries {
+			if err := s.repo.Upsert(ctx, &boundaries[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return boundaries, nil
+}
+
+func (s *zoneBoundaryService) List(ctx context.Context) ([]domain.ZoneBoundary, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *zoneBoundaryService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Locate prefiere el polígono de menor extensión (una colonia sobre su zona)
+func (s *zoneBoundaryService) Locate(ctx context.Context, lat, lng float64) (*domain.ZoneBoundary, error) {
+	boundaries, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *domain.ZoneBoundary
+	for i := range boundaries {
+		b := &boundaries[i]
+		if !b.Contains(lat, lng) {
+			continue
+		}
+		if best == nil || b.BoundsArea() < best.BoundsArea() {
+			best = b
+		}
+	}
+	return best, nil
+}
+
+// Backfill recorre por lotes las propiedades con coordenadas y actualiza las que cambian de zona
+func (s *zoneBoundaryService) Backfill(ctx context.Context, batchSize int) (int, error) {
+	if batchSize < 1 {
+		batchSize = 500
+	}
+
+	updated := 0
+	var afterID int64
+	for {
+		properties, err := s.propertyRepo.ListWithCoordinates(ctx, afterID, batchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(properties) == 0 {
+			return updated, nil
+		}
+
+		for i := range properties {
+			p := &properties[i]
+			afterID = p.ID
+
+			boundary, err := s.Locate(ctx, p.Lat, p.Lng)
+			if err != nil {
+				return updated, err
+			}
+			previous := p.ZoneBoundaryID
+			p.SetZoneBoundary(boundary)
+			if sameID(previous, p.ZoneBoundaryID) {
+				continue
+			}
+			err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := s.propertyRepo.UpdateZone(ctx, p.ID, p.ZoneBoundaryID, p.ZoneID); err != nil {
+					return err
+				}
+				return recordRevision(ctx, s.revisions, p, domain.RevisionZone, nil)
+			})
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+// load retorna los polígonos en memoria, recargándolos si expiraron
+func (s *zoneBoundaryService) load(ctx context.Context) ([]domain.ZoneBoundary, error) {
+	s.mu.RLock()
+	boundaries, loadedAt := s.boundaries, s.loadedAt
+	s.mu.RUnlock()
+	if boundaries != nil && time.Since(loadedAt) < zoneCacheTTL {
+		return boundaries, nil
+	}
+
+	boundaries, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range boundaries {
+		polygons, err := domain.ParseGeometry(boundaries[i].Geometry)
+		if err != nil {
+			slog.Warn("Skipping invalid zone boundary", "id", boundaries[i].ID, "error", err)
+			continue
+		}
+		boundaries[i].Polygons = polygons
+	}
+
+	s.mu.Lock()
+	s.boundaries, s.loadedAt = boundaries, time.Now()
+	s.mu.Unlock()
+	return boundaries, nil
+}
+
+func (s *zoneBoundaryService) invalidate() {
+	s.mu.Lock()
+	s.boundaries = nil
+	s.mu.Unlock()
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,3 @@
+-- Migration: 000005_property_analytics.down.sql
+DELETE FROM permissions WHERE name = 'view_property_analytics';
+DROP TABLE IF EXISTS property_events;
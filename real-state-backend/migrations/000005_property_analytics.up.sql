@@ -0,0 +1,24 @@
+-- Migration: 000005_property_analytics.up.sql
+-- Contadores de vistas, favoritos y consultas por propiedad
+
+CREATE TABLE property_events (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('view', 'favorite', 'inquiry')),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    device_id VARCHAR(255),
+    viewer_key VARCHAR(300) NOT NULL, -- usuario/dispositivo para deduplicar
+    event_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    metadata JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Vistas y favoritos se cuentan una sola vez por usuario/dispositivo y día
+CREATE UNIQUE INDEX uq_property_events_daily ON property_events(property_id, event_type, viewer_key, event_date)
+    WHERE event_type IN ('view', 'favorite');
+CREATE INDEX idx_property_events_property_date ON property_events(property_id, event_date);
+CREATE INDEX idx_property_events_date_type ON property_events(event_date, event_type);
+
+-- Permiso para consultar reportes de analítica
+INSERT INTO permissions (name, resource, action) VALUES ('view_property_analytics', 'analytics', 'read');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'view_property_analytics';
@@ -0,0 +1,5 @@
+-- Migration: 000006_property_duplicates.down.sql
+DELETE FROM permissions WHERE name = 'review_duplicate_properties';
+DROP INDEX IF EXISTS idx_properties_lat_lng;
+DROP INDEX IF EXISTS idx_properties_address_normalized;
+ALTER TABLE properties DROP COLUMN IF EXISTS address_normalized;
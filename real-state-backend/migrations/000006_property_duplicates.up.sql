@@ -0,0 +1,21 @@
+-- Migration: 000006_property_duplicates.up.sql
+-- Dirección normalizada para detección de propiedades duplicadas
+
+ALTER TABLE properties ADD COLUMN address_normalized TEXT;
+
+-- Backfill aproximado de domain.NormalizeAddress para las filas existentes
+UPDATE properties SET address_normalized = btrim(regexp_replace(
+    regexp_replace(regexp_replace(regexp_replace(regexp_replace(
+        translate(lower(address), 'áéíóúüñ.,#-/()', 'aeiouun       '),
+        '\m(avenida|avda|ave)\M', 'av', 'g'),
+        '\m(calle|cll)\M', 'c', 'g'),
+        '\mzona\M', 'z', 'g'),
+        '\m(numero|num)\M', 'no', 'g'),
+    '\s+', ' ', 'g'));
+
+CREATE INDEX idx_properties_address_normalized ON properties(address_normalized);
+CREATE INDEX idx_properties_lat_lng ON properties(lat, lng);
+
+-- Permiso para el reporte de duplicados del catálogo
+INSERT INTO permissions (name, resource, action) VALUES ('review_duplicate_properties', 'properties', 'review_duplicates');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'review_duplicate_properties';
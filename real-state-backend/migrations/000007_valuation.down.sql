@@ -0,0 +1,3 @@
+-- Migration: 000007_valuation.down.sql
+DROP INDEX IF EXISTS idx_properties_type_city;
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,15 @@
+-- Migration: 000007_valuation.up.sql
+-- Tipos de cambio para normalizar precios entre monedas (USD como base)
+
+CREATE TABLE exchange_rates (
+    currency VARCHAR(3) PRIMARY KEY,
+    rate_to_usd DECIMAL(18, 8) NOT NULL CHECK (rate_to_usd > 0), -- USD por unidad de la moneda
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO exchange_rates (currency, rate_to_usd) VALUES
+    ('USD', 1),
+    ('GTQ', 0.12850000);
+
+-- Índice para la búsqueda de comparables por tipo y ciudad
+CREATE INDEX idx_properties_type_city ON properties(type, lower(city));
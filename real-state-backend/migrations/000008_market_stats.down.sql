@@ -0,0 +1,8 @@
+-- Migration: 000008_market_stats.down.sql
+DELETE FROM permissions WHERE name = 'view_market_stats';
+DROP MATERIALIZED VIEW IF EXISTS market_stats_monthly;
+DROP TRIGGER IF EXISTS trg_properties_price_history ON properties;
+DROP FUNCTION IF EXISTS record_property_price();
+DROP TABLE IF EXISTS property_price_history;
+DROP INDEX IF EXISTS idx_properties_status;
+ALTER TABLE properties DROP COLUMN IF EXISTS closed_at, DROP COLUMN IF EXISTS status;
@@ -0,0 +1,128 @@
+-- Migration: 000008_market_stats.up.sql
+-- Estado de publicación, historial de precios y estadísticas de mercado mensuales
+
+ALTER TABLE properties
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published', 'reserved', 'sold', 'rented', 'withdrawn')),
+    ADD COLUMN closed_at TIMESTAMP; -- Fecha en que salió del mercado (vendida, rentada o retirada)
+
+CREATE INDEX idx_properties_status ON properties(status);
+
+-- Historial de precios, alimentado por trigger en cada alta o cambio de precio
+CREATE TABLE property_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL,
+    previous_price DECIMAL(10, 2),
+    currency VARCHAR(3) NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_property_price_history_property ON property_price_history(property_id, changed_at);
+
+CREATE OR REPLACE FUNCTION record_property_price() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO property_price_history (property_id, price, currency)
+        VALUES (NEW.id, NEW.price, COALESCE(NEW.currency, 'USD'));
+    ELSIF NEW.price IS DISTINCT FROM OLD.price OR NEW.currency IS DISTINCT FROM OLD.currency THEN
+        INSERT INTO property_price_history (property_id, price, previous_price, currency)
+        VALUES (NEW.id, NEW.price, OLD.price, COALESCE(NEW.currency, 'USD'));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_properties_price_history
+    AFTER INSERT OR UPDATE OF price, currency ON properties
+    FOR EACH ROW EXECUTE FUNCTION record_property_price();
+
+-- Precio inicial de las propiedades existentes
+INSERT INTO property_price_history (property_id, price, currency, changed_at)
+SELECT id, price, COALESCE(currency, 'USD'), COALESCE(created_at, CURRENT_TIMESTAMP) FROM properties;
+
+-- Estadísticas mensuales por ciudad y tipo, normalizadas a USD.
+-- Se refresca periódicamente desde la API (REFRESH MATERIALIZED VIEW CONCURRENTLY).
+CREATE MATERIALIZED VIEW market_stats_monthly AS
+WITH normalized AS (
+    SELECT p.id, lower(btrim(COALESCE(p.city, ''))) AS city, p.type, p.status,
+           p.price, COALESCE(p.currency, 'USD') AS currency, p.area_sqm,
+           p.created_at, p.closed_at
+    FROM properties p
+    WHERE p.status <> 'draft' AND p.created_at IS NOT NULL
+),
+months AS (
+    SELECT generate_series(
+        date_trunc('month', (SELECT MIN(created_at) FROM normalized)),
+        date_trunc('month', CURRENT_TIMESTAMP),
+        INTERVAL '1 month') AS month
+),
+-- Inventario al cierre de cada mes, con el precio vigente según el historial
+active AS (
+    SELECT m.month, n.city, n.type,
+           COALESCE(h.price, n.price) * COALESCE(er.rate_to_usd, 1) AS price_usd,
+           CASE WHEN n.area_sqm > 0
+                THEN COALESCE(h.price, n.price) * COALESCE(er.rate_to_usd, 1) / n.area_sqm
+           END AS price_per_sqm_usd
+    FROM months m
+    JOIN normalized n ON n.created_at < m.month + INTERVAL '1 month'
+                     AND (n.closed_at IS NULL OR n.closed_at >= m.month + INTERVAL '1 month')
+    LEFT JOIN LATERAL (
+        SELECT ph.price, ph.currency FROM property_price_history ph
+        WHERE ph.property_id = n.id AND ph.changed_at < m.month + INTERVAL '1 month'
+        ORDER BY ph.changed_at DESC LIMIT 1
+    ) h ON TRUE
+    LEFT JOIN exchange_rates er ON er.currency = COALESCE(h.currency, n.currency)
+),
+inventory AS (
+    SELECT month, city, type, COUNT(*) AS inventory_count,
+           percentile_cont(0.5) WITHIN GROUP (ORDER BY price_usd) AS median_price_usd,
+           percentile_cont(0.5) WITHIN GROUP (ORDER BY price_per_sqm_usd) AS median_price_per_sqm_usd
+    FROM active
+    GROUP BY month, city, type
+),
+new_listings AS (
+    SELECT date_trunc('month', created_at) AS month, city, type, COUNT(*) AS new_listings
+    FROM normalized
+    GROUP BY 1, 2, 3
+),
+closed AS (
+    SELECT date_trunc('month', closed_at) AS month, city, type, COUNT(*) AS closed_count,
+           AVG(EXTRACT(EPOCH FROM closed_at - created_at) / 86400) AS avg_days_on_market
+    FROM normalized
+    WHERE status IN ('sold', 'rented') AND closed_at IS NOT NULL
+    GROUP BY 1, 2, 3
+),
+reductions AS (
+    SELECT date_trunc('month', ph.changed_at) AS month, n.city, n.type, COUNT(*) AS price_reductions
+    FROM property_price_history ph
+    JOIN normalized n ON n.id = ph.property_id
+    WHERE ph.previous_price IS NOT NULL AND ph.price < ph.previous_price
+    GROUP BY 1, 2, 3
+),
+keys AS (
+    SELECT month, city, type FROM inventory
+    UNION SELECT month, city, type FROM new_listings
+    UNION SELECT month, city, type FROM closed
+    UNION SELECT month, city, type FROM reductions
+)
+SELECT k.month::date AS month, k.city, k.type,
+       COALESCE(i.inventory_count, 0) AS inventory_count,
+       COALESCE(nl.new_listings, 0) AS new_listings,
+       COALESCE(c.closed_count, 0) AS closed_count,
+       i.median_price_usd,
+       i.median_price_per_sqm_usd,
+       c.avg_days_on_market,
+       COALESCE(rd.price_reductions, 0) AS price_reductions
+FROM keys k
+LEFT JOIN inventory i ON i.month = k.month AND i.city = k.city AND i.type = k.type
+LEFT JOIN new_listings nl ON nl.month = k.month AND nl.city = k.city AND nl.type = k.type
+LEFT JOIN closed c ON c.month = k.month AND c.city = k.city AND c.type = k.type
+LEFT JOIN reductions rd ON rd.month = k.month AND rd.city = k.city AND rd.type = k.type;
+
+-- Índice único requerido para REFRESH ... CONCURRENTLY
+CREATE UNIQUE INDEX uq_market_stats_monthly ON market_stats_monthly(month, city, type);
+
+-- Permiso para consultar estadísticas de mercado
+INSERT INTO permissions (name, resource, action) VALUES ('view_market_stats', 'market_stats', 'read');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'view_market_stats';
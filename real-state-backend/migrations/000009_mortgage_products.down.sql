@@ -0,0 +1,3 @@
+-- Migration: 000009_mortgage_products.down.sql
+DELETE FROM permissions WHERE name = 'manage_mortgage_products';
+DROP TABLE IF EXISTS mortgage_products;
@@ -0,0 +1,26 @@
+-- Migration: 000009_mortgage_products.up.sql
+-- Productos hipotecarios de bancos usados como presets de la calculadora
+
+CREATE TABLE mortgage_products (
+    id SERIAL PRIMARY KEY,
+    bank_name VARCHAR(100) NOT NULL,
+    product_name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL CHECK (currency IN ('USD', 'GTQ')),
+    annual_rate DECIMAL(6, 4) NOT NULL CHECK (annual_rate >= 0 AND annual_rate < 1), -- 0.0850 = 8.5%
+    max_term_years INT NOT NULL CHECK (max_term_years BETWEEN 1 AND 40),
+    min_down_payment_pct DECIMAL(5, 4) NOT NULL DEFAULT 0.10 CHECK (min_down_payment_pct BETWEEN 0 AND 1),
+    max_payment_to_income DECIMAL(5, 4) NOT NULL DEFAULT 0.35 CHECK (max_payment_to_income > 0 AND max_payment_to_income <= 1),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bank_name, product_name, currency)
+);
+
+INSERT INTO mortgage_products (bank_name, product_name, currency, annual_rate, max_term_years, min_down_payment_pct, max_payment_to_income) VALUES
+    ('FHA', 'Seguro de hipoteca FHA', 'GTQ', 0.0750, 30, 0.10, 0.35),
+    ('Banco Genérico', 'Vivienda en quetzales', 'GTQ', 0.0850, 25, 0.15, 0.35),
+    ('Banco Genérico', 'Vivienda en dólares', 'USD', 0.0725, 20, 0.20, 0.30);
+
+-- Permiso para administrar los presets
+INSERT INTO permissions (name, resource, action) VALUES ('manage_mortgage_products', 'mortgage_products', 'manage');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'manage_mortgage_products';
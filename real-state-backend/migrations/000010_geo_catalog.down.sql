@@ -0,0 +1,8 @@
+-- Migration: 000010_geo_catalog.down.sql
+ALTER TABLE properties
+    DROP COLUMN IF EXISTS zone_id,
+    DROP COLUMN IF EXISTS municipality_id,
+    DROP COLUMN IF EXISTS department_id;
+DROP TABLE IF EXISTS geo_zones;
+DROP TABLE IF EXISTS geo_municipalities;
+DROP TABLE IF EXISTS geo_departments;
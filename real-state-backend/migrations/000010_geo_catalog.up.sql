@@ -0,0 +1,118 @@
+-- Migration: 000010_geo_catalog.up.sql
+-- Catálogo administrativo: departamentos, municipios y zonas de Guatemala
+
+CREATE TABLE geo_departments (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(2) UNIQUE NOT NULL, -- Código INE
+    name VARCHAR(50) UNIQUE NOT NULL
+);
+
+CREATE TABLE geo_municipalities (
+    id SERIAL PRIMARY KEY,
+    department_id INT NOT NULL REFERENCES geo_departments(id) ON DELETE CASCADE,
+    code VARCHAR(4) UNIQUE NOT NULL, -- Código INE
+    name VARCHAR(80) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}', -- Variantes usadas en texto libre ("Guate", "Xela")
+    UNIQUE (department_id, name)
+);
+
+CREATE TABLE geo_zones (
+    id SERIAL PRIMARY KEY,
+    municipality_id INT NOT NULL REFERENCES geo_municipalities(id) ON DELETE CASCADE,
+    number INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE (municipality_id, number)
+);
+
+CREATE INDEX idx_geo_municipalities_department ON geo_municipalities(department_id);
+
+INSERT INTO geo_departments (code, name) VALUES
+    ('01', 'Guatemala'), ('02', 'El Progreso'), ('03', 'Sacatepéquez'), ('04', 'Chimaltenango'),
+    ('05', 'Escuintla'), ('06', 'Santa Rosa'), ('07', 'Sololá'), ('08', 'Totonicapán'),
+    ('09', 'Quetzaltenango'), ('10', 'Suchitepéquez'), ('11', 'Retalhuleu'), ('12', 'San Marcos'),
+    ('13', 'Huehuetenango'), ('14', 'Quiché'), ('15', 'Baja Verapaz'), ('16', 'Alta Verapaz'),
+    ('17', 'Petén'), ('18', 'Izabal'), ('19', 'Zacapa'), ('20', 'Chiquimula'),
+    ('21', 'Jalapa'), ('22', 'Jutiapa');
+
+-- Municipios del departamento de Guatemala y cabeceras departamentales.
+-- El resto del catálogo INE se agrega con migraciones posteriores.
+INSERT INTO geo_municipalities (department_id, code, name, aliases)
+SELECT d.id, m.code, m.name, m.aliases
+FROM (VALUES
+    ('01', '0101', 'Guatemala', ARRAY['Guate', 'Ciudad de Guatemala', 'Ciudad Guatemala', 'Guatemala City', 'Cdad. de Guatemala']),
+    ('01', '0102', 'Santa Catarina Pinula', ARRAY['Sta. Catarina Pinula']),
+    ('01', '0103', 'San José Pinula', ARRAY['SJP']),
+    ('01', '0104', 'San José del Golfo', ARRAY[]::TEXT[]),
+    ('01', '0105', 'Palencia', ARRAY[]::TEXT[]),
+    ('01', '0106', 'Chinautla', ARRAY[]::TEXT[]),
+    ('01', '0107', 'San Pedro Ayampuc', ARRAY[]::TEXT[]),
+    ('01', '0108', 'Mixco', ARRAY[]::TEXT[]),
+    ('01', '0109', 'San Pedro Sacatepéquez', ARRAY[]::TEXT[]),
+    ('01', '0110', 'San Juan Sacatepéquez', ARRAY[]::TEXT[]),
+    ('01', '0111', 'San Raymundo', ARRAY[]::TEXT[]),
+    ('01', '0112', 'Chuarrancho', ARRAY[]::TEXT[]),
+    ('01', '0113', 'Fraijanes', ARRAY[]::TEXT[]),
+    ('01', '0114', 'Amatitlán', ARRAY[]::TEXT[]),
+    ('01', '0115', 'Villa Nueva', ARRAY[]::TEXT[]),
+    ('01', '0116', 'Villa Canales', ARRAY[]::TEXT[]),
+    ('01', '0117', 'San Miguel Petapa', ARRAY['Petapa']),
+    ('02', '0201', 'Guastatoya', ARRAY['El Progreso']),
+    ('03', '0301', 'Antigua Guatemala', ARRAY['Antigua', 'La Antigua', 'La Antigua Guatemala']),
+    ('04', '0401', 'Chimaltenango', ARRAY[]::TEXT[]),
+    ('05', '0501', 'Escuintla', ARRAY[]::TEXT[]),
+    ('06', '0601', 'Cuilapa', ARRAY[]::TEXT[]),
+    ('07', '0701', 'Sololá', ARRAY[]::TEXT[]),
+    ('08', '0801', 'Totonicapán', ARRAY[]::TEXT[]),
+    ('09', '0901', 'Quetzaltenango', ARRAY['Xela', 'Xelajú']),
+    ('10', '1001', 'Mazatenango', ARRAY[]::TEXT[]),
+    ('11', '1101', 'Retalhuleu', ARRAY['Reu']),
+    ('12', '1201', 'San Marcos', ARRAY[]::TEXT[]),
+    ('13', '1301', 'Huehuetenango', ARRAY['Huehue']),
+    ('14', '1401', 'Santa Cruz del Quiché', ARRAY['Quiché']),
+    ('15', '1501', 'Salamá', ARRAY[]::TEXT[]),
+    ('16', '1601', 'Cobán', ARRAY[]::TEXT[]),
+    ('17', '1701', 'Flores', ARRAY[]::TEXT[]),
+    ('18', '1801', 'Puerto Barrios', ARRAY[]::TEXT[]),
+    ('19', '1901', 'Zacapa', ARRAY[]::TEXT[]),
+    ('20', '2001', 'Chiquimula', ARRAY[]::TEXT[]),
+    ('21', '2101', 'Jalapa', ARRAY[]::TEXT[]),
+    ('22', '2201', 'Jutiapa', ARRAY[]::TEXT[])
+) AS m(department_code, code, name, aliases)
+JOIN geo_departments d ON d.code = m.department_code;
+
+-- Zonas de la Ciudad de Guatemala (no existen las zonas 20, 22 y 23)
+INSERT INTO geo_zones (municipality_id, number, name)
+SELECT m.id, z.n, 'Zona ' || z.n
+FROM geo_municipalities m,
+     unnest(ARRAY[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 21, 24, 25]) AS z(n)
+WHERE m.code = '0101';
+
+-- Ubicación normalizada en propiedades (city conserva el nombre canónico del municipio)
+ALTER TABLE properties
+    ADD COLUMN department_id INT REFERENCES geo_departments(id),
+    ADD COLUMN municipality_id INT REFERENCES geo_municipalities(id),
+    ADD COLUMN zone_id INT REFERENCES geo_zones(id);
+
+CREATE INDEX idx_properties_department ON properties(department_id);
+CREATE INDEX idx_properties_municipality ON properties(municipality_id);
+CREATE INDEX idx_properties_zone ON properties(zone_id);
+
+-- Backfill: asociar propiedades existentes cuyo city coincide con un nombre o alias.
+-- Los nombres que coinciden con más de un municipio quedan sin resolver.
+WITH matches AS (
+    SELECT p.id AS property_id, m.id AS municipality_id, m.department_id, m.name
+    FROM properties p
+    JOIN geo_municipalities m
+      ON lower(btrim(p.city)) = lower(m.name)
+      OR lower(btrim(p.city)) = ANY (SELECT lower(a) FROM unnest(m.aliases) AS a)
+), unique_matches AS (
+    SELECT property_id, min(municipality_id) AS municipality_id
+    FROM matches
+    GROUP BY property_id
+    HAVING count(*) = 1
+)
+UPDATE properties p
+SET municipality_id = m.municipality_id, department_id = m.department_id, city = m.name
+FROM unique_matches u
+JOIN matches m ON m.property_id = u.property_id
+WHERE p.id = u.property_id;
@@ -0,0 +1,4 @@
+-- Migration: 000011_zone_boundaries.down.sql
+DELETE FROM permissions WHERE name = 'manage_zone_boundaries';
+ALTER TABLE properties DROP COLUMN IF EXISTS zone_boundary_id;
+DROP TABLE IF EXISTS zone_boundaries;
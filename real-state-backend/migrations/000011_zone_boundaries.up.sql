@@ -0,0 +1,27 @@
+-- Migration: 000011_zone_boundaries.up.sql
+-- Polígonos de zonas/colonias (GeoJSON) para asignar la zona de cada propiedad
+
+CREATE TABLE zone_boundaries (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'neighbourhood' CHECK (kind IN ('zone', 'neighbourhood')),
+    municipality_id INT REFERENCES geo_municipalities(id) ON DELETE SET NULL,
+    geo_zone_id INT REFERENCES geo_zones(id) ON DELETE SET NULL,
+    geometry JSONB NOT NULL, -- GeoJSON Polygon o MultiPolygon
+    min_lat DECIMAL(10, 8) NOT NULL,
+    min_lng DECIMAL(11, 8) NOT NULL,
+    max_lat DECIMAL(10, 8) NOT NULL,
+    max_lng DECIMAL(11, 8) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, kind)
+);
+
+CREATE INDEX idx_zone_boundaries_bbox ON zone_boundaries(min_lat, max_lat, min_lng, max_lng);
+
+ALTER TABLE properties ADD COLUMN zone_boundary_id INT REFERENCES zone_boundaries(id) ON DELETE SET NULL;
+CREATE INDEX idx_properties_zone_boundary ON properties(zone_boundary_id);
+
+-- Permiso para cargar polígonos
+INSERT INTO permissions (name, resource, action) VALUES ('manage_zone_boundaries', 'zone_boundaries', 'manage');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'manage_zone_boundaries';
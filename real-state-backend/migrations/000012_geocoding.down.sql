@@ -0,0 +1,3 @@
+-- Migration: 000012_geocoding.down.sql
+ALTER TABLE geo_municipalities DROP COLUMN IF EXISTS lat, DROP COLUMN IF EXISTS lng;
+ALTER TABLE properties DROP COLUMN IF EXISTS geocode_confidence, DROP COLUMN IF EXISTS geocode_source, DROP COLUMN IF EXISTS geocoded_at;
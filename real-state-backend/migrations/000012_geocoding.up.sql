@@ -0,0 +1,36 @@
+-- Migration: 000012_geocoding.up.sql
+-- Geocodificación de direcciones: origen y confianza de las coordenadas
+
+ALTER TABLE properties
+    ADD COLUMN geocode_confidence DECIMAL(3, 2) CHECK (geocode_confidence BETWEEN 0 AND 1),
+    ADD COLUMN geocode_source VARCHAR(20), -- manual, nominatim, catalog
+    ADD COLUMN geocoded_at TIMESTAMP;
+
+-- Las coordenadas existentes fueron ingresadas a mano
+UPDATE properties
+SET geocode_confidence = 1, geocode_source = 'manual', geocoded_at = updated_at
+WHERE lat IS NOT NULL AND lng IS NOT NULL;
+
+-- Centro del casco urbano de cada municipio (respaldo del geocodificador sin conexión)
+ALTER TABLE geo_municipalities
+    ADD COLUMN lat DECIMAL(10, 8),
+    ADD COLUMN lng DECIMAL(11, 8);
+
+UPDATE geo_municipalities m
+SET lat = c.lat, lng = c.lng
+FROM (VALUES
+    ('0101', 14.6349, -90.5069), ('0102', 14.5689, -90.4953), ('0103', 14.5461, -90.4114),
+    ('0104', 14.7617, -90.3767), ('0105', 14.6656, -90.3583), ('0106', 14.7086, -90.4997),
+    ('0107', 14.7875, -90.4522), ('0108', 14.6308, -90.6064), ('0109', 14.6853, -90.6428),
+    ('0110', 14.7189, -90.6442), ('0111', 14.7647, -90.5950), ('0112', 14.8186, -90.5136),
+    ('0113', 14.4650, -90.4408), ('0114', 14.4769, -90.6158), ('0115', 14.5269, -90.5875),
+    ('0116', 14.4819, -90.5339), ('0117', 14.5019, -90.5583), ('0201', 14.8536, -90.0694),
+    ('0301', 14.5586, -90.7295), ('0401', 14.6611, -90.8194), ('0501', 14.3050, -90.7850),
+    ('0601', 14.2789, -90.2983), ('0701', 14.7722, -91.1833), ('0801', 14.9108, -91.3611),
+    ('0901', 14.8347, -91.5180), ('1001', 14.5342, -91.5033), ('1101', 14.5361, -91.6775),
+    ('1201', 14.9653, -91.7958), ('1301', 15.3197, -91.4708), ('1401', 15.0306, -91.1489),
+    ('1501', 15.1031, -90.3167), ('1601', 15.4708, -90.3711), ('1701', 16.9297, -89.8922),
+    ('1801', 15.7278, -88.5944), ('1901', 14.9722, -89.5306), ('2001', 14.8000, -89.5458),
+    ('2101', 14.6339, -89.9892), ('2201', 14.2917, -89.8958)
+) AS c(code, lat, lng)
+WHERE m.code = c.code;
@@ -0,0 +1,2 @@
+-- Migration: 000013_property_revisions.down.sql
+DROP TABLE IF EXISTS property_revisions;
@@ -0,0 +1,29 @@
+-- Migration: 000013_property_revisions.up.sql
+-- Historial de revisiones: copia completa de la propiedad en cada cambio
+
+CREATE TABLE property_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    change_type VARCHAR(20) NOT NULL, -- create, update, status, coordinates, geocode, zone, revert
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL = proceso automático
+    reverted_from INT, -- Revisión restaurada (solo en revert)
+    snapshot JSONB NOT NULL, -- domain.Property serializado
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (property_id, revision)
+);
+
+-- Revisión inicial de las propiedades existentes
+INSERT INTO property_revisions (property_id, revision, change_type, snapshot, created_at)
+SELECT p.id, 1, 'create', jsonb_strip_nulls(jsonb_build_object(
+           'id', p.id, 'title', p.title, 'description', COALESCE(p.description, ''),
+           'price', p.price, 'currency', COALESCE(p.currency, 'USD'), 'address', p.address,
+           'city', COALESCE(p.city, ''), 'department_id', p.department_id,
+           'municipality_id', p.municipality_id, 'zone_id', p.zone_id,
+           'zone_boundary_id', p.zone_boundary_id, 'type', p.type,
+           'bedrooms', p.bedrooms, 'bathrooms', p.bathrooms, 'area_sqm', COALESCE(p.area_sqm, 0),
+           'lat', p.lat, 'lng', p.lng, 'geocode_confidence', p.geocode_confidence,
+           'geocode_source', p.geocode_source, 'main_image', COALESCE(p.main_image, ''),
+           'status', p.status, 'created_at', p.created_at, 'updated_at', p.updated_at)),
+       COALESCE(p.updated_at, CURRENT_TIMESTAMP)
+FROM properties p;
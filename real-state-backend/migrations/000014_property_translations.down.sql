@@ -0,0 +1,3 @@
+-- Migration: 000014_property_translations.down.sql
+DROP TABLE IF EXISTS property_translations;
+ALTER TABLE properties DROP COLUMN IF EXISTS source_language;
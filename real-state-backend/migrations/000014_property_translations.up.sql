@@ -0,0 +1,14 @@
+-- Migration: 000014_property_translations.up.sql
+-- Traducciones del título y la descripción por idioma
+
+ALTER TABLE properties ADD COLUMN source_language VARCHAR(5) NOT NULL DEFAULT 'es';
+
+CREATE TABLE property_translations (
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    language VARCHAR(5) NOT NULL, -- es, en
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (property_id, language)
+);
@@ -0,0 +1,3 @@
+-- Migration: 000015_property_amenities.down.sql
+DROP INDEX IF EXISTS idx_properties_amenities;
+ALTER TABLE properties DROP COLUMN IF EXISTS amenities;
@@ -0,0 +1,6 @@
+-- Migration: 000015_property_amenities.up.sql
+-- Amenidades de la propiedad (catálogo fijo en domain.PropertyAmenities)
+
+ALTER TABLE properties ADD COLUMN amenities TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_properties_amenities ON properties USING GIN (amenities);
@@ -0,0 +1,3 @@
+-- Migration: 000016_property_share_links.down.sql
+DELETE FROM permissions WHERE name = 'share_property';
+DROP TABLE IF EXISTS property_share_links;
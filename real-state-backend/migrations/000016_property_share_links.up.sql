@@ -0,0 +1,19 @@
+-- Migration: 000016_property_share_links.up.sql
+-- Enlaces públicos firmados para compartir una propiedad sin cuenta
+
+CREATE TABLE property_share_links (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    open_count INT NOT NULL DEFAULT 0,
+    last_opened_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_property_share_links_property ON property_share_links(property_id);
+
+-- Permiso para generar y revocar enlaces
+INSERT INTO permissions (name, resource, action) VALUES ('share_property', 'properties', 'share');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'share_property';
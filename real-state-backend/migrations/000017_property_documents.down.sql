@@ -0,0 +1,5 @@
+-- Migration: 000017_property_documents.down.sql
+DELETE FROM permissions WHERE name = 'view_property_documents';
+DROP TABLE IF EXISTS property_document_versions;
+DROP TABLE IF EXISTS property_documents;
+ALTER TABLE properties DROP COLUMN IF EXISTS agent_id;
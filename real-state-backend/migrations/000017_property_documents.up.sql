@@ -0,0 +1,36 @@
+-- Migration: 000017_property_documents.up.sql
+-- Bóveda de documentos por propiedad (escrituras, certificaciones, mandatos) con versiones
+
+-- Agente responsable de la propiedad (quien la registró)
+ALTER TABLE properties ADD COLUMN agent_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_properties_agent ON properties(agent_id);
+
+CREATE TABLE property_documents (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    document_type VARCHAR(30) NOT NULL, -- deed, cadastral_certificate, mandate, other
+    title VARCHAR(255) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_property_documents_property ON property_documents(property_id);
+
+CREATE TABLE property_document_versions (
+    id BIGSERIAL PRIMARY KEY,
+    document_id BIGINT NOT NULL REFERENCES property_documents(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    checksum_sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(500) NOT NULL, -- Ubicación en el almacenamiento (ports.DocumentStorage)
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (document_id, version)
+);
+
+-- Permiso para ver documentos de propiedades de otros agentes
+INSERT INTO permissions (name, resource, action) VALUES ('view_property_documents', 'property_documents', 'read');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'view_property_documents';
@@ -0,0 +1,8 @@
+-- Migration: 000018_property_registry.down.sql
+DELETE FROM permissions WHERE name = 'view_land_registry';
+DROP INDEX IF EXISTS idx_properties_iusi_active;
+DROP INDEX IF EXISTS idx_properties_registry_active;
+ALTER TABLE properties DROP COLUMN IF EXISTS registry_iusi;
+ALTER TABLE properties DROP COLUMN IF EXISTS registry_libro;
+ALTER TABLE properties DROP COLUMN IF EXISTS registry_folio;
+ALTER TABLE properties DROP COLUMN IF EXISTS registry_finca;
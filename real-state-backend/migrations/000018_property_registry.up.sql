@@ -0,0 +1,17 @@
+-- Migration: 000018_property_registry.up.sql
+-- Identificación en el Registro General de la Propiedad (finca/folio/libro) y matrícula IUSI
+
+ALTER TABLE properties ADD COLUMN registry_finca VARCHAR(10);
+ALTER TABLE properties ADD COLUMN registry_folio VARCHAR(10);
+ALTER TABLE properties ADD COLUMN registry_libro VARCHAR(10);
+ALTER TABLE properties ADD COLUMN registry_iusi VARCHAR(30); -- Matrícula fiscal / número catastral
+
+-- Dos propiedades activas (draft, published, reserved) no pueden compartir identidad registral
+CREATE UNIQUE INDEX idx_properties_registry_active ON properties(registry_finca, registry_folio, registry_libro)
+    WHERE registry_finca IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+CREATE UNIQUE INDEX idx_properties_iusi_active ON properties(registry_iusi)
+    WHERE registry_iusi IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+
+-- Permiso para ver y buscar datos registrales (usuarios internos)
+INSERT INTO permissions (name, resource, action) VALUES ('view_land_registry', 'properties', 'view_registry');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'view_land_registry';
@@ -0,0 +1,4 @@
+-- Migration: 000019_property_offers.down.sql
+DELETE FROM permissions WHERE name = 'manage_offers';
+DROP TABLE IF EXISTS property_offer_events;
+DROP TABLE IF EXISTS property_offers;
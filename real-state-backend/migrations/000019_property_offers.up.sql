@@ -0,0 +1,42 @@
+-- Migration: 000019_property_offers.up.sql
+-- Ofertas de compra/renta y su hilo de negociación (contraofertas, mensajes)
+
+CREATE TABLE property_offers (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    buyer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    conditions TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted'
+        CHECK (status IN ('submitted', 'countered', 'accepted', 'rejected', 'withdrawn', 'expired')),
+    awaiting_party VARCHAR(10) CHECK (awaiting_party IN ('buyer', 'seller')), -- NULL al cerrarse
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_property_offers_property ON property_offers(property_id);
+CREATE INDEX idx_property_offers_buyer ON property_offers(buyer_id);
+-- Vencimiento automático: solo se recorren las ofertas abiertas
+CREATE INDEX idx_property_offers_open_expiry ON property_offers(expires_at) WHERE status IN ('submitted', 'countered');
+
+CREATE TABLE property_offer_events (
+    id BIGSERIAL PRIMARY KEY,
+    offer_id BIGINT NOT NULL REFERENCES property_offers(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL = sistema (vencimiento)
+    party VARCHAR(10) NOT NULL CHECK (party IN ('buyer', 'seller', 'system')),
+    kind VARCHAR(20) NOT NULL, -- submitted, countered, accepted, rejected, withdrawn, expired, message
+    amount DECIMAL(15,2),
+    currency VARCHAR(3),
+    conditions TEXT,
+    expires_at TIMESTAMP,
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_property_offer_events_offer ON property_offer_events(offer_id, id);
+
+-- Permiso para negociar en nombre del vendedor (además del agente responsable)
+INSERT INTO permissions (name, resource, action) VALUES ('manage_offers', 'properties', 'manage_offers');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'manage_offers';
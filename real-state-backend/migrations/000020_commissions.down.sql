@@ -0,0 +1,5 @@
+-- Migration: 000020_commissions.down.sql
+DELETE FROM permissions WHERE name = 'manage_commissions';
+DROP TABLE IF EXISTS commission_ledger;
+DROP TABLE IF EXISTS commission_settlements;
+DROP TABLE IF EXISTS commission_rules;
@@ -0,0 +1,60 @@
+-- Migration: 000020_commissions.up.sql
+-- Reglas de comisión por tipo de operación y libro de comisiones por participante
+
+CREATE TABLE commission_rules (
+    operation_type VARCHAR(10) PRIMARY KEY CHECK (operation_type IN ('sale', 'rent')),
+    rate_percent DECIMAL(6,3) NOT NULL CHECK (rate_percent > 0 AND rate_percent <= 200), -- Sobre el precio (renta: 100 = un mes)
+    listing_split_percent DECIMAL(5,2) NOT NULL CHECK (listing_split_percent BETWEEN 0 AND 100), -- Del monto para agentes
+    agency_share_percent DECIMAL(5,2) NOT NULL CHECK (agency_share_percent BETWEEN 0 AND 100), -- De la comisión
+    iva_percent DECIMAL(5,2) NOT NULL CHECK (iva_percent BETWEEN 0 AND 100),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO commission_rules (operation_type, rate_percent, listing_split_percent, agency_share_percent, iva_percent) VALUES
+('sale', 5, 50, 40, 12),
+('rent', 100, 50, 40, 12);
+
+-- Una liquidación por cierre (la propiedad puede rentarse varias veces)
+CREATE TABLE commission_settlements (
+    id BIGSERIAL PRIMARY KEY,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    operation_type VARCHAR(10) NOT NULL,
+    closed_at TIMESTAMP NOT NULL,
+    price DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    offer_id BIGINT REFERENCES property_offers(id) ON DELETE SET NULL, -- Oferta aceptada que fijó el precio
+    listing_agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    selling_agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- Copia de la regla aplicada
+    rate_percent DECIMAL(6,3) NOT NULL,
+    listing_split_percent DECIMAL(5,2) NOT NULL,
+    agency_share_percent DECIMAL(5,2) NOT NULL,
+    iva_percent DECIMAL(5,2) NOT NULL,
+    commission_amount DECIMAL(15,2) NOT NULL,
+    iva_amount DECIMAL(15,2) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (property_id, closed_at)
+);
+
+CREATE TABLE commission_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    settlement_id BIGINT NOT NULL REFERENCES commission_settlements(id) ON DELETE CASCADE,
+    property_id INT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    participant VARCHAR(10) NOT NULL CHECK (participant IN ('agent', 'agency')),
+    agent_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL para la agencia
+    roles TEXT[] NOT NULL DEFAULT '{}', -- listing, selling
+    base_amount DECIMAL(15,2) NOT NULL,
+    iva_amount DECIMAL(15,2) NOT NULL,
+    total_amount DECIMAL(15,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    entry_date TIMESTAMP NOT NULL, -- Fecha de cierre de la operación
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_commission_ledger_agent_date ON commission_ledger(agent_id, entry_date);
+
+-- Permiso para configurar reglas, liquidar manualmente y ver estados de cuenta de otros agentes
+INSERT INTO permissions (name, resource, action) VALUES ('manage_commissions', 'commissions', 'manage');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'manage_commissions';
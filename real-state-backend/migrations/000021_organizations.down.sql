@@ -0,0 +1,31 @@
+-- Migration: 000021_organizations.down.sql
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p
+    WHERE r.name = 'admin' AND r.organization_id IS NULL AND p.platform AND p.name <> 'manage_organizations'
+    ON CONFLICT DO NOTHING;
+DELETE FROM roles WHERE name IN ('platform_admin', 'agency_admin') AND organization_id IS NULL;
+DELETE FROM permissions WHERE name IN ('manage_organizations', 'manage_agency_users');
+ALTER TABLE permissions DROP COLUMN IF EXISTS platform;
+
+DROP INDEX IF EXISTS idx_properties_registry_active;
+DROP INDEX IF EXISTS idx_properties_iusi_active;
+CREATE UNIQUE INDEX idx_properties_registry_active ON properties(registry_finca, registry_folio, registry_libro)
+    WHERE registry_finca IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+CREATE UNIQUE INDEX idx_properties_iusi_active ON properties(registry_iusi)
+    WHERE registry_iusi IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+
+DELETE FROM commission_rules WHERE organization_id <> '00000000-0000-0000-0000-000000000001';
+ALTER TABLE commission_rules DROP CONSTRAINT commission_rules_pkey;
+ALTER TABLE commission_rules DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE commission_rules ADD PRIMARY KEY (operation_type);
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS organization_id;
+
+DELETE FROM roles WHERE organization_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_roles_organization_name;
+DROP INDEX IF EXISTS idx_roles_global_name;
+ALTER TABLE roles DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE roles ADD CONSTRAINT roles_name_key UNIQUE (name);
+
+ALTER TABLE properties DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organizations;
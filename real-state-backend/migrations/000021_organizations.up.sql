@@ -0,0 +1,71 @@
+-- Migration: 000021_organizations.up.sql
+-- Multi-agencia: organizaciones que acotan usuarios, roles, propiedades y auditoría
+
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(120) NOT NULL,
+    slug VARCHAR(60) UNIQUE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE, -- Inactiva = sus usuarios no pueden iniciar sesión
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Agencia por defecto para los datos existentes
+INSERT INTO organizations (id, name, slug) VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default');
+
+ALTER TABLE users ADD COLUMN organization_id UUID REFERENCES organizations(id);
+UPDATE users SET organization_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+CREATE INDEX idx_users_organization ON users(organization_id);
+
+ALTER TABLE properties ADD COLUMN organization_id UUID REFERENCES organizations(id);
+UPDATE properties SET organization_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE properties ALTER COLUMN organization_id SET NOT NULL;
+CREATE INDEX idx_properties_organization ON properties(organization_id);
+
+-- Roles globales (organization_id NULL) o propios de una agencia
+ALTER TABLE roles ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE roles DROP CONSTRAINT roles_name_key;
+CREATE UNIQUE INDEX idx_roles_global_name ON roles(name) WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX idx_roles_organization_name ON roles(organization_id, name) WHERE organization_id IS NOT NULL;
+
+-- NULL para eventos sin usuario identificado (ej. login con usuario inexistente)
+ALTER TABLE audit_logs ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
+UPDATE audit_logs a SET organization_id = u.organization_id FROM users u WHERE u.id = a.user_id;
+CREATE INDEX idx_audit_logs_organization ON audit_logs(organization_id, timestamp);
+
+-- Reglas de comisión por agencia
+ALTER TABLE commission_rules ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE commission_rules SET organization_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE commission_rules ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE commission_rules DROP CONSTRAINT commission_rules_pkey;
+ALTER TABLE commission_rules ADD PRIMARY KEY (organization_id, operation_type);
+
+-- La identidad registral es única entre las propiedades activas de cada agencia
+DROP INDEX IF EXISTS idx_properties_registry_active;
+DROP INDEX IF EXISTS idx_properties_iusi_active;
+CREATE UNIQUE INDEX idx_properties_registry_active ON properties(organization_id, registry_finca, registry_folio, registry_libro)
+    WHERE registry_finca IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+CREATE UNIQUE INDEX idx_properties_iusi_active ON properties(organization_id, registry_iusi)
+    WHERE registry_iusi IS NOT NULL AND status IN ('draft', 'published', 'reserved');
+
+-- Permisos de plataforma: solo se otorgan con roles globales, nunca desde una agencia
+ALTER TABLE permissions ADD COLUMN platform BOOLEAN NOT NULL DEFAULT FALSE;
+INSERT INTO permissions (name, resource, action, platform) VALUES ('manage_organizations', 'organizations', 'manage', TRUE);
+INSERT INTO permissions (name, resource, action, platform) VALUES ('manage_security_config', 'security_config', 'manage', TRUE)
+    ON CONFLICT (name) DO UPDATE SET platform = TRUE;
+UPDATE permissions SET platform = TRUE WHERE name IN ('manage_zone_boundaries', 'manage_mortgage_products');
+INSERT INTO permissions (name, resource, action) VALUES ('manage_agency_users', 'users', 'manage');
+
+INSERT INTO roles (name, description) VALUES ('platform_admin', 'Administrador de la plataforma (agencias y catálogos globales)');
+INSERT INTO roles (name, description) VALUES ('agency_admin', 'Administrador de agencia (usuarios de su organización)');
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'platform_admin' AND p.platform;
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'agency_admin' AND p.name = 'manage_agency_users';
+
+-- El rol admin queda como administrador de agencia con todos los permisos operativos
+DELETE FROM role_permissions WHERE role_id = (SELECT id FROM roles WHERE name = 'admin' AND organization_id IS NULL)
+    AND permission_id IN (SELECT id FROM permissions WHERE platform);
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'manage_agency_users';
+INSERT INTO user_roles (user_id, role_id) SELECT ur.user_id, pr.id FROM user_roles ur
+    JOIN roles r ON r.id = ur.role_id AND r.name = 'admin'
+    CROSS JOIN roles pr WHERE pr.name = 'platform_admin';
@@ -0,0 +1,5 @@
+-- Migration: 000022_webhooks.down.sql
+DELETE FROM permissions WHERE name = 'manage_webhooks';
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,3 @@
+-- Migration: 000023_outbox.down.sql
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+DROP TABLE IF EXISTS outbox_events;
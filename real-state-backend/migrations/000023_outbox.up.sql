@@ -0,0 +1,25 @@
+-- Migration: 000023_outbox.up.sql
+-- Outbox transaccional: los eventos se confirman junto con el cambio que los origina y
+-- un despachador los entrega (al menos una vez) a los suscriptores del proceso
+
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(), -- Clave de idempotencia de los suscriptores
+    event_type VARCHAR(80) NOT NULL,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE, -- NULL fuera de una petición autenticada
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMP, -- NULL mientras algún suscriptor no lo haya procesado
+    created_at TIMESTAMP NOT NULL
+);
+
+-- El despachador solo recorre los eventos pendientes; la limpieza, los publicados
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_published ON outbox_events(published_at) WHERE published_at IS NOT NULL;
+
+-- Un evento reentregado por el outbox no duplica la entrega del webhook (los reenvíos manuales sí se permiten)
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(subscription_id, event_id) WHERE redelivery_of IS NULL;
@@ -0,0 +1,3 @@
+-- Migration: 000024_notifications.down.sql
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,34 @@
+-- Migration: 000024_notifications.up.sql
+-- Notificaciones por correo: cola persistente con reintentos y preferencias por usuario
+
+CREATE TABLE notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    language VARCHAR(5) NOT NULL DEFAULT 'es',
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    muted_kinds TEXT[] NOT NULL DEFAULT '{}', -- Tipos que el usuario no quiere recibir
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    -- Contenido ya renderizado: un cambio de plantilla no altera los correos en cola
+    subject VARCHAR(255) NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP, -- NULL al enviarse o fallar definitivamente
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP,
+    dedupe_key VARCHAR(120) UNIQUE, -- Ej. lead_alert:<event_id>; NULL = sin deduplicar
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, id DESC);
+-- El despachador solo recorre las pendientes
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+-- Migration: 000025_duplicate_candidates.down.sql
+DROP INDEX IF EXISTS idx_properties_type_title;
@@ -0,0 +1,5 @@
+-- Migration: 000025_duplicate_candidates.up.sql
+-- Índice para el bloque por título del reporte de duplicados.
+-- address_normalized se recalcula con cmd/backfill-addresses (misma normalización que la API)
+
+CREATE INDEX idx_properties_type_title ON properties(type, lower(title));
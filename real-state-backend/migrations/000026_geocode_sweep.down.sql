@@ -0,0 +1,3 @@
+-- Migration: 000026_geocode_sweep.down.sql
+DROP INDEX IF EXISTS idx_properties_geocode_pending;
+ALTER TABLE properties DROP COLUMN IF EXISTS geocode_attempted_at;
@@ -0,0 +1,6 @@
+-- Migration: 000026_geocode_sweep.up.sql
+-- Último intento de geocodificación, para reintentar las propiedades sin coordenadas
+
+ALTER TABLE properties ADD COLUMN geocode_attempted_at TIMESTAMP;
+
+CREATE INDEX idx_properties_geocode_pending ON properties(geocode_attempted_at) WHERE lat IS NULL;
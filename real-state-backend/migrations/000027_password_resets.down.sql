@@ -0,0 +1,2 @@
+-- Migration: 000027_password_resets.down.sql
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,13 @@
+-- Migration: 000027_password_resets.up.sql
+-- Recuperación de contraseña: enlaces de un solo uso enviados por correo
+
+CREATE TABLE password_resets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE, -- SHA-256 del token; el token solo viaja en el correo
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP, -- Usado o reemplazado por un enlace posterior
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_password_resets_pending ON password_resets(user_id) WHERE used_at IS NULL;
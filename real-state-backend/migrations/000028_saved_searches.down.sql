@@ -0,0 +1,2 @@
+-- Migration: 000028_saved_searches.down.sql
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,16 @@
+-- Migration: 000028_saved_searches.up.sql
+-- Búsquedas guardadas: avisan por correo de las propiedades nuevas que cumplen los criterios
+
+CREATE TABLE saved_searches (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    criteria JSONB NOT NULL DEFAULT '{}', -- domain.SavedSearchCriteria
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches(user_id, id);
+-- Al crearse una propiedad se buscan candidatas por agencia y tipo
+CREATE INDEX idx_saved_searches_candidates ON saved_searches(organization_id, (criteria->>'type'));
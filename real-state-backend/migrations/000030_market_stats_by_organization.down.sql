@@ -0,0 +1,86 @@
+-- Migration: 000030_market_stats_by_organization.down.sql
+DROP MATERIALIZED VIEW IF EXISTS market_stats_monthly;
+
+CREATE MATERIALIZED VIEW market_stats_monthly AS
+WITH normalized AS (
+    SELECT p.id, lower(btrim(COALESCE(p.city, ''))) AS city, p.type, p.status,
+           p.price, COALESCE(p.currency, 'USD') AS currency, p.area_sqm,
+           p.created_at, p.closed_at
+    FROM properties p
+    WHERE p.status <> 'draft' AND p.created_at IS NOT NULL
+),
+months AS (
+    SELECT generate_series(
+        date_trunc('month', (SELECT MIN(created_at) FROM normalized)),
+        date_trunc('month', CURRENT_TIMESTAMP),
+        INTERVAL '1 month') AS month
+),
+-- Inventario al cierre de cada mes, con el precio vigente según el historial.
+-- Sin tipo de cambio el precio en USD queda NULL y percentile_cont lo ignora.
+active AS (
+    SELECT m.month, n.city, n.type,
+           er.rate_to_usd IS NULL AS unconverted,
+           COALESCE(h.price, n.price) * er.rate_to_usd AS price_usd,
+           CASE WHEN n.area_sqm > 0
+                THEN COALESCE(h.price, n.price) * er.rate_to_usd / n.area_sqm
+           END AS price_per_sqm_usd
+    FROM months m
+    JOIN normalized n ON n.created_at < m.month + INTERVAL '1 month'
+                     AND (n.closed_at IS NULL OR n.closed_at >= m.month + INTERVAL '1 month')
+    LEFT JOIN LATERAL (
+        SELECT ph.price, ph.currency FROM property_price_history ph
+        WHERE ph.property_id = n.id AND ph.changed_at < m.month + INTERVAL '1 month'
+        ORDER BY ph.changed_at DESC LIMIT 1
+    ) h ON TRUE
+    LEFT JOIN exchange_rates er ON er.currency = COALESCE(h.currency, n.currency)
+),
+inventory AS (
+    SELECT month, city, type, COUNT(*) AS inventory_count,
+           COUNT(*) FILTER (WHERE unconverted) AS unconverted_count,
+           percentile_cont(0.5) WITHIN GROUP (ORDER BY price_usd) AS median_price_usd,
+           percentile_cont(0.5) WITHIN GROUP (ORDER BY price_per_sqm_usd) AS median_price_per_sqm_usd
+    FROM active
+    GROUP BY month, city, type
+),
+new_listings AS (
+    SELECT date_trunc('month', created_at) AS month, city, type, COUNT(*) AS new_listings
+    FROM normalized
+    GROUP BY 1, 2, 3
+),
+closed AS (
+    SELECT date_trunc('month', closed_at) AS month, city, type, COUNT(*) AS closed_count,
+           AVG(EXTRACT(EPOCH FROM closed_at - created_at) / 86400) AS avg_days_on_market
+    FROM normalized
+    WHERE status IN ('sold', 'rented') AND closed_at IS NOT NULL
+    GROUP BY 1, 2, 3
+),
+reductions AS (
+    SELECT date_trunc('month', ph.changed_at) AS month, n.city, n.type, COUNT(*) AS price_reductions
+    FROM property_price_history ph
+    JOIN normalized n ON n.id = ph.property_id
+    WHERE ph.previous_price IS NOT NULL AND ph.price < ph.previous_price
+    GROUP BY 1, 2, 3
+),
+keys AS (
+    SELECT month, city, type FROM inventory
+    UNION SELECT month, city, type FROM new_listings
+    UNION SELECT month, city, type FROM closed
+    UNION SELECT month, city, type FROM reductions
+)
+SELECT k.month::date AS month, k.city, k.type,
+       COALESCE(i.inventory_count, 0) AS inventory_count,
+       COALESCE(nl.new_listings, 0) AS new_listings,
+       COALESCE(c.closed_count, 0) AS closed_count,
+       i.median_price_usd,
+       i.median_price_per_sqm_usd,
+       c.avg_days_on_market,
+       COALESCE(rd.price_reductions, 0) AS price_reductions,
+       COALESCE(i.unconverted_count, 0) AS unconverted_count
+FROM keys k
+LEFT JOIN inventory i ON i.month = k.month AND i.city = k.city AND i.type = k.type
+LEFT JOIN new_listings nl ON nl.month = k.month AND nl.city = k.city AND nl.type = k.type
+LEFT JOIN closed c ON c.month = k.month AND c.city = k.city AND c.type = k.type
+LEFT JOIN reductions rd ON rd.month = k.month AND rd.city = k.city AND rd.type = k.type;
+
+-- Índice único requerido para REFRESH ... CONCURRENTLY
+CREATE UNIQUE INDEX uq_market_stats_monthly ON market_stats_monthly(month, city, type);
@@ -0,0 +1,3 @@
+-- Migration: 000031_password_reset_token_at_send.down.sql
+DELETE FROM password_resets WHERE token_hash IS NULL;
+ALTER TABLE password_resets ALTER COLUMN token_hash SET NOT NULL;
@@ -0,0 +1,9 @@
+-- Migration: 000031_password_reset_token_at_send.up.sql
+-- El token de recuperación se emite al enviar el correo: la cola de notificaciones
+-- solo guarda el id del enlace y no el token
+
+-- NULL = enlace registrado cuyo correo aún no se envía
+ALTER TABLE password_resets ALTER COLUMN token_hash DROP NOT NULL;
+
+-- Los correos ya renderizados contienen el token en claro
+UPDATE notifications SET text_body = '', html_body = '' WHERE kind = 'password_reset';
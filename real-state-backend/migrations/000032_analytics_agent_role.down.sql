@@ -0,0 +1,2 @@
+-- Migration: 000032_analytics_agent_role.down.sql
+DELETE FROM roles WHERE name = 'agent';
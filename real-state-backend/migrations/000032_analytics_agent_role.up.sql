@@ -0,0 +1,6 @@
+-- Migration: 000032_analytics_agent_role.up.sql
+-- Rol de agente: consulta el ranking de más vistas y la analítica de las propiedades
+
+INSERT INTO roles (name, description) VALUES ('agent', 'Agente inmobiliario') ON CONFLICT (name) DO NOTHING;
+INSERT INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'agent' AND p.name = 'view_property_analytics'
+    ON CONFLICT DO NOTHING;
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateWindow cuenta las peticiones de un cliente en la ventana actual
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitMiddleware limita las peticiones por IP con una ventana fija en memoria.
+// Pensado para las rutas públicas; con varias instancias el límite es por instancia.
+func RateLimitMiddleware(limit int, window time.Duration) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	clients := make(map[string]*rateWindow)
+	lastSweep := time.Now()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			now := time.Now()
+			mu.Lock()
+			// Limpieza periódica de clientes inactivos
+			if now.Sub(lastSweep) > window {
+				for key, c := range clients {
+					if now.Sub(c.start) > window {
+						delete(clients, key)
+					}
+				}
+				lastSweep = now
+			}
+			c, ok := clients[ip]
+			if !ok || now.Sub(c.start) > window {
+				c = &rateWindow{start: now}
+				clients[ip] = c
+			}
+			c.count++
+			count, reset := c.count, c.start.Add(window)
+			mu.Unlock()
+
+			remaining := limit - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			if count > limit {
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+				http.Error(w, `{"error": "Too many requests"}`, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
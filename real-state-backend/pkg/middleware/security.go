@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"real-state-backend/internal/core/domain"
 	"real-state-backend/internal/core/ports"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,7 +23,7 @@ func SecurityMiddleware(next http.Handler) http.Handler {
 		// 1. CORS (Cross-Origin Resource Sharing)
 		// Ajusta "*" al dominio específico de tu app en producción
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == "OPTIONS" {
@@ -127,6 +128,8 @@ func JWTMiddleware(authService ports.AuthService, secret string) func(http.Handl
 			slog.Info("JWT validated", "user_id", userID, "jti", jti)
 			ctx := context.WithValue(r.Context(), "user_id", userID)
 			ctx = context.WithValue(ctx, "jti", jti)
+			// Organización del usuario: los repositorios acotan las consultas a ella
+			ctx = context.WithValue(ctx, "organization_id", session.OrganizationID)
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -134,6 +137,15 @@ func JWTMiddleware(authService ports.AuthService, secret string) func(http.Handl
 	}
 }
 
+// PlatformScopeMiddleware da alcance de plataforma a las rutas públicas (sin JWT), que
+// muestran datos de todas las agencias
+func PlatformScopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "organization_id", domain.PlatformScope)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // RBACMiddleware verifica permisos específicos
 func RBACMiddleware(authService ports.AuthService, requiredPermission string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {